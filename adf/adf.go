@@ -0,0 +1,111 @@
+// Package adf converts markdown to the Atlassian Document Format, the JSON
+// document format used by the Jira Cloud and Confluence Cloud REST APIs
+package adf
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/evilmonkeyinc/markdownconverter"
+	"github.com/evilmonkeyinc/markdownconverter/links"
+	"github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/parser"
+)
+
+// Version is the version of the Atlassian Document Format
+const Version int = 1
+
+// New returns a new instance of Converter
+func New() *Converter {
+	return &Converter{}
+}
+
+// Converter is the Atlassian Document Format Converter implementation
+type Converter struct {
+	// Links is used to rewrite the destinations of links and images, leave nil
+	// to keep them as they are
+	Links *links.Rewriter
+}
+
+// Node is a node in an Atlassian Document Format document
+type Node struct {
+	Version int                    `json:"version,omitempty"`
+	Type    string                 `json:"type"`
+	Attrs   map[string]interface{} `json:"attrs,omitempty"`
+	Content []*Node                `json:"content,omitempty"`
+	Text    string                 `json:"text,omitempty"`
+	Marks   []*Mark                `json:"marks,omitempty"`
+}
+
+// Mark is formatting applied to a text node
+type Mark struct {
+	Type  string                 `json:"type"`
+	Attrs map[string]interface{} `json:"attrs,omitempty"`
+}
+
+// Format returns a unique name for the converter
+func (converter *Converter) Format() string {
+	return "adf"
+}
+
+// Parse will parse the standard markdown and return the converted data
+func (converter *Converter) Parse(markdwn []byte) ([]byte, error) {
+	document, err := converter.Document(markdwn)
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := &bytes.Buffer{}
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSpace(buffer.Bytes()), nil
+}
+
+// Document converts the markdown to an Atlassian Document Format document, so
+// it can be included in a request to the Jira or Confluence APIs
+func (converter *Converter) Document(markdwn []byte) (*Node, error) {
+	_, body := markdownconverter.FrontMatter(markdwn)
+	document := markdown.Parse(markdown.NormalizeNewlines(body), parser.NewWithExtensions(parser.CommonExtensions|parser.OrderedListStart))
+	markdownconverter.Admonitions(document)
+	if converter.Links != nil {
+		if err := converter.Links.Rewrite(document); err != nil {
+			return nil, err
+		}
+	}
+
+	renderer := &renderer{}
+	return &Node{
+		Version: Version,
+		Type:    "doc",
+		Content: renderer.blocks(document.GetChildren()),
+	}, nil
+}
+
+// MarshalJSON returns the JSON encoding of the node, including the content of
+// an empty document which is required by the schema. HTML characters are not
+// escaped, leaving that to the encoder of the whole document.
+func (node *Node) MarshalJSON() ([]byte, error) {
+	type plain Node
+	var value interface{} = (*plain)(node)
+	if node.Type == "doc" && len(node.Content) == 0 {
+		value = &struct {
+			*plain
+			Content []*Node `json:"content"`
+		}{
+			plain:   (*plain)(node),
+			Content: []*Node{},
+		}
+	}
+
+	buffer := &bytes.Buffer{}
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSpace(buffer.Bytes()), nil
+}
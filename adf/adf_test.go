@@ -0,0 +1,269 @@
+package adf
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/evilmonkeyinc/markdownconverter/links"
+	"github.com/stretchr/testify/assert"
+)
+
+// documentContent returns the JSON content of the document
+func documentContent(t *testing.T, output []byte) string {
+	document := struct {
+		Content json.RawMessage `json:"content"`
+	}{}
+	assert.Nil(t, json.Unmarshal(output, &document))
+	return string(document.Content)
+}
+
+func Test_Converter_Format(t *testing.T) {
+	actual := New().Format()
+	assert.Equal(t, "adf", actual)
+}
+
+func Test_Converter_Parse(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "empty",
+			input:    "",
+			expected: `[]`,
+		},
+		{
+			name:     "heading",
+			input:    "### Heading 3",
+			expected: `[{"type":"heading","attrs":{"level":3},"content":[{"type":"text","text":"Heading 3"}]}]`,
+		},
+		{
+			name:  "marks",
+			input: "**bold _both_** ~~strike~~ <u>underline</u> `code` <tag> & more",
+			expected: `[{"type":"paragraph","content":[
+				{"type":"text","text":"bold ","marks":[{"type":"strong"}]},
+				{"type":"text","text":"both","marks":[{"type":"strong"},{"type":"em"}]},
+				{"type":"text","text":" "},
+				{"type":"text","text":"strike","marks":[{"type":"strike"}]},
+				{"type":"text","text":" "},
+				{"type":"text","text":"underline","marks":[{"type":"underline"}]},
+				{"type":"text","text":" "},
+				{"type":"text","text":"code","marks":[{"type":"code"}]},
+				{"type":"text","text":"  & more"}
+			]}]`,
+		},
+		{
+			name:  "links",
+			input: "[**docs**](https://example.com \"Docs\") and [`code`](https://example.com/code)",
+			expected: `[{"type":"paragraph","content":[
+				{"type":"text","text":"docs","marks":[{"type":"link","attrs":{"href":"https://example.com","title":"Docs"}},{"type":"strong"}]},
+				{"type":"text","text":" and "},
+				{"type":"text","text":"code","marks":[{"type":"code"},{"type":"link","attrs":{"href":"https://example.com/code"}}]}
+			]}]`,
+		},
+		{
+			name:     "hard_break",
+			input:    "first  \nsecond\nthird",
+			expected: `[{"type":"paragraph","content":[{"type":"text","text":"first"},{"type":"hardBreak"},{"type":"text","text":"second third"}]}]`,
+		},
+		{
+			name:     "image",
+			input:    "![The logo](https://example.com/logo.png)",
+			expected: `[{"type":"mediaSingle","attrs":{"layout":"center"},"content":[{"type":"media","attrs":{"type":"external","url":"https://example.com/logo.png","alt":"The logo"}}]}]`,
+		},
+		{
+			name:     "inline_image",
+			input:    "see ![The logo](https://example.com/logo.png)",
+			expected: `[{"type":"paragraph","content":[{"type":"text","text":"see "},{"type":"text","text":"The logo","marks":[{"type":"link","attrs":{"href":"https://example.com/logo.png"}}]}]}]`,
+		},
+		{
+			name:  "lists",
+			input: "* one\n* two\n  1. nested",
+			expected: `[{"type":"bulletList","content":[
+				{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"one"}]}]},
+				{"type":"listItem","content":[
+					{"type":"paragraph","content":[{"type":"text","text":"two"}]},
+					{"type":"orderedList","attrs":{"order":1},"content":[{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"nested"}]}]}]}
+				]}
+			]}]`,
+		},
+		{
+			name:     "code",
+			input:    "```Go\nfunc main() {}\n```\n\n```\n```",
+			expected: `[{"type":"codeBlock","attrs":{"language":"go"},"content":[{"type":"text","text":"func main() {}"}]},{"type":"codeBlock"}]`,
+		},
+		{
+			name:     "quote",
+			input:    "> # Quoted heading\n>\n> text",
+			expected: `[{"type":"blockquote","content":[{"type":"paragraph","content":[{"type":"text","text":"Quoted heading","marks":[{"type":"strong"}]}]},{"type":"paragraph","content":[{"type":"text","text":"text"}]}]}]`,
+		},
+		{
+			name:     "admonition",
+			input:    "> [!CAUTION]\n> Be careful",
+			expected: `[{"type":"panel","attrs":{"panelType":"error"},"content":[{"type":"paragraph","content":[{"type":"text","text":"Be careful"}]}]}]`,
+		},
+		{
+			name:     "empty_quote",
+			input:    ">",
+			expected: `[{"type":"blockquote","content":[{"type":"paragraph"}]}]`,
+		},
+		{
+			name:     "empty_admonition",
+			input:    "> [!NOTE]",
+			expected: `[{"type":"panel","attrs":{"panelType":"info"},"content":[{"type":"paragraph"}]}]`,
+		},
+		{
+			name:     "ordered_list_start",
+			input:    "3. third",
+			expected: `[{"type":"orderedList","attrs":{"order":3},"content":[{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"third"}]}]}]}]`,
+		},
+		{
+			name:  "table",
+			input: "| Name | Value |\n| --- | --- |\n| one | |",
+			expected: `[{"type":"table","content":[
+				{"type":"tableRow","content":[
+					{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"Name"}]}]},
+					{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"Value"}]}]}
+				]},
+				{"type":"tableRow","content":[
+					{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"one"}]}]},
+					{"type":"tableCell","content":[{"type":"paragraph"}]}
+				]}
+			]}]`,
+		},
+		{
+			name:     "rule",
+			input:    "---",
+			expected: `[{"type":"rule"}]`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := New().Parse([]byte(test.input))
+			assert.Nil(t, err)
+			assert.JSONEq(t, test.expected, documentContent(t, actual))
+			assert.Nil(t, validate(actual))
+		})
+	}
+}
+
+func Test_Converter_Parse_Schema(t *testing.T) {
+	document := `# Release Notes
+
+Some **bold**, _italic_, and ` + "`code`" + ` with a [link](https://example.com).
+
+> [!NOTE]
+> ## Upgrading
+> * step one
+> * step two
+>
+> ---
+
+* item
+  > quoted in a list
+  ` + "```" + `
+  code in a list
+  ` + "```" + `
+
+1. ![image](https://example.com/image.png)
+
+| Header | Other |
+| --- | --- |
+| **cell** | $x^2$ |
+
+$$
+y = mx + c
+$$
+`
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "document", input: document},
+		{name: "empty_quote", input: ">"},
+		{name: "empty_admonition", input: "> [!NOTE]"},
+		{name: "quoted_heading", input: "> # **bold** text"},
+		{name: "list_heading", input: "* # _a **b**_"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := New().Parse([]byte(test.input))
+			assert.Nil(t, err)
+			assert.Nil(t, validate(actual))
+		})
+	}
+}
+
+func Test_Converter_Document(t *testing.T) {
+	document, err := New().Document([]byte("text"))
+	assert.Nil(t, err)
+	assert.Equal(t, &Node{
+		Version: Version,
+		Type:    "doc",
+		Content: []*Node{{Type: "paragraph", Content: []*Node{{Type: "text", Text: "text"}}}},
+	}, document)
+}
+
+func Test_Converter_Parse_Links(t *testing.T) {
+	converter := New()
+	converter.Links = &links.Rewriter{
+		BaseURL:        "https://example.com/docs/",
+		MarkdownToHTML: true,
+	}
+
+	actual, err := converter.Parse([]byte("[setup](./setup.md)"))
+	assert.Nil(t, err)
+	assert.JSONEq(t, `[{"type":"paragraph","content":[{"type":"text","text":"setup","marks":[{"type":"link","attrs":{"href":"https://example.com/docs/setup.html"}}]}]}]`, documentContent(t, actual))
+
+	converter.Links.BaseURL = "invalid"
+	_, err = converter.Parse([]byte("[setup](./setup.md)"))
+	assert.ErrorIs(t, err, links.ErrInvalidBaseURL)
+}
+
+func Test_validate(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "version",
+			input: `{"type":"doc","content":[]}`,
+		},
+		{
+			name:  "empty_text",
+			input: `{"version":1,"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":""}]}]}`,
+		},
+		{
+			name:  "inline_in_doc",
+			input: `{"version":1,"type":"doc","content":[{"type":"text","text":"text"}]}`,
+		},
+		{
+			name:  "code_with_strong",
+			input: `{"version":1,"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"x","marks":[{"type":"code"},{"type":"strong"}]}]}]}`,
+		},
+		{
+			name:  "duplicate_mark",
+			input: `{"version":1,"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"x","marks":[{"type":"strong"},{"type":"strong"}]}]}]}`,
+		},
+		{
+			name:  "nested_blockquote",
+			input: `{"version":1,"type":"doc","content":[{"type":"blockquote","content":[{"type":"blockquote","content":[{"type":"paragraph"}]}]}]}`,
+		},
+		{
+			name:  "heading_level",
+			input: `{"version":1,"type":"doc","content":[{"type":"heading","attrs":{"level":7}}]}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.NotNil(t, validate([]byte(test.input)))
+		})
+	}
+}
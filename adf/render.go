@@ -0,0 +1,351 @@
+package adf
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/evilmonkeyinc/markdownconverter"
+	"github.com/gomarkdown/markdown/ast"
+)
+
+// panelTypes are the panel types of each admonition type
+var panelTypes map[string]string = map[string]string{
+	"caution":   "error",
+	"important": "note",
+	"note":      "info",
+	"tip":       "success",
+	"warning":   "warning",
+}
+
+// allowedContent are the block nodes allowed in the content of other block
+// nodes, any other block is replaced with its own content
+var allowedContent map[string]map[string]bool = map[string]map[string]bool{
+	"blockquote": {"bulletList": true, "codeBlock": true, "mediaSingle": true, "orderedList": true, "paragraph": true},
+	"listItem":   {"bulletList": true, "codeBlock": true, "mediaSingle": true, "orderedList": true, "paragraph": true},
+	"panel":      {"bulletList": true, "codeBlock": true, "heading": true, "mediaSingle": true, "orderedList": true, "paragraph": true, "rule": true},
+}
+
+type renderer struct {
+	// underline is true between underline tags
+	underline bool
+}
+
+// blocks returns the nodes for each block node
+func (renderer *renderer) blocks(nodes []ast.Node) []*Node {
+	content := make([]*Node, 0, len(nodes))
+	for _, node := range nodes {
+		content = append(content, renderer.block(node)...)
+	}
+	if len(content) == 0 {
+		return nil
+	}
+	return content
+}
+
+// children returns the nodes for the block nodes allowed in the parent
+func (renderer *renderer) children(parent string, nodes []ast.Node) []*Node {
+	return allowed(parent, renderer.blocks(nodes))
+}
+
+// allowed returns the nodes the parent allows, replacing the others with
+// their content. Headings are replaced with a paragraph of bold text.
+func allowed(parent string, nodes []*Node) []*Node {
+	content := make([]*Node, 0, len(nodes))
+	for _, node := range nodes {
+		switch {
+		case allowedContent[parent][node.Type]:
+			content = append(content, node)
+		case node.Type == "heading":
+			for _, text := range node.Content {
+				if text.Type == "text" && !hasMark(text, "code") && !hasMark(text, "strong") {
+					text.Marks = withMark(text.Marks, &Mark{Type: "strong"})
+				}
+			}
+			content = append(content, &Node{Type: "paragraph", Content: node.Content})
+		default:
+			content = append(content, allowed(parent, node.Content)...)
+		}
+	}
+	return content
+}
+
+func (renderer *renderer) block(node ast.Node) []*Node {
+	switch node := node.(type) {
+	case *ast.Heading:
+		return []*Node{{
+			Type:    "heading",
+			Attrs:   map[string]interface{}{"level": node.Level},
+			Content: renderer.inline(node, nil),
+		}}
+	case *ast.Paragraph:
+		if image, ok := markdownconverter.OnlyImage(node); ok {
+			return []*Node{{
+				Type:  "mediaSingle",
+				Attrs: map[string]interface{}{"layout": "center"},
+				Content: []*Node{{
+					Type: "media",
+					Attrs: map[string]interface{}{
+						"type": "external",
+						"url":  string(image.Destination),
+						"alt":  markdownconverter.PlainText(image),
+					},
+				}},
+			}}
+		}
+		content := renderer.inline(node, nil)
+		if len(content) == 0 {
+			return nil
+		}
+		return []*Node{{Type: "paragraph", Content: content}}
+	case *ast.BlockQuote:
+		return []*Node{{Type: "blockquote", Content: required(renderer.children("blockquote", node.Children))}}
+	case *markdownconverter.Admonition:
+		return []*Node{{
+			Type:    "panel",
+			Attrs:   map[string]interface{}{"panelType": panelTypes[node.Type]},
+			Content: required(renderer.children("panel", node.Children)),
+		}}
+	case *ast.CodeBlock:
+		language := ""
+		if fields := strings.Fields(string(node.Info)); len(fields) > 0 {
+			language = strings.ToLower(fields[0])
+		}
+		return []*Node{codeBlock(language, strings.TrimRight(string(node.Literal), "\n"))}
+	case *ast.MathBlock:
+		return []*Node{codeBlock("", strings.TrimSpace(string(node.Literal)))}
+	case *ast.List:
+		return []*Node{renderer.list(node)}
+	case *ast.HorizontalRule:
+		return []*Node{{Type: "rule"}}
+	case *ast.Table:
+		return []*Node{renderer.table(node)}
+	case *ast.HTMLBlock:
+		return nil
+	default:
+		if container := node.AsContainer(); container != nil {
+			return renderer.blocks(container.Children)
+		}
+		if text := strings.TrimSpace(string(node.AsLeaf().Literal)); text != "" {
+			return []*Node{{Type: "paragraph", Content: []*Node{{Type: "text", Text: text}}}}
+		}
+		return nil
+	}
+}
+
+// required returns the content, or an empty paragraph if there is none, for
+// the nodes that must have content such as an empty quote
+func required(content []*Node) []*Node {
+	if len(content) == 0 {
+		return []*Node{{Type: "paragraph"}}
+	}
+	return content
+}
+
+func (renderer *renderer) list(node *ast.List) *Node {
+	list := &Node{Type: "bulletList"}
+	if node.ListFlags&ast.ListTypeOrdered != 0 {
+		list.Type = "orderedList"
+		order := node.Start
+		if order == 0 {
+			order = 1
+		}
+		list.Attrs = map[string]interface{}{"order": order}
+	}
+	for _, item := range node.Children {
+		content := renderer.children("listItem", item.GetChildren())
+		if len(content) == 0 || content[0].Type != "paragraph" {
+			// the first node of a list item must be a paragraph
+			content = append([]*Node{{Type: "paragraph"}}, content...)
+		}
+		list.Content = append(list.Content, &Node{Type: "listItem", Content: content})
+	}
+	return list
+}
+
+// table returns the table, with the cells of the header row as table headers
+func (renderer *renderer) table(node *ast.Table) *Node {
+	table := &Node{Type: "table"}
+	ast.WalkFunc(node, func(child ast.Node, entering bool) ast.WalkStatus {
+		row, ok := child.(*ast.TableRow)
+		if !ok || !entering {
+			return ast.GoToNext
+		}
+		cellType := "tableCell"
+		if _, header := row.Parent.(*ast.TableHeader); header {
+			cellType = "tableHeader"
+		}
+		tableRow := &Node{Type: "tableRow"}
+		for _, cell := range row.Children {
+			paragraph := &Node{Type: "paragraph", Content: renderer.inline(cell, nil)}
+			tableRow.Content = append(tableRow.Content, &Node{Type: cellType, Content: []*Node{paragraph}})
+		}
+		table.Content = append(table.Content, tableRow)
+		return ast.SkipChildren
+	})
+	return table
+}
+
+// inline returns the text nodes for the inline children of the node, with
+// the marks of its parents
+func (renderer *renderer) inline(node ast.Node, marks []*Mark) []*Node {
+	content := make([]*Node, 0)
+	for _, child := range node.GetChildren() {
+		for _, text := range renderer.inlineNode(child, marks) {
+			content = appendText(content, text)
+		}
+	}
+	return trimText(content)
+}
+
+func (renderer *renderer) inlineNode(node ast.Node, marks []*Mark) []*Node {
+	switch node := node.(type) {
+	case *ast.Text:
+		return []*Node{renderer.text(strings.ReplaceAll(string(node.Literal), "\n", " "), marks)}
+	case *ast.Code:
+		return []*Node{renderer.code(string(node.Literal), marks)}
+	case *ast.Math:
+		return []*Node{renderer.code(strings.TrimSpace(string(node.Literal)), marks)}
+	case *ast.Emph:
+		return renderer.inline(node, withMark(marks, &Mark{Type: "em"}))
+	case *ast.Strong:
+		return renderer.inline(node, withMark(marks, &Mark{Type: "strong"}))
+	case *ast.Del:
+		return renderer.inline(node, withMark(marks, &Mark{Type: "strike"}))
+	case *ast.Subscript:
+		return []*Node{renderer.text(string(node.Literal), withMark(marks, subsup("sub")))}
+	case *ast.Superscript:
+		return []*Node{renderer.text(string(node.Literal), withMark(marks, subsup("sup")))}
+	case *ast.Hardbreak:
+		return []*Node{{Type: "hardBreak"}}
+	case *ast.Link:
+		link := linkMark(node.Destination, node.Title)
+		content := renderer.inline(node, withMark(marks, link))
+		if len(content) == 0 {
+			return []*Node{renderer.text(string(node.Destination), withMark(marks, link))}
+		}
+		return content
+	case *ast.Image:
+		text := markdownconverter.PlainText(node)
+		if text == "" {
+			text = string(node.Destination)
+		}
+		return []*Node{renderer.text(text, withMark(marks, linkMark(node.Destination, node.Title)))}
+	case *ast.HTMLSpan:
+		if opening, ok := markdownconverter.UnderlineTag(node); ok {
+			renderer.underline = opening
+		}
+		return nil
+	default:
+		if node.AsContainer() != nil {
+			return renderer.inline(node, marks)
+		}
+		return []*Node{renderer.text(string(node.AsLeaf().Literal), marks)}
+	}
+}
+
+// text returns a text node with the marks, and an underline if inside
+// underline tags
+func (renderer *renderer) text(text string, marks []*Mark) *Node {
+	if renderer.underline {
+		marks = withMark(marks, &Mark{Type: "underline"})
+	}
+	return &Node{Type: "text", Text: text, Marks: marks}
+}
+
+// code returns a text node with the code mark, which can only be combined
+// with a link mark
+func (renderer *renderer) code(text string, marks []*Mark) *Node {
+	codeMarks := []*Mark{{Type: "code"}}
+	for _, mark := range marks {
+		if mark.Type == "link" {
+			codeMarks = append(codeMarks, mark)
+		}
+	}
+	return &Node{Type: "text", Text: text, Marks: codeMarks}
+}
+
+// appendText appends the node to the content, combining it with the last node
+// if both are text with the same marks. Empty text nodes are not allowed so
+// are dropped.
+func appendText(content []*Node, node *Node) []*Node {
+	if node.Type == "text" && node.Text == "" {
+		return content
+	}
+	if len(content) > 0 {
+		last := content[len(content)-1]
+		if last.Type == "text" && node.Type == "text" && reflect.DeepEqual(last.Marks, node.Marks) {
+			last.Text += node.Text
+			return content
+		}
+	}
+	return append(content, node)
+}
+
+// trimText removes the whitespace from the start and end of the content
+func trimText(content []*Node) []*Node {
+	if len(content) > 0 && content[0].Type == "text" {
+		content[0].Text = strings.TrimLeft(content[0].Text, " \t\n")
+		if content[0].Text == "" {
+			content = content[1:]
+		}
+	}
+	if last := len(content) - 1; last >= 0 && content[last].Type == "text" {
+		content[last].Text = strings.TrimRight(content[last].Text, " \t\n")
+		if content[last].Text == "" {
+			content = content[:last]
+		}
+	}
+	if len(content) == 0 {
+		return nil
+	}
+	return content
+}
+
+// hasMark returns true if the node has a mark of the type
+func hasMark(node *Node, markType string) bool {
+	for _, mark := range node.Marks {
+		if mark.Type == markType {
+			return true
+		}
+	}
+	return false
+}
+
+// withMark returns a copy of the marks with the mark added, unless there is
+// already a mark of its type as marks cannot be repeated
+func withMark(marks []*Mark, mark *Mark) []*Mark {
+	combined := make([]*Mark, 0, len(marks)+1)
+	combined = append(combined, marks...)
+	for _, existing := range marks {
+		if existing.Type == mark.Type {
+			return combined
+		}
+	}
+	return append(combined, mark)
+}
+
+// linkMark returns a link mark to the destination, with the title if it is
+// not empty
+func linkMark(destination, title []byte) *Mark {
+	attrs := map[string]interface{}{"href": string(destination)}
+	if len(title) > 0 {
+		attrs["title"] = string(title)
+	}
+	return &Mark{Type: "link", Attrs: attrs}
+}
+
+func subsup(kind string) *Mark {
+	return &Mark{Type: "subsup", Attrs: map[string]interface{}{"type": kind}}
+}
+
+// codeBlock returns a code block, with the language if it is not empty
+func codeBlock(language, code string) *Node {
+	node := &Node{Type: "codeBlock"}
+	if language != "" {
+		node.Attrs = map[string]interface{}{"language": language}
+	}
+	if code != "" {
+		node.Content = []*Node{{Type: "text", Text: code}}
+	}
+	return node
+}
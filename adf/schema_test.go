@@ -0,0 +1,246 @@
+package adf
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// schemaNode is the part of the Atlassian Document Format schema that
+// applies to a node type
+type schemaNode struct {
+	// content are the node types allowed in the content, nil if the node has
+	// no content
+	content map[string]bool
+	// first are the node types allowed as the first node of the content, if
+	// different from the rest
+	first map[string]bool
+	// required is true if the content must contain at least one node
+	required bool
+	// attrs validates the attributes of the node
+	attrs func(attrs map[string]interface{}) error
+}
+
+var (
+	schemaBlocks map[string]bool = map[string]bool{
+		"blockquote": true, "bulletList": true, "codeBlock": true, "heading": true, "mediaSingle": true,
+		"orderedList": true, "panel": true, "paragraph": true, "rule": true, "table": true,
+	}
+	schemaInline    map[string]bool = map[string]bool{"hardBreak": true, "text": true}
+	schemaListItems map[string]bool = map[string]bool{"bulletList": true, "codeBlock": true, "mediaSingle": true, "orderedList": true, "paragraph": true}
+	schemaCells     map[string]bool = map[string]bool{
+		"blockquote": true, "bulletList": true, "codeBlock": true, "heading": true, "mediaSingle": true,
+		"orderedList": true, "panel": true, "paragraph": true, "rule": true,
+	}
+	schemaMarks map[string]func(attrs map[string]interface{}) error = map[string]func(attrs map[string]interface{}) error{
+		"code":      noAttrs,
+		"em":        noAttrs,
+		"link":      requireString("href"),
+		"strike":    noAttrs,
+		"strong":    noAttrs,
+		"subsup":    requireOneOf("type", "sub", "sup"),
+		"underline": noAttrs,
+	}
+	schemaNodes map[string]schemaNode = map[string]schemaNode{
+		"blockquote": {
+			content:  map[string]bool{"bulletList": true, "codeBlock": true, "mediaSingle": true, "orderedList": true, "paragraph": true},
+			required: true,
+			attrs:    noAttrs,
+		},
+		"bulletList":  {content: map[string]bool{"listItem": true}, required: true, attrs: noAttrs},
+		"codeBlock":   {content: map[string]bool{"text": true}, attrs: optionalString("language")},
+		"hardBreak":   {attrs: noAttrs},
+		"heading":     {content: schemaInline, attrs: requireLevel},
+		"listItem":    {content: schemaListItems, first: map[string]bool{"codeBlock": true, "mediaSingle": true, "paragraph": true}, required: true, attrs: noAttrs},
+		"media":       {attrs: requireExternalMedia},
+		"mediaSingle": {content: map[string]bool{"media": true}, required: true, attrs: requireOneOf("layout", "wrap-left", "center", "wrap-right", "wide", "full-width", "align-start", "align-end")},
+		"orderedList": {content: map[string]bool{"listItem": true}, required: true, attrs: optionalOrder},
+		"panel": {
+			content:  map[string]bool{"bulletList": true, "codeBlock": true, "heading": true, "mediaSingle": true, "orderedList": true, "paragraph": true, "rule": true},
+			required: true,
+			attrs:    requireOneOf("panelType", "info", "note", "tip", "warning", "error", "success"),
+		},
+		"paragraph":   {content: schemaInline, attrs: noAttrs},
+		"rule":        {attrs: noAttrs},
+		"table":       {content: map[string]bool{"tableRow": true}, required: true, attrs: noAttrs},
+		"tableCell":   {content: schemaCells, required: true, attrs: noAttrs},
+		"tableHeader": {content: schemaCells, required: true, attrs: noAttrs},
+		"tableRow":    {content: map[string]bool{"tableCell": true, "tableHeader": true}, required: true, attrs: noAttrs},
+		"text":        {attrs: noAttrs},
+	}
+)
+
+// validate returns an error if the JSON is not a valid Atlassian Document
+// Format document, checking the node types, content, attributes, and marks
+// defined by the schema at https://unpkg.com/@atlaskit/adf-schema/dist/json-schema/v1/full.json
+func validate(data []byte) error {
+	document := map[string]interface{}{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return err
+	}
+	if document["version"] != float64(1) {
+		return fmt.Errorf("doc: version must be 1")
+	}
+	if document["type"] != "doc" {
+		return fmt.Errorf("doc: type must be doc")
+	}
+	content, ok := document["content"].([]interface{})
+	if !ok {
+		return fmt.Errorf("doc: content is required")
+	}
+	for index, child := range content {
+		if err := validateNode(child, schemaBlocks, fmt.Sprintf("doc.content[%d]", index)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateNode(value interface{}, allowed map[string]bool, path string) error {
+	node, ok := value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s: must be an object", path)
+	}
+	nodeType, _ := node["type"].(string)
+	schema, known := schemaNodes[nodeType]
+	if !known || !allowed[nodeType] {
+		return fmt.Errorf("%s: %s is not allowed", path, nodeType)
+	}
+	path += "(" + nodeType + ")"
+
+	for key := range node {
+		switch key {
+		case "type", "attrs", "content":
+		case "text", "marks":
+			if nodeType != "text" {
+				return fmt.Errorf("%s: %s is not allowed", path, key)
+			}
+		default:
+			return fmt.Errorf("%s: unknown property %s", path, key)
+		}
+	}
+
+	attrs, _ := node["attrs"].(map[string]interface{})
+	if err := schema.attrs(attrs); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	if nodeType == "text" {
+		return validateText(node, path)
+	}
+
+	content, hasContent := node["content"].([]interface{})
+	if hasContent && schema.content == nil {
+		return fmt.Errorf("%s: content is not allowed", path)
+	}
+	if schema.required && len(content) == 0 {
+		return fmt.Errorf("%s: content is required", path)
+	}
+	for index, child := range content {
+		allowedChildren := schema.content
+		if index == 0 && schema.first != nil {
+			allowedChildren = schema.first
+		}
+		if err := validateNode(child, allowedChildren, fmt.Sprintf("%s.content[%d]", path, index)); err != nil {
+			return err
+		}
+		if nodeType == "codeBlock" {
+			if _, marked := child.(map[string]interface{})["marks"]; marked {
+				return fmt.Errorf("%s: code block text cannot have marks", path)
+			}
+		}
+	}
+	return nil
+}
+
+func validateText(node map[string]interface{}, path string) error {
+	if text, _ := node["text"].(string); text == "" {
+		return fmt.Errorf("%s: text must not be empty", path)
+	}
+	marks, _ := node["marks"].([]interface{})
+	seen := map[string]bool{}
+	for _, value := range marks {
+		mark, _ := value.(map[string]interface{})
+		markType, _ := mark["type"].(string)
+		validateAttrs, ok := schemaMarks[markType]
+		if !ok {
+			return fmt.Errorf("%s: unknown mark %s", path, markType)
+		}
+		if seen[markType] {
+			return fmt.Errorf("%s: duplicate mark %s", path, markType)
+		}
+		seen[markType] = true
+		attrs, _ := mark["attrs"].(map[string]interface{})
+		if err := validateAttrs(attrs); err != nil {
+			return fmt.Errorf("%s: mark %s: %w", path, markType, err)
+		}
+	}
+	if seen["code"] {
+		for markType := range seen {
+			if markType != "code" && markType != "link" {
+				return fmt.Errorf("%s: code mark cannot be combined with %s", path, markType)
+			}
+		}
+	}
+	return nil
+}
+
+func noAttrs(attrs map[string]interface{}) error {
+	if len(attrs) > 0 {
+		return fmt.Errorf("attrs are not allowed")
+	}
+	return nil
+}
+
+func requireString(name string) func(attrs map[string]interface{}) error {
+	return func(attrs map[string]interface{}) error {
+		if value, ok := attrs[name].(string); !ok || value == "" {
+			return fmt.Errorf("attribute %s is required", name)
+		}
+		return nil
+	}
+}
+
+func optionalString(name string) func(attrs map[string]interface{}) error {
+	return func(attrs map[string]interface{}) error {
+		for key, value := range attrs {
+			if _, ok := value.(string); key != name || !ok {
+				return fmt.Errorf("attribute %s is not allowed", key)
+			}
+		}
+		return nil
+	}
+}
+
+func requireOneOf(name string, values ...string) func(attrs map[string]interface{}) error {
+	return func(attrs map[string]interface{}) error {
+		for _, value := range values {
+			if attrs[name] == value {
+				return nil
+			}
+		}
+		return fmt.Errorf("attribute %s must be one of %v", name, values)
+	}
+}
+
+func requireLevel(attrs map[string]interface{}) error {
+	if level, ok := attrs["level"].(float64); !ok || level < 1 || level > 6 {
+		return fmt.Errorf("attribute level must be 1 to 6")
+	}
+	return nil
+}
+
+func optionalOrder(attrs map[string]interface{}) error {
+	for key, value := range attrs {
+		if order, ok := value.(float64); key != "order" || !ok || order < 0 {
+			return fmt.Errorf("attribute %s is not allowed", key)
+		}
+	}
+	return nil
+}
+
+func requireExternalMedia(attrs map[string]interface{}) error {
+	if attrs["type"] != "external" {
+		return fmt.Errorf("attribute type must be external")
+	}
+	return requireString("url")(attrs)
+}
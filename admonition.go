@@ -0,0 +1,117 @@
+package markdownconverter
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/gomarkdown/markdown/ast"
+)
+
+// admonitionMarker matches the "[!NOTE]" style marker on the first line of an
+// admonition block quote
+var admonitionMarker *regexp.Regexp = regexp.MustCompile(`(?i)^\[!(note|tip|important|warning|caution)\][ \t]*(\n|$)`)
+
+// admonitionLabels are the display names of each admonition type
+var admonitionLabels map[string]string = map[string]string{
+	"caution":   "Caution",
+	"important": "Important",
+	"note":      "Note",
+	"tip":       "Tip",
+	"warning":   "Warning",
+}
+
+// admonitionEmoji are the emoji shown before the label of each admonition type
+var admonitionEmoji map[string]string = map[string]string{
+	"caution":   "🛑",
+	"important": "❗",
+	"note":      "ℹ️",
+	"tip":       "💡",
+	"warning":   "⚠️",
+}
+
+// Admonition is a GitHub style callout, a block quote starting with a marker
+// such as "[!NOTE]" or "[!WARNING]", with the marker removed from its content
+type Admonition struct {
+	ast.Container
+
+	// Type is the lower case admonition type, one of "note", "tip",
+	// "important", "warning", or "caution"
+	Type string
+}
+
+// Label returns the display name of the admonition type, such as "Warning"
+func (admonition *Admonition) Label() string {
+	return admonitionLabels[admonition.Type]
+}
+
+// Emoji returns the emoji shown before the label of the admonition type
+func (admonition *Admonition) Emoji() string {
+	return admonitionEmoji[admonition.Type]
+}
+
+// Admonitions replaces every block quote in the document that starts with an
+// admonition marker with an Admonition node containing the rest of its content
+func Admonitions(document ast.Node) {
+	quotes := make([]*ast.BlockQuote, 0)
+	ast.WalkFunc(document, func(node ast.Node, entering bool) ast.WalkStatus {
+		if quote, ok := node.(*ast.BlockQuote); ok && entering {
+			quotes = append(quotes, quote)
+		}
+		return ast.GoToNext
+	})
+
+	for _, quote := range quotes {
+		admonitionType := removeAdmonitionMarker(quote)
+		if admonitionType == "" {
+			continue
+		}
+
+		admonition := &Admonition{Type: admonitionType}
+		admonition.Children = quote.Children
+		for _, child := range admonition.Children {
+			child.SetParent(admonition)
+		}
+
+		parent := quote.Parent.AsContainer()
+		for index, sibling := range parent.Children {
+			if sibling == quote {
+				parent.Children[index] = admonition
+			}
+		}
+		admonition.Parent = quote.Parent
+	}
+}
+
+// removeAdmonitionMarker removes the marker from the start of the block quote
+// and returns its type, or returns an empty string if there is no marker
+func removeAdmonitionMarker(quote *ast.BlockQuote) string {
+	if len(quote.Children) == 0 {
+		return ""
+	}
+	paragraph, ok := quote.Children[0].(*ast.Paragraph)
+	if !ok || len(paragraph.Children) == 0 {
+		return ""
+	}
+	text, ok := paragraph.Children[0].(*ast.Text)
+	if !ok {
+		return ""
+	}
+	match := admonitionMarker.FindSubmatch(text.Literal)
+	if match == nil {
+		return ""
+	}
+
+	text.Literal = text.Literal[len(match[0]):]
+	if len(text.Literal) == 0 {
+		paragraph.Children = paragraph.Children[1:]
+		if len(paragraph.Children) > 0 {
+			if _, ok := paragraph.Children[0].(*ast.Hardbreak); ok {
+				paragraph.Children = paragraph.Children[1:]
+			}
+		}
+		if len(paragraph.Children) == 0 {
+			quote.Children = quote.Children[1:]
+		}
+	}
+	return strings.ToLower(string(match[1]))
+}
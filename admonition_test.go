@@ -0,0 +1,104 @@
+package markdownconverter
+
+import (
+	"testing"
+
+	"github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/gomarkdown/markdown/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Admonitions(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		label    string
+		emoji    string
+		children int
+	}{
+		{
+			name:     "note",
+			input:    "> [!NOTE]\n> text",
+			expected: "note",
+			label:    "Note",
+			emoji:    "ℹ️",
+			children: 1,
+		},
+		{
+			name:     "case_insensitive",
+			input:    "> [!Important]  \n> text",
+			expected: "important",
+			label:    "Important",
+			emoji:    "❗",
+			children: 1,
+		},
+		{
+			name:     "marker_paragraph",
+			input:    "> [!CAUTION]\n>\n> text",
+			expected: "caution",
+			label:    "Caution",
+			emoji:    "🛑",
+			children: 1,
+		},
+		{
+			name:     "nested",
+			input:    "- item\n\n  > [!TIP]\n  > text",
+			expected: "tip",
+			label:    "Tip",
+			emoji:    "💡",
+			children: 1,
+		},
+		{
+			name:  "inline_marker",
+			input: "> [!NOTE] text",
+		},
+		{
+			name:  "unknown_type",
+			input: "> [!DANGER]\n> text",
+		},
+		{
+			name:  "block_quote",
+			input: "> text",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			document := markdown.Parse([]byte(test.input), parser.NewWithExtensions(parser.CommonExtensions))
+			Admonitions(document)
+
+			var admonition *Admonition
+			quotes := 0
+			ast.WalkFunc(document, func(node ast.Node, entering bool) ast.WalkStatus {
+				switch node := node.(type) {
+				case *Admonition:
+					admonition = node
+				case *ast.BlockQuote:
+					if entering {
+						quotes++
+					}
+				}
+				return ast.GoToNext
+			})
+
+			if test.expected == "" {
+				assert.Nil(t, admonition)
+				assert.Equal(t, 1, quotes)
+				return
+			}
+			assert.Equal(t, 0, quotes)
+			assert.Equal(t, test.expected, admonition.Type)
+			assert.Equal(t, test.label, admonition.Label())
+			assert.Equal(t, test.emoji, admonition.Emoji())
+			assert.Len(t, admonition.Children, test.children)
+			for _, child := range admonition.Children {
+				assert.Equal(t, admonition, child.GetParent())
+			}
+			text := admonition.Children[0].GetChildren()[0].AsLeaf().Literal
+			assert.Equal(t, "text", string(text))
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// Package asciidoc converts markdown to AsciiDoc
+package asciidoc
+
+import (
+	"strings"
+
+	"github.com/evilmonkeyinc/markdownconverter"
+	"github.com/evilmonkeyinc/markdownconverter/links"
+	"github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/gomarkdown/markdown/parser"
+)
+
+// New returns a new instance of Converter
+func New() *Converter {
+	return &Converter{}
+}
+
+// Converter is the AsciiDoc Converter implementation
+type Converter struct {
+	// Links is used to rewrite the destinations of links and images, leave nil
+	// to keep them as they are
+	Links *links.Rewriter
+}
+
+// Format returns a unique name for the converter
+func (converter *Converter) Format() string {
+	return "asciidoc"
+}
+
+// Parse will parse the standard markdown and return the converted data
+func (converter *Converter) Parse(markdwn []byte) ([]byte, error) {
+	frontMatter, body := markdownconverter.FrontMatter(markdwn)
+	document := markdown.Parse(markdown.NormalizeNewlines(body), parser.New())
+	markdownconverter.Admonitions(document)
+	if converter.Links != nil {
+		if err := converter.Links.Rewrite(document); err != nil {
+			return nil, err
+		}
+	}
+
+	renderer := &renderer{headingOffset: 1}
+	nodes := document.GetChildren()
+	title := frontMatter["title"]
+	if heading, ok := documentTitle(nodes); ok && title == "" {
+		// the only top level heading is the document title, so the other
+		// headings are moved up a level
+		title = renderer.inline(heading)
+		nodes = nodes[1:]
+		renderer.headingOffset = 0
+	}
+
+	output := renderer.blocks(nodes, "\n\n")
+	if title != "" {
+		output = "= " + title + "\n\n" + output
+	}
+	return []byte(strings.TrimSpace(output)), nil
+}
+
+// documentTitle returns the first node if it is a level one heading and
+// there are no other level one headings
+func documentTitle(nodes []ast.Node) (*ast.Heading, bool) {
+	if len(nodes) == 0 {
+		return nil, false
+	}
+	title, ok := nodes[0].(*ast.Heading)
+	if !ok || title.Level != 1 {
+		return nil, false
+	}
+	for _, node := range nodes[1:] {
+		if heading, ok := node.(*ast.Heading); ok && heading.Level == 1 {
+			return nil, false
+		}
+	}
+	return title, true
+}
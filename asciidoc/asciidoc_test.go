@@ -0,0 +1,152 @@
+package asciidoc
+
+import (
+	"testing"
+
+	"github.com/evilmonkeyinc/markdownconverter/links"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Converter_Format(t *testing.T) {
+	actual := New().Format()
+	assert.Equal(t, "asciidoc", actual)
+}
+
+func Test_Converter_Parse(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "document_title",
+			input:    "# Title\n\n## Section\n\n### Subsection",
+			expected: "= Title\n\n== Section\n\n=== Subsection",
+		},
+		{
+			name:     "sections",
+			input:    "# First\n\n# Second\n\n###### Deepest",
+			expected: "== First\n\n== Second\n\n====== Deepest",
+		},
+		{
+			name:     "front_matter_title",
+			input:    "---\ntitle: Notes\n---\n# First",
+			expected: "= Notes\n\n== First",
+		},
+		{
+			name:     "section_id",
+			input:    "## Install {#install}",
+			expected: "[#install]\n=== Install",
+		},
+		{
+			name:     "formatting",
+			input:    "**bold** _italic_ ~~strikethrough~~ <u>underline</u> `code`",
+			expected: "*bold* _italic_ [.line-through]#strikethrough# [.underline]##underline## `+code+`",
+		},
+		{
+			name:     "unconstrained",
+			input:    "un**believ**able",
+			expected: "un**believ**able",
+		},
+		{
+			name:     "escaping",
+			input:    "snake_case and 2*3 or a+b but not plain words",
+			expected: "++snake_case++ and ++2*3++ or pass:[a+b] but not plain words",
+		},
+		{
+			name:     "line_starts",
+			input:    "\\- not a list\n\n1\\. not a list\n\n= eq\n\n\\* star\n\n// not a comment\n\n.title\n\nline\n\\- next",
+			expected: "{empty}- not a list\n\n{empty}1. not a list\n\n{empty}= eq\n\n++*++ star\n\n{empty}// not a comment\n\n{empty}.title\n\nline\n{empty}- next",
+		},
+		{
+			name:     "links",
+			input:    "[evilmonkeyinc](https://github.com/evilmonkeyinc) and <https://github.com>",
+			expected: "https://github.com/evilmonkeyinc[evilmonkeyinc] and https://github.com",
+		},
+		{
+			name:     "cross_references",
+			input:    "[install](#install), [setup](docs/setup.md#linux), and [notes](notes.txt)",
+			expected: "<<install,install>>, xref:docs/setup.adoc#linux[setup], and link:notes.txt[notes]",
+		},
+		{
+			name:     "images",
+			input:    "![The logo](logo.png \"Logo\")\n\ninline ![icon, small](icon.png)",
+			expected: "image::logo.png[The logo,title=\"Logo\"]\n\ninline image:icon.png[\"icon, small\"]",
+		},
+		{
+			name:     "source",
+			input:    "```go\nfunc main() {}\n```\n\n```\n----\n```",
+			expected: "[source,go]\n----\nfunc main() {}\n----\n\n-----\n----\n-----",
+		},
+		{
+			name:     "diagram",
+			input:    "```dot\ndigraph { a -> b }\n```",
+			expected: "[graphviz]\n....\ndigraph { a -> b }\n....",
+		},
+		{
+			name:     "math",
+			input:    "inline $x^2$\n\n$$\ny = mx + c\n$$",
+			expected: "inline latexmath:[x^2]\n\n[latexmath]\n++++\ny = mx + c\n++++",
+		},
+		{
+			name:     "admonition",
+			input:    "> [!IMPORTANT]\n> Read this",
+			expected: "[IMPORTANT]\n====\nRead this\n====",
+		},
+		{
+			name:     "quote",
+			input:    "> quoted\n> > nested",
+			expected: "____\nquoted\n\n_____\nnested\n_____\n____",
+		},
+		{
+			name:     "lists",
+			input:    "* one\n* two\n  1. nested\n  2. more\n* three",
+			expected: "* one\n* two\n.. nested\n.. more\n* three",
+		},
+		{
+			name:     "list_continuation",
+			input:    "1. first\n\n    more about first\n\n2. second",
+			expected: ". first\n+\nmore about first\n. second",
+		},
+		{
+			name:     "table",
+			input:    "| Name | Value |\n| --- | --- |\n| one | **1** |",
+			expected: "[options=\"header\"]\n|===\n|Name |Value\n\n|one |*1*\n|===",
+		},
+		{
+			name:     "horizontal_rule",
+			input:    "above\n\n---\n\nbelow",
+			expected: "above\n\n'''\n\nbelow",
+		},
+		{
+			name:     "html",
+			input:    "text\n\n<div>\nhtml\n</div>\n",
+			expected: "text\n\n++++\n<div>\nhtml\n</div>\n++++",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := New().Parse([]byte(test.input))
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, string(actual))
+		})
+	}
+}
+
+func Test_Converter_Parse_Links(t *testing.T) {
+	converter := New()
+	converter.Links = &links.Rewriter{
+		BaseURL:        "https://example.com/docs/",
+		MarkdownToHTML: true,
+	}
+
+	actual, err := converter.Parse([]byte("[setup](./setup.md)"))
+	assert.Nil(t, err)
+	assert.Equal(t, "https://example.com/docs/setup.html[setup]", string(actual))
+
+	converter.Links.BaseURL = "invalid"
+	_, err = converter.Parse([]byte("[setup](./setup.md)"))
+	assert.ErrorIs(t, err, links.ErrInvalidBaseURL)
+}
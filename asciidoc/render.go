@@ -0,0 +1,363 @@
+package asciidoc
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/evilmonkeyinc/markdownconverter"
+	"github.com/gomarkdown/markdown/ast"
+)
+
+const (
+	// maxSectionMarkers is the number of markers of the deepest section
+	// level, deeper headings are shown at this level
+	maxSectionMarkers int = 6
+
+	// specialCharacters are the characters that could start AsciiDoc markup,
+	// words containing them are passed through as they are
+	specialCharacters string = "*_`#^~[]{}+\\<"
+)
+
+// word matches each word in text
+var word *regexp.Regexp = regexp.MustCompile(`\S+`)
+
+// lineStarts match the text at the start of a line that AsciiDoc would read
+// as the start of a block rather than as paragraph text
+var lineStarts []*regexp.Regexp = []*regexp.Regexp{
+	// lists
+	regexp.MustCompile(`^(?:-|\*+|\.+|\d+\.|[A-Za-z]\.)(?: |$)`),
+	// section titles
+	regexp.MustCompile(`^=+(?: |$)`),
+	// block titles, attribute lists, and comments
+	regexp.MustCompile(`^(?:\.[^ .]|\[|//)`),
+	// admonition paragraphs
+	regexp.MustCompile(`^(?:NOTE|TIP|IMPORTANT|WARNING|CAUTION): `),
+	// block delimiters and breaks
+	regexp.MustCompile(`^(?:-{2,}|={4,}|\.{4,}|\*{4,}|_{4,}|\+{4,}|/{4,}|'{3,}|<{3,})$`),
+}
+
+// linkSchemes are the URL schemes AsciiDoc recognises as links
+var linkSchemes map[string]bool = map[string]bool{
+	"ftp":    true,
+	"http":   true,
+	"https":  true,
+	"irc":    true,
+	"mailto": true,
+}
+
+type renderer struct {
+	// headingOffset is added to the heading level to get the number of
+	// section title markers
+	headingOffset int
+	// quoteDepth and exampleDepth are the number of quote and example blocks
+	// the current node is in, so nested blocks use longer delimiters
+	quoteDepth   int
+	exampleDepth int
+}
+
+// blocks renders each block node joined by the separator
+func (renderer *renderer) blocks(nodes []ast.Node, separator string) string {
+	parts := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		if text := renderer.block(node); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, separator)
+}
+
+func (renderer *renderer) block(node ast.Node) string {
+	switch node := node.(type) {
+	case *ast.Heading:
+		markers := node.Level + renderer.headingOffset
+		if markers > maxSectionMarkers {
+			markers = maxSectionMarkers
+		}
+		title := strings.Repeat("=", markers) + " " + renderer.inline(node)
+		if node.HeadingID != "" {
+			return "[#" + node.HeadingID + "]\n" + title
+		}
+		return title
+	case *ast.Paragraph:
+		if image, ok := markdownconverter.OnlyImage(node); ok {
+			return "image::" + string(image.Destination) + "[" + imageAttributes(image) + "]"
+		}
+		return escapeLines(renderer.inline(node))
+	case *ast.BlockQuote:
+		renderer.quoteDepth++
+		content := renderer.blocks(node.Children, "\n\n")
+		renderer.quoteDepth--
+		return delimited(strings.Repeat("_", 4+renderer.quoteDepth), content)
+	case *markdownconverter.Admonition:
+		renderer.exampleDepth++
+		content := renderer.blocks(node.Children, "\n\n")
+		renderer.exampleDepth--
+		return "[" + strings.ToUpper(node.Type) + "]\n" + delimited(strings.Repeat("=", 4+renderer.exampleDepth), content)
+	case *ast.CodeBlock:
+		code := strings.TrimRight(string(node.Literal), "\n")
+		if diagramType := markdownconverter.DiagramType(node.Info); diagramType != "" {
+			return "[" + diagramType + "]\n" + delimited(delimiter('.', code), code)
+		}
+		if fields := strings.Fields(string(node.Info)); len(fields) > 0 {
+			return "[source," + fields[0] + "]\n" + delimited(delimiter('-', code), code)
+		}
+		return delimited(delimiter('-', code), code)
+	case *ast.MathBlock:
+		math := strings.TrimSpace(string(node.Literal))
+		return "[latexmath]\n" + delimited(delimiter('+', math), math)
+	case *ast.List:
+		return renderer.list(node, 1)
+	case *ast.HorizontalRule:
+		return "'''"
+	case *ast.Table:
+		return renderer.table(node)
+	case *ast.HTMLBlock:
+		html := strings.TrimRight(string(node.Literal), "\n")
+		return delimited(delimiter('+', html), html)
+	default:
+		if container := node.AsContainer(); container != nil {
+			return renderer.blocks(container.Children, "\n\n")
+		}
+		return escape(strings.TrimSpace(string(node.AsLeaf().Literal)))
+	}
+}
+
+// list renders the list with markers repeated for the depth of the list,
+// attaching blocks after the first paragraph of an item with a list
+// continuation
+func (renderer *renderer) list(node *ast.List, depth int) string {
+	marker := strings.Repeat("*", depth)
+	if node.ListFlags&ast.ListTypeOrdered != 0 {
+		marker = strings.Repeat(".", depth)
+	}
+
+	items := make([]string, 0, len(node.Children))
+	for _, item := range node.Children {
+		lines := []string{marker}
+		for index, child := range item.GetChildren() {
+			switch child := child.(type) {
+			case *ast.List:
+				lines = append(lines, "\n"+renderer.list(child, depth+1))
+			case *ast.Paragraph:
+				if index == 0 {
+					lines[0] += " " + escapeLines(renderer.inline(child))
+					continue
+				}
+				lines = append(lines, "\n+\n"+renderer.block(child))
+			default:
+				if text := renderer.block(child); text != "" {
+					lines = append(lines, "\n+\n"+text)
+				}
+			}
+		}
+		items = append(items, strings.Join(lines, ""))
+	}
+	return strings.Join(items, "\n")
+}
+
+// table renders the table with a header row
+func (renderer *renderer) table(node *ast.Table) string {
+	rows := make([]string, 0)
+	ast.WalkFunc(node, func(child ast.Node, entering bool) ast.WalkStatus {
+		row, ok := child.(*ast.TableRow)
+		if !ok || !entering {
+			return ast.GoToNext
+		}
+		cells := make([]string, 0, len(row.Children))
+		for _, cell := range row.Children {
+			cells = append(cells, "|"+strings.ReplaceAll(renderer.inline(cell), "|", `\|`))
+		}
+		line := strings.Join(cells, " ")
+		if _, header := row.Parent.(*ast.TableHeader); header {
+			// a blank line after the first row marks it as the header
+			line += "\n"
+		}
+		rows = append(rows, line)
+		return ast.SkipChildren
+	})
+	return "[options=\"header\"]\n|===\n" + strings.Join(rows, "\n") + "\n|==="
+}
+
+// inline renders the inline children of the node
+func (renderer *renderer) inline(node ast.Node) string {
+	builder := &strings.Builder{}
+	for _, child := range node.GetChildren() {
+		builder.WriteString(renderer.inlineNode(child))
+	}
+	return strings.TrimSpace(builder.String())
+}
+
+func (renderer *renderer) inlineNode(node ast.Node) string {
+	switch node := node.(type) {
+	case *ast.Text:
+		return escape(string(node.Literal))
+	case *ast.Code:
+		return formatted(node, "`", "+"+string(node.Literal)+"+")
+	case *ast.Math:
+		return "latexmath:[" + strings.ReplaceAll(strings.TrimSpace(string(node.Literal)), "]", `\]`) + "]"
+	case *ast.Emph:
+		return formatted(node, "_", renderer.inline(node))
+	case *ast.Strong:
+		return formatted(node, "*", renderer.inline(node))
+	case *ast.Del:
+		return "[.line-through]" + formatted(node, "#", renderer.inline(node))
+	case *ast.Subscript:
+		return "~" + escape(string(node.Literal)) + "~"
+	case *ast.Superscript:
+		return "^" + escape(string(node.Literal)) + "^"
+	case *ast.Hardbreak:
+		return " +\n"
+	case *ast.Link:
+		return link(string(node.Destination), strings.ReplaceAll(renderer.inline(node), "]", `\]`))
+	case *ast.Image:
+		return "image:" + string(node.Destination) + "[" + imageAttributes(node) + "]"
+	case *ast.HTMLSpan:
+		tag := string(node.Literal)
+		if opening, ok := markdownconverter.UnderlineTag(node); ok {
+			if opening {
+				return "[.underline]##"
+			}
+			return "##"
+		}
+		return "+++" + tag + "+++"
+	default:
+		if node.AsContainer() != nil {
+			return renderer.inline(node)
+		}
+		return escape(string(node.AsLeaf().Literal))
+	}
+}
+
+// link renders a link to the destination. Links to an anchor or a markdown
+// file become cross references, and other relative links use the link macro.
+func link(destination, text string) string {
+	if strings.HasPrefix(destination, "#") {
+		if text == "" {
+			return "<<" + destination[1:] + ">>"
+		}
+		return "<<" + destination[1:] + "," + text + ">>"
+	}
+
+	parsed, err := url.Parse(destination)
+	switch {
+	case err != nil:
+		return "link:++" + destination + "++[" + text + "]"
+	case parsed.Scheme != "":
+		if !linkSchemes[parsed.Scheme] {
+			return "link:" + destination + "[" + text + "]"
+		}
+		if text == "" || text == escape(destination) {
+			return destination
+		}
+		return destination + "[" + text + "]"
+	case parsed.Host == "" && path.Ext(parsed.Path) == ".md":
+		target := strings.TrimSuffix(parsed.Path, ".md") + ".adoc"
+		if parsed.Fragment != "" {
+			target += "#" + parsed.Fragment
+		}
+		return "xref:" + target + "[" + text + "]"
+	default:
+		return "link:" + destination + "[" + text + "]"
+	}
+}
+
+// imageAttributes returns the alternative text and title attributes of the
+// image macro
+func imageAttributes(image *ast.Image) string {
+	attributes := quote(markdownconverter.PlainText(image))
+	if len(image.Title) == 0 {
+		return attributes
+	}
+	title := `title="` + strings.NewReplacer(`"`, `\"`, `]`, `\]`).Replace(string(image.Title)) + `"`
+	if attributes == "" {
+		return title
+	}
+	return attributes + "," + title
+}
+
+// quote returns the attribute value in double quotes if it contains
+// characters that would end it
+func quote(value string) string {
+	if value == "" || !strings.ContainsAny(value, `,]"=`) {
+		return value
+	}
+	return `"` + strings.NewReplacer(`"`, `\"`, `]`, `\]`).Replace(value) + `"`
+}
+
+// formatted wraps the content in the constrained marker, or the doubled
+// unconstrained marker if the node is directly against a word character
+func formatted(node ast.Node, marker, content string) string {
+	if wordCharacter(ast.GetPrevNode(node), false) || wordCharacter(ast.GetNextNode(node), true) {
+		marker += marker
+	}
+	return marker + content + marker
+}
+
+// wordCharacter returns true if the text of the sibling node next to the node
+// is a word character
+func wordCharacter(sibling ast.Node, next bool) bool {
+	text, ok := sibling.(*ast.Text)
+	if !ok || len(text.Literal) == 0 {
+		return false
+	}
+	var r rune
+	if next {
+		r, _ = utf8.DecodeRune(text.Literal)
+	} else {
+		r, _ = utf8.DecodeLastRune(text.Literal)
+	}
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// escape passes through each word of the text that contains characters that
+// could start AsciiDoc markup
+func escape(text string) string {
+	return word.ReplaceAllStringFunc(text, func(match string) string {
+		if !strings.ContainsAny(match, specialCharacters) {
+			return match
+		}
+		if strings.Contains(match, "+") {
+			return "pass:[" + strings.ReplaceAll(match, "]", `\]`) + "]"
+		}
+		return "++" + match + "++"
+	})
+}
+
+// escapeLines starts each line that would start a block with an empty
+// attribute reference, so it stays paragraph text
+func escapeLines(text string) string {
+	lines := strings.Split(text, "\n")
+	for index, line := range lines {
+		for _, lineStart := range lineStarts {
+			if lineStart.MatchString(line) {
+				lines[index] = "{empty}" + line
+				break
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// delimited returns the content between the delimiter lines
+func delimited(delimiterLine, content string) string {
+	if content == "" {
+		return delimiterLine + "\n" + delimiterLine
+	}
+	return delimiterLine + "\n" + content + "\n" + delimiterLine
+}
+
+// delimiter returns a delimiter line of the character that is longer than any
+// line of the content made only of that character
+func delimiter(character rune, content string) string {
+	length := 4
+	for _, line := range strings.Split(content, "\n") {
+		if line != "" && strings.Trim(line, string(character)) == "" && len(line) >= length {
+			length = len(line) + 1
+		}
+	}
+	return strings.Repeat(string(character), length)
+}
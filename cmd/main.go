@@ -4,19 +4,41 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	nethttp "net/http"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/evilmonkeyinc/markdownconverter"
+	"github.com/evilmonkeyinc/markdownconverter/adf"
+	"github.com/evilmonkeyinc/markdownconverter/asciidoc"
+	"github.com/evilmonkeyinc/markdownconverter/confluence"
+	"github.com/evilmonkeyinc/markdownconverter/discord"
+	"github.com/evilmonkeyinc/markdownconverter/email"
+	"github.com/evilmonkeyinc/markdownconverter/eml"
+	"github.com/evilmonkeyinc/markdownconverter/highlight"
 	"github.com/evilmonkeyinc/markdownconverter/http"
+	"github.com/evilmonkeyinc/markdownconverter/jira"
+	"github.com/evilmonkeyinc/markdownconverter/links"
+	"github.com/evilmonkeyinc/markdownconverter/preview"
+	"github.com/evilmonkeyinc/markdownconverter/rst"
+	"github.com/evilmonkeyinc/markdownconverter/server"
 	"github.com/evilmonkeyinc/markdownconverter/slack"
+	"github.com/evilmonkeyinc/markdownconverter/teams"
+	"github.com/evilmonkeyinc/markdownconverter/telegram"
+	"github.com/evilmonkeyinc/markdownconverter/terminal"
+	"github.com/evilmonkeyinc/markdownconverter/text"
 	flag "github.com/spf13/pflag"
 )
 
 const (
 	cmdHelp    string = "help"
+	cmdPreview string = "preview"
+	cmdServe   string = "serve"
 	cmdVersion string = "version"
+
+	previewAddress string = "localhost:8080"
 )
 
 var (
@@ -36,23 +58,188 @@ var (
 	errOutputFailedOpen  error = fmt.Errorf("failed to open output")
 	errOutputFailedWrite error = fmt.Errorf("failed to write output")
 	errParseFailed       error = fmt.Errorf("failed to parse")
+	errTablesUnexpected  error = fmt.Errorf("unexpected tables")
+	errModeUnexpected    error = fmt.Errorf("unexpected mode")
 )
 
-func loadConverters() (map[string]markdownconverter.Converter, []string) {
+// options holds the format specific settings passed on the command line
+type options struct {
+	extensions   []string
+	htmlFlags    []string
+	standalone   bool
+	defaultStyle bool
+	stylesheet   string
+	sanitize     bool
+	headingIDs   bool
+	anchors      bool
+	toc          bool
+	highlight    bool
+	theme        string
+	inline       bool
+	mathML       bool
+	diagramLink  string
+	suppress     bool
+	tables       string
+	telegramMode string
+	rstTables    string
+	textWidth    int
+	termWidth    int
+	noHyperlinks bool
+	baseURL      string
+	mdToHTML     bool
+	embedImages  bool
+	imageDir     string
+	imageLimit   int64
+	serve        bool
+	// format is the output format, empty when every format is used
+	format string
+}
+
+// validates returns true if the settings of the format should be validated,
+// which are only checked when the format is used
+func (opts options) validates(format string) bool {
+	return opts.format == "" || opts.format == format
+}
+
+func loadConverters(opts options) (map[string]markdownconverter.Converter, []string, error) {
 	converters := make(map[string]markdownconverter.Converter)
 	available := make([]string, 0)
 
 	// TODO: use plugins to find other converters in same directory as tool?
 
+	var rewriter *links.Rewriter
+	if opts.baseURL != "" || opts.mdToHTML {
+		rewriter = &links.Rewriter{
+			BaseURL:        opts.baseURL,
+			MarkdownToHTML: opts.mdToHTML,
+		}
+	}
+
 	slackConverter := slack.New()
+	slackConverter.Links = rewriter
+	slackConverter.DiagramLink = opts.diagramLink
 	available = append(available, slackConverter.Format())
 	converters[slackConverter.Format()] = slackConverter
 
 	httpConverter := http.New()
+	for _, extension := range opts.extensions {
+		if err := httpConverter.SetExtension(extension); err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, flag := range opts.htmlFlags {
+		if err := httpConverter.SetFlag(flag); err != nil {
+			return nil, nil, err
+		}
+	}
+	httpConverter.EmbedImages = opts.embedImages
+	httpConverter.ImageDir = opts.imageDir
+	httpConverter.ImageSizeLimit = opts.imageLimit
+	httpConverter.Links = rewriter
+	httpConverter.Standalone = opts.standalone
+	httpConverter.DefaultStyle = opts.defaultStyle
+	httpConverter.Stylesheet = opts.stylesheet
+	httpConverter.HeadingIDs = opts.headingIDs
+	httpConverter.HeadingAnchors = opts.anchors
+	httpConverter.TableOfContents = opts.toc
+	httpConverter.Highlight = opts.highlight
+	httpConverter.HighlightTheme = opts.theme
+	httpConverter.HighlightInline = opts.inline
+	httpConverter.MathML = opts.mathML
+	if opts.sanitize {
+		httpConverter.Sanitizer = http.DefaultPolicy()
+		httpConverter.Sanitizer.DataImages = opts.embedImages
+		httpConverter.Sanitizer.MathML = opts.mathML
+		httpConverter.Sanitizer.HighlightStyles = opts.highlight
+	}
 	available = append(available, httpConverter.Format())
 	converters[httpConverter.Format()] = httpConverter
 
-	return converters, available
+	emailConverter := email.New()
+	emailConverter.HTML.Extensions = httpConverter.Extensions
+	emailConverter.HTML.Links = rewriter
+	emailConverter.HTML.EmbedImages = opts.embedImages
+	emailConverter.HTML.ImageDir = opts.imageDir
+	emailConverter.HTML.ImageSizeLimit = opts.imageLimit
+	emailConverter.HTML.Highlight = opts.highlight
+	emailConverter.HTML.HighlightTheme = opts.theme
+	emailConverter.HTML.Sanitizer = httpConverter.Sanitizer
+	available = append(available, emailConverter.Format())
+	converters[emailConverter.Format()] = emailConverter
+
+	emlConverter := eml.New()
+	emlConverter.Email = emailConverter
+	emlConverter.AttachImages = !opts.serve
+	available = append(available, emlConverter.Format())
+	converters[emlConverter.Format()] = emlConverter
+
+	discordConverter := discord.New()
+	discordConverter.Links = rewriter
+	discordConverter.SuppressEmbeds = opts.suppress
+	if opts.validates(discordConverter.Format()) && opts.tables != discord.TableCode && opts.tables != discord.TableList {
+		return nil, nil, fmt.Errorf("%w '%s', expected: (%s, %s)", errTablesUnexpected, opts.tables, discord.TableCode, discord.TableList)
+	}
+	discordConverter.Tables = opts.tables
+	available = append(available, discordConverter.Format())
+	converters[discordConverter.Format()] = discordConverter
+
+	teamsConverter := teams.New()
+	teamsConverter.Links = rewriter
+	available = append(available, teamsConverter.Format())
+	converters[teamsConverter.Format()] = teamsConverter
+
+	telegramConverter := telegram.New()
+	telegramConverter.Links = rewriter
+	if opts.validates(telegramConverter.Format()) && opts.telegramMode != telegram.ModeMarkdownV2 && opts.telegramMode != telegram.ModeHTML {
+		return nil, nil, fmt.Errorf("%w '%s', expected: (%s, %s)", errModeUnexpected, opts.telegramMode, telegram.ModeMarkdownV2, telegram.ModeHTML)
+	}
+	telegramConverter.Mode = opts.telegramMode
+	available = append(available, telegramConverter.Format())
+	converters[telegramConverter.Format()] = telegramConverter
+
+	jiraConverter := jira.New()
+	jiraConverter.Links = rewriter
+	available = append(available, jiraConverter.Format())
+	converters[jiraConverter.Format()] = jiraConverter
+
+	confluenceConverter := confluence.New()
+	confluenceConverter.Links = rewriter
+	available = append(available, confluenceConverter.Format())
+	converters[confluenceConverter.Format()] = confluenceConverter
+
+	adfConverter := adf.New()
+	adfConverter.Links = rewriter
+	available = append(available, adfConverter.Format())
+	converters[adfConverter.Format()] = adfConverter
+
+	asciidocConverter := asciidoc.New()
+	asciidocConverter.Links = rewriter
+	available = append(available, asciidocConverter.Format())
+	converters[asciidocConverter.Format()] = asciidocConverter
+
+	rstConverter := rst.New()
+	rstConverter.Links = rewriter
+	if opts.validates(rstConverter.Format()) && opts.rstTables != rst.TableGrid && opts.rstTables != rst.TableList {
+		return nil, nil, fmt.Errorf("%w '%s', expected: (%s, %s)", errTablesUnexpected, opts.rstTables, rst.TableGrid, rst.TableList)
+	}
+	rstConverter.Tables = opts.rstTables
+	available = append(available, rstConverter.Format())
+	converters[rstConverter.Format()] = rstConverter
+
+	textConverter := text.New()
+	textConverter.Links = rewriter
+	textConverter.Width = opts.textWidth
+	available = append(available, textConverter.Format())
+	converters[textConverter.Format()] = textConverter
+
+	terminalConverter := terminal.New()
+	terminalConverter.Links = rewriter
+	terminalConverter.Width = opts.termWidth
+	terminalConverter.Hyperlinks = !opts.noHyperlinks
+	available = append(available, terminalConverter.Format())
+	converters[terminalConverter.Format()] = terminalConverter
+
+	return converters, available, nil
 }
 
 func printHelp(writer *os.File, flagset *flag.FlagSet) {
@@ -60,6 +247,8 @@ func printHelp(writer *os.File, flagset *flag.FlagSet) {
 	fmt.Fprintf(writer, "%s is a tool for converting markdown to other formats\n\n", Command)
 	fmt.Fprintf(writer, "Usage:\n\n")
 	fmt.Fprintf(writer, "  %s [format] [input] [output]\n", Command)
+	fmt.Fprintf(writer, "  %s serve\n", Command)
+	fmt.Fprintf(writer, "  %s preview [input]\n", Command)
 	fmt.Fprintf(writer, "\nExample:\n\n")
 	fmt.Fprintf(writer, `  %s slack "[evilmonkeyinc](https://github.com/evilmonkeyinc)"`+"\n", Command)
 	fmt.Fprintf(writer, `  > <https://github.com/evilmonkeyinc|evilmonkeyinc>`+"\n")
@@ -73,7 +262,9 @@ func outputError(err error) {
 }
 
 func main() {
-	var format, input, output string
+	var format, input, output, address string
+	var maxBodySize int64
+	var opts options
 
 	flagset := flag.NewFlagSet("", flag.ContinueOnError)
 	flagset.Usage = func() {}
@@ -81,6 +272,33 @@ func main() {
 	flagset.StringVarP(&format, "format", "f", "", "The output format")
 	flagset.StringVarP(&input, "input", "i", "", "The input source file")
 	flagset.StringVarP(&output, "output", "o", "", "The output destination file. optional")
+	flagset.StringVar(&address, "address", ":8080", "The address the serve and preview commands listen on, preview defaults to localhost:8080. optional")
+	flagset.Int64Var(&maxBodySize, "max-body-size", server.DefaultMaxBodySize, "The size in bytes above which the serve command rejects requests, 0 for no limit. optional")
+	flagset.StringVar(&opts.baseURL, "base-url", "", "The URL used to resolve relative links and images. optional")
+	flagset.BoolVar(&opts.mdToHTML, "md-to-html", false, "Rewrite relative links to markdown files to link to HTML files. optional")
+	flagset.StringVar(&opts.diagramLink, "diagram-link", "", "The URL linked in place of diagrams in the slack format output, such as the published page. optional")
+	flagset.BoolVar(&opts.suppress, "suppress-embeds", false, "Wrap links in angle brackets so Discord does not show link previews in the discord format output. optional")
+	flagset.StringVar(&opts.tables, "discord-tables", discord.TableCode, fmt.Sprintf("How tables are shown in the discord format output. optional (%s, %s)", discord.TableCode, discord.TableList))
+	flagset.StringVar(&opts.telegramMode, "telegram-mode", telegram.ModeMarkdownV2, fmt.Sprintf("The parse mode of the telegram format output. optional (%s, %s)", telegram.ModeMarkdownV2, telegram.ModeHTML))
+	flagset.StringVar(&opts.rstTables, "rst-tables", rst.TableGrid, fmt.Sprintf("How tables are written in the rst format output. optional (%s, %s)", rst.TableGrid, rst.TableList))
+	flagset.IntVar(&opts.textWidth, "text-width", text.DefaultWidth, "The number of columns the text format output is wrapped at, 0 to not wrap. optional")
+	flagset.IntVar(&opts.termWidth, "terminal-width", 0, "The number of columns the terminal format output is wrapped at, 0 to detect the width of the terminal. optional")
+	flagset.BoolVar(&opts.noHyperlinks, "no-hyperlinks", false, "Write the URL after links rather than using clickable hyperlinks in the terminal format output. optional")
+	flagset.StringSliceVar(&opts.extensions, "extension", nil, fmt.Sprintf("Parser extensions to enable for the http format, prefix with 'no-' to disable. optional (%s)", strings.Join(http.ExtensionNames(), ", ")))
+	flagset.BoolVar(&opts.headingIDs, "heading-ids", false, "Give every heading a unique ID in the http format output. optional")
+	flagset.BoolVar(&opts.anchors, "heading-anchors", false, "Add a link to itself in every heading in the http format output. optional")
+	flagset.BoolVar(&opts.toc, "toc", false, "Replace a [TOC] paragraph, or the start of the http format output, with a table of contents. optional")
+	flagset.BoolVar(&opts.highlight, "highlight", false, fmt.Sprintf("Add syntax highlighting to code blocks in the http format output. optional (%s)", strings.Join(highlight.Languages(), ", ")))
+	flagset.StringVar(&opts.theme, "highlight-theme", highlight.DefaultTheme, fmt.Sprintf("The syntax highlighting theme. optional (%s)", strings.Join(highlight.ThemeNames(), ", ")))
+	flagset.BoolVar(&opts.inline, "highlight-inline", false, "Use inline styles rather than classes for syntax highlighting. optional")
+	flagset.BoolVar(&opts.mathML, "mathml", false, "Render TeX math between $ or $$ delimiters as MathML in the http format output. optional")
+	flagset.BoolVar(&opts.sanitize, "sanitize", false, "Remove unsafe HTML, such as scripts and javascript links, from the http format output. optional")
+	flagset.BoolVar(&opts.embedImages, "embed-images", false, "Embed local images, relative to the input file, as data URIs in the http format output. optional")
+	flagset.Int64Var(&opts.imageLimit, "max-image-size", http.DefaultImageSizeLimit, "The size in bytes above which images are not embedded, 0 for no limit. optional")
+	flagset.BoolVar(&opts.standalone, "standalone", false, "Output a complete HTML document for the http format. optional")
+	flagset.BoolVar(&opts.defaultStyle, "default-style", false, "Embed the default stylesheet in standalone HTML documents. optional")
+	flagset.StringVar(&opts.stylesheet, "stylesheet", "", "The path or URL of a stylesheet to link from standalone HTML documents. optional")
+	flagset.StringSliceVar(&opts.htmlFlags, "html-flag", nil, fmt.Sprintf("Renderer flags to enable for the http format, prefix with 'no-' to disable. optional (%s)", strings.Join(http.FlagNames(), ", ")))
 	if err := flagset.Parse(os.Args[1:]); err != nil {
 		if !errors.Is(err, flag.ErrHelp) {
 			printHelp(os.Stderr, flagset)
@@ -98,6 +316,24 @@ func main() {
 	case cmdVersion:
 		fmt.Fprintf(os.Stdout, "version %s %s/%s\n", Version, OS, Arch)
 		return
+	case cmdPreview:
+		if input == "" {
+			input = flagset.Arg(1)
+		}
+		if !flagset.Changed("address") {
+			address = previewAddress
+		}
+		if err := startPreview(address, input, opts); err != nil {
+			outputError(err)
+		}
+		return
+	case cmdServe:
+		opts.serve = true
+		opts.embedImages = false
+		if err := serve(address, maxBodySize, opts); err != nil {
+			outputError(err)
+		}
+		return
 	default:
 		break
 	}
@@ -120,7 +356,16 @@ func main() {
 		outputError(errFormatUndefined)
 	}
 
-	converters, available := loadConverters()
+	opts.format = format
+	opts.imageDir = "."
+	if isFile(input) {
+		opts.imageDir = filepath.Dir(input)
+	}
+
+	converters, available, err := loadConverters(opts)
+	if err != nil {
+		outputError(err)
+	}
 
 	if converter, ok := converters[format]; ok {
 		inputBytes, err := handleInput(input)
@@ -141,13 +386,67 @@ func main() {
 	os.Exit(0)
 }
 
+// startPreview serves a live preview of the input file, with the HTML embedding
+// its images so the page does not need to serve them
+func startPreview(address, input string, opts options) error {
+	if input == "" {
+		return errInputUndefined
+	}
+	if _, err := os.Stat(input); err != nil {
+		return fmt.Errorf("%w %s", errInputFailedRead, err)
+	}
+
+	opts.standalone = true
+	opts.defaultStyle = opts.stylesheet == ""
+	opts.embedImages = true
+	opts.imageDir = filepath.Dir(input)
+	opts.format = "http"
+	converters, _, err := loadConverters(opts)
+	if err != nil {
+		return err
+	}
+
+	httpServer := &nethttp.Server{
+		Addr:              address,
+		Handler:           preview.New(input, converters["http"], converters["slack"]),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	fmt.Fprintf(os.Stderr, "previewing %s on http://%s\n", input, address)
+	return httpServer.ListenAndServe()
+}
+
+// serve starts the conversion service, local images are never read so
+// requests cannot include files from the server
+func serve(address string, maxBodySize int64, opts options) error {
+	converters, available, err := loadConverters(opts)
+	if err != nil {
+		return err
+	}
+
+	ordered := make([]markdownconverter.Converter, 0, len(available))
+	for _, format := range available {
+		ordered = append(ordered, converters[format])
+	}
+	handler := server.New(ordered...)
+	handler.MaxBodySize = maxBodySize
+
+	httpServer := &nethttp.Server{
+		Addr:              address,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       time.Minute,
+		WriteTimeout:      time.Minute,
+	}
+	fmt.Fprintf(os.Stderr, "listening on %s\n", address)
+	return httpServer.ListenAndServe()
+}
+
 func handleInput(filename string) ([]byte, error) {
 	if filename == "" {
 		return nil, errInputUndefined
 	}
 
-	filepath := filepath.Ext(filename)
-	if filepath == "" {
+	if !isFile(filename) {
 		return []byte(filename), nil
 	}
 
@@ -163,6 +462,12 @@ func handleInput(filename string) ([]byte, error) {
 	return byteValue, nil
 }
 
+// isFile returns true if the input should be treated as a filename rather
+// than the markdown itself
+func isFile(input string) bool {
+	return filepath.Ext(input) != ""
+}
+
 func handleOutput(filename string, content []byte) error {
 	if filename == "" {
 		if _, err := os.Stdout.Write(content); err != nil {
@@ -0,0 +1,87 @@
+// Package confluence converts markdown to the Confluence storage format, the
+// XHTML used to create and update pages with the Confluence API
+package confluence
+
+import (
+	"io"
+	"strings"
+
+	"github.com/evilmonkeyinc/markdownconverter"
+	"github.com/evilmonkeyinc/markdownconverter/links"
+	"github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/gomarkdown/markdown/html"
+	"github.com/gomarkdown/markdown/parser"
+)
+
+// New returns a new instance of Converter
+func New() *Converter {
+	return &Converter{}
+}
+
+// Converter is the Confluence storage format Converter implementation
+type Converter struct {
+	// Links is used to rewrite the destinations of links and images, leave nil
+	// to keep them as they are
+	Links *links.Rewriter
+}
+
+// Format returns a unique name for the converter
+func (converter *Converter) Format() string {
+	return "confluence"
+}
+
+// Parse will parse the standard markdown and return the converted data
+func (converter *Converter) Parse(markdwn []byte) ([]byte, error) {
+	_, body := markdownconverter.FrontMatter(markdwn)
+	document := markdown.Parse(markdown.NormalizeNewlines(body), parser.New())
+	markdownconverter.Admonitions(document)
+	if converter.Links != nil {
+		if err := converter.Links.Rewrite(document); err != nil {
+			return nil, err
+		}
+	}
+
+	// raw HTML is skipped as the storage format must be well formed XML
+	var renderer *html.Renderer
+	renderer = html.NewRenderer(html.RendererOptions{
+		Flags: html.UseXHTML | html.SkipHTML,
+		RenderNodeHook: func(w io.Writer, node ast.Node, entering bool) (ast.WalkStatus, bool) {
+			switch node := node.(type) {
+			case *ast.CodeBlock:
+				renderCode(w, renderer, node)
+				return ast.GoToNext, true
+			case *ast.Math:
+				renderer.Outs(w, "<code>")
+				html.EscapeHTML(w, []byte(strings.TrimSpace(string(node.Literal))))
+				renderer.Outs(w, "</code>")
+				return ast.GoToNext, true
+			case *ast.HTMLSpan:
+				if opening, ok := markdownconverter.UnderlineTag(node); ok {
+					if opening {
+						renderer.Outs(w, "<u>")
+					} else {
+						renderer.Outs(w, "</u>")
+					}
+				}
+				return ast.GoToNext, true
+			case *ast.MathBlock:
+				if entering {
+					renderCodeMacro(w, renderer, "", "", strings.TrimSpace(string(node.Literal)))
+				}
+				return ast.SkipChildren, true
+			case *ast.Image:
+				if entering {
+					renderImage(w, renderer, node)
+				}
+				return ast.SkipChildren, true
+			case *markdownconverter.Admonition:
+				renderAdmonition(w, renderer, node, entering)
+				return ast.GoToNext, true
+			}
+			return ast.GoToNext, false
+		},
+	})
+
+	return []byte(strings.TrimSpace(string(markdown.Render(document, renderer)))), nil
+}
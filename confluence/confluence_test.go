@@ -0,0 +1,130 @@
+package confluence
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/evilmonkeyinc/markdownconverter/links"
+	"github.com/stretchr/testify/assert"
+)
+
+// wellFormed returns an error if the storage format is not well formed XML
+func wellFormed(storage string) error {
+	decoder := xml.NewDecoder(strings.NewReader(`<page xmlns:ac="ac" xmlns:ri="ri">` + storage + `</page>`))
+	for {
+		if _, err := decoder.Token(); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+func Test_Converter_Format(t *testing.T) {
+	actual := New().Format()
+	assert.Equal(t, "confluence", actual)
+}
+
+func Test_Converter_Parse(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "formatting",
+			input:    "# Title\n\n**bold** _italic_ ~~strikethrough~~ <u>underline</u> `a<b` & more",
+			expected: "<h1>Title</h1>\n\n<p><strong>bold</strong> <em>italic</em> <del>strikethrough</del> <u>underline</u> <code>a&lt;b</code> &amp; more</p>",
+		},
+		{
+			name:     "raw_html",
+			input:    "before <span onclick=\"x\">text</span> after<br>\n\n<div>\nblock\n</div>",
+			expected: "<p>before text after</p>\n\n<p>\nblock\n</p>",
+		},
+		{
+			name:     "link",
+			input:    "[docs](https://example.com/?a=1&b=2)",
+			expected: `<p><a href="https://example.com/?a=1&amp;b=2">docs</a></p>`,
+		},
+		{
+			name:     "image",
+			input:    `![The "logo"](https://example.com/logo.png "Logo")`,
+			expected: `<p><ac:image ac:alt="The &quot;logo&quot;" ac:title="Logo"><ri:url ri:value="https://example.com/logo.png" /></ac:image></p>`,
+		},
+		{
+			name:     "code",
+			input:    "```Go\nfunc main() {}\n```",
+			expected: `<ac:structured-macro ac:name="code"><ac:parameter ac:name="language">go</ac:parameter><ac:plain-text-body><![CDATA[func main() {}]]></ac:plain-text-body></ac:structured-macro>`,
+		},
+		{
+			name:     "code_unknown_language",
+			input:    "```brainfuck\n+[]>\n```",
+			expected: `<ac:structured-macro ac:name="code"><ac:plain-text-body><![CDATA[+[]>]]></ac:plain-text-body></ac:structured-macro>`,
+		},
+		{
+			name:     "code_cdata_end",
+			input:    "```\nx[y[0]]>1\n```",
+			expected: `<ac:structured-macro ac:name="code"><ac:plain-text-body><![CDATA[x[y[0]]]]><![CDATA[>1]]></ac:plain-text-body></ac:structured-macro>`,
+		},
+		{
+			name:     "diagram",
+			input:    "```mermaid\ngraph TD\n```",
+			expected: `<ac:structured-macro ac:name="code"><ac:parameter ac:name="title">Mermaid diagram</ac:parameter><ac:plain-text-body><![CDATA[graph TD]]></ac:plain-text-body></ac:structured-macro>`,
+		},
+		{
+			name:     "math",
+			input:    "inline $x<y$",
+			expected: "<p>inline <code>x&lt;y</code></p>",
+		},
+		{
+			name:     "admonition",
+			input:    "> [!WARNING]\n> Be careful",
+			expected: "<ac:structured-macro ac:name=\"note\"><ac:parameter ac:name=\"title\">Warning</ac:parameter><ac:rich-text-body>\n<p>Be careful</p>\n</ac:rich-text-body></ac:structured-macro>",
+		},
+		{
+			name:     "admonition_tip",
+			input:    "> [!TIP]\n> Try this",
+			expected: "<ac:structured-macro ac:name=\"tip\"><ac:parameter ac:name=\"title\">Tip</ac:parameter><ac:rich-text-body>\n<p>Try this</p>\n</ac:rich-text-body></ac:structured-macro>",
+		},
+		{
+			name:     "xhtml",
+			input:    "above  \nbelow\n\n---",
+			expected: "<p>above<br />\nbelow</p>\n\n<hr />",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := New().Parse([]byte(test.input))
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, string(actual))
+			assert.Nil(t, wellFormed(string(actual)))
+		})
+	}
+}
+
+func Test_Converter_Parse_WellFormed(t *testing.T) {
+	input := "# Release <1.2>\n\n* one & two\n* three\n  1. nested\n\n> quote\n\n| Name | Value |\n| --- | --- |\n| a | <b>1</b> |\n\n<script>alert(1)</script>\n\n```html\n<p>]]></p>\n```"
+	actual, err := New().Parse([]byte(input))
+	assert.Nil(t, err)
+	assert.Nil(t, wellFormed(string(actual)))
+}
+
+func Test_Converter_Parse_Links(t *testing.T) {
+	converter := New()
+	converter.Links = &links.Rewriter{
+		BaseURL:        "https://example.com/docs/",
+		MarkdownToHTML: true,
+	}
+
+	actual, err := converter.Parse([]byte("[setup](./setup.md)"))
+	assert.Nil(t, err)
+	assert.Equal(t, `<p><a href="https://example.com/docs/setup.html">setup</a></p>`, string(actual))
+
+	converter.Links.BaseURL = "invalid"
+	_, err = converter.Parse([]byte("[setup](./setup.md)"))
+	assert.ErrorIs(t, err, links.ErrInvalidBaseURL)
+}
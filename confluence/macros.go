@@ -0,0 +1,138 @@
+package confluence
+
+import (
+	"io"
+	"strings"
+
+	"github.com/evilmonkeyinc/markdownconverter"
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/gomarkdown/markdown/html"
+)
+
+// admonitionMacros are the macros used for each admonition type
+var admonitionMacros map[string]string = map[string]string{
+	"caution":   "warning",
+	"important": "info",
+	"note":      "info",
+	"tip":       "tip",
+	"warning":   "note",
+}
+
+// codeLanguages are the languages of the code macro, keyed by the fenced code
+// block language
+var codeLanguages map[string]string = map[string]string{
+	"actionscript": "actionscript3",
+	"applescript":  "applescript",
+	"bash":         "bash",
+	"c#":           "c#",
+	"c++":          "cpp",
+	"cpp":          "cpp",
+	"cs":           "c#",
+	"csharp":       "c#",
+	"css":          "css",
+	"diff":         "diff",
+	"erlang":       "erl",
+	"go":           "go",
+	"golang":       "go",
+	"groovy":       "groovy",
+	"html":         "html",
+	"java":         "java",
+	"javascript":   "js",
+	"js":           "js",
+	"json":         "json",
+	"kotlin":       "kotlin",
+	"patch":        "diff",
+	"perl":         "perl",
+	"php":          "php",
+	"powershell":   "powershell",
+	"py":           "py",
+	"python":       "py",
+	"ruby":         "ruby",
+	"sass":         "sass",
+	"scala":        "scala",
+	"sh":           "bash",
+	"shell":        "bash",
+	"sql":          "sql",
+	"typescript":   "typescript",
+	"vb":           "vb",
+	"xml":          "xml",
+	"yaml":         "yml",
+	"yml":          "yml",
+	"zsh":          "bash",
+}
+
+// cdataEnd ends a CDATA section, so must be split when it appears in code
+const cdataEnd string = "]]>"
+
+// renderCode writes the code block as a code macro with its language, or
+// titled with the diagram type for diagrams
+func renderCode(w io.Writer, renderer *html.Renderer, codeBlock *ast.CodeBlock) {
+	code := strings.TrimRight(string(codeBlock.Literal), "\n")
+	if diagramType := markdownconverter.DiagramType(codeBlock.Info); diagramType != "" {
+		renderCodeMacro(w, renderer, "", markdownconverter.DiagramLabel(diagramType)+" diagram", code)
+		return
+	}
+
+	language := ""
+	if fields := strings.Fields(string(codeBlock.Info)); len(fields) > 0 {
+		language = codeLanguages[strings.ToLower(fields[0])]
+	}
+	renderCodeMacro(w, renderer, language, "", code)
+}
+
+// renderCodeMacro writes a code macro, with the language and title parameters
+// if they are not empty
+func renderCodeMacro(w io.Writer, renderer *html.Renderer, language, title, code string) {
+	renderer.CR(w)
+	renderer.Outs(w, `<ac:structured-macro ac:name="code">`)
+	renderParameter(w, renderer, "language", language)
+	renderParameter(w, renderer, "title", title)
+	renderer.Outs(w, "<ac:plain-text-body><![CDATA[")
+	renderer.Outs(w, strings.ReplaceAll(code, cdataEnd, "]]]]><![CDATA[>"))
+	renderer.Outs(w, "]]></ac:plain-text-body></ac:structured-macro>")
+	renderer.CR(w)
+}
+
+// renderAdmonition writes the admonition as an info, tip, note, or warning
+// macro titled with its label
+func renderAdmonition(w io.Writer, renderer *html.Renderer, admonition *markdownconverter.Admonition, entering bool) {
+	if !entering {
+		renderer.Outs(w, "</ac:rich-text-body></ac:structured-macro>")
+		renderer.CR(w)
+		return
+	}
+
+	renderer.CR(w)
+	renderer.Outs(w, `<ac:structured-macro ac:name="`+admonitionMacros[admonition.Type]+`">`)
+	renderParameter(w, renderer, "title", admonition.Label())
+	renderer.Outs(w, "<ac:rich-text-body>")
+	renderer.CR(w)
+}
+
+// renderImage writes the image as an image referencing its URL
+func renderImage(w io.Writer, renderer *html.Renderer, image *ast.Image) {
+	renderer.Outs(w, "<ac:image")
+	if alt := markdownconverter.PlainText(image); alt != "" {
+		renderer.Outs(w, ` ac:alt="`)
+		html.EscapeHTML(w, []byte(alt))
+		renderer.Outs(w, `"`)
+	}
+	if len(image.Title) > 0 {
+		renderer.Outs(w, ` ac:title="`)
+		html.EscapeHTML(w, image.Title)
+		renderer.Outs(w, `"`)
+	}
+	renderer.Outs(w, `><ri:url ri:value="`)
+	html.EscapeHTML(w, image.Destination)
+	renderer.Outs(w, `" /></ac:image>`)
+}
+
+// renderParameter writes a macro parameter if the value is not empty
+func renderParameter(w io.Writer, renderer *html.Renderer, name, value string) {
+	if value == "" {
+		return
+	}
+	renderer.Outs(w, `<ac:parameter ac:name="`+name+`">`)
+	html.EscapeHTML(w, []byte(value))
+	renderer.Outs(w, "</ac:parameter>")
+}
@@ -0,0 +1,38 @@
+package markdownconverter
+
+import (
+	"bytes"
+	"strings"
+)
+
+// diagramLanguages maps the info string languages of fenced diagram blocks to
+// their diagram type
+var diagramLanguages map[string]string = map[string]string{
+	"dot":      "graphviz",
+	"graphviz": "graphviz",
+	"mermaid":  "mermaid",
+	"plantuml": "plantuml",
+	"puml":     "plantuml",
+}
+
+// diagramLabels are the display names of each diagram type
+var diagramLabels map[string]string = map[string]string{
+	"graphviz": "Graphviz",
+	"mermaid":  "Mermaid",
+	"plantuml": "PlantUML",
+}
+
+// DiagramType returns the diagram type of a fenced code block from its info
+// string, one of "mermaid", "plantuml", or "graphviz", or an empty string if
+// the block is not a diagram
+func DiagramType(info []byte) string {
+	if end := bytes.IndexAny(info, "\t "); end >= 0 {
+		info = info[:end]
+	}
+	return diagramLanguages[strings.ToLower(string(info))]
+}
+
+// DiagramLabel returns the display name of the diagram type, such as "Mermaid"
+func DiagramLabel(diagramType string) string {
+	return diagramLabels[diagramType]
+}
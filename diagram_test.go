@@ -0,0 +1,33 @@
+package markdownconverter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DiagramType(t *testing.T) {
+
+	tests := []struct {
+		info     string
+		expected string
+		label    string
+	}{
+		{info: "mermaid", expected: "mermaid", label: "Mermaid"},
+		{info: "Mermaid title=flow", expected: "mermaid", label: "Mermaid"},
+		{info: "plantuml", expected: "plantuml", label: "PlantUML"},
+		{info: "puml", expected: "plantuml", label: "PlantUML"},
+		{info: "dot", expected: "graphviz", label: "Graphviz"},
+		{info: "graphviz", expected: "graphviz", label: "Graphviz"},
+		{info: "go", expected: "", label: ""},
+		{info: "", expected: "", label: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.info, func(t *testing.T) {
+			actual := DiagramType([]byte(test.info))
+			assert.Equal(t, test.expected, actual)
+			assert.Equal(t, test.label, DiagramLabel(actual))
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// Package discord converts markdown to the markdown flavour used by Discord
+// messages
+package discord
+
+import (
+	"strings"
+
+	"github.com/evilmonkeyinc/markdownconverter"
+	"github.com/evilmonkeyinc/markdownconverter/links"
+	"github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/parser"
+)
+
+const (
+	// DefaultMessageLimit is the maximum number of characters in a Discord message
+	DefaultMessageLimit int = 2000
+
+	// TableCode renders tables as aligned columns in a code block
+	TableCode string = "code"
+	// TableList renders each table row as a list item of header and value pairs
+	TableList string = "list"
+)
+
+// New returns a new instance of Converter
+func New() *Converter {
+	return &Converter{
+		MessageLimit: DefaultMessageLimit,
+		Tables:       TableCode,
+	}
+}
+
+// Converter is the Discord markdown Converter implementation
+type Converter struct {
+	// Links is used to rewrite the destinations of links and images, leave nil
+	// to keep them as they are
+	Links *links.Rewriter
+	// SuppressEmbeds will wrap URLs in angle brackets so Discord does not show
+	// a preview of the page they link to
+	SuppressEmbeds bool
+	// Tables is how tables, which Discord does not support, are shown, either
+	// TableCode or TableList
+	Tables string
+	// MessageLimit is the maximum number of characters in each message
+	// returned by Messages
+	MessageLimit int
+}
+
+// Format returns a unique name for the converter
+func (converter *Converter) Format() string {
+	return "discord"
+}
+
+// Parse will parse the standard markdown and return the converted data
+func (converter *Converter) Parse(markdwn []byte) ([]byte, error) {
+	_, body := markdownconverter.FrontMatter(markdwn)
+	document := markdown.Parse(markdown.NormalizeNewlines(body), parser.New())
+	markdownconverter.Admonitions(document)
+	if converter.Links != nil {
+		if err := converter.Links.Rewrite(document); err != nil {
+			return nil, err
+		}
+	}
+
+	renderer := &renderer{converter: converter}
+	return []byte(strings.TrimSpace(renderer.blocks(document.GetChildren(), "\n\n"))), nil
+}
+
+// Messages converts the markdown and splits it into messages no longer than
+// the message limit, so it can be posted as a series of messages
+func (converter *Converter) Messages(markdwn []byte) ([]string, error) {
+	output, err := converter.Parse(markdwn)
+	if err != nil {
+		return nil, err
+	}
+	return Split(string(output), converter.MessageLimit), nil
+}
@@ -0,0 +1,308 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/evilmonkeyinc/markdownconverter/links"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Converter_Format(t *testing.T) {
+	actual := New().Format()
+	assert.Equal(t, "discord", actual)
+}
+
+func Test_Converter_Parse(t *testing.T) {
+
+	converter := New()
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			input:    "# Heading 1",
+			expected: "# Heading 1",
+		},
+		{
+			input:    "### Heading 3",
+			expected: "### Heading 3",
+		},
+		{
+			input:    "#### Heading 4",
+			expected: "### Heading 4",
+		},
+		{
+			input:    "###### Heading 6",
+			expected: "### Heading 6",
+		},
+		{
+			input:    "**bold** and _italic_ and ~~strikethrough~~",
+			expected: "**bold** and *italic* and ~~strikethrough~~",
+		},
+		{
+			input:    "<u>underline</u> and <ins>inserted</ins>",
+			expected: "__underline__ and __inserted__",
+		},
+		{
+			input:    "a ||spoiler|| here",
+			expected: "a ||spoiler|| here",
+		},
+		{
+			input:    "escape 2*3 and snake_case",
+			expected: "escape 2\\*3 and snake\\_case",
+		},
+		{
+			input:    "use `go test` and $x^2$",
+			expected: "use `go test` and `x^2`",
+		},
+		{
+			input:    "``a`b`` and `` `tick` ``",
+			expected: "``a`b`` and `` `tick` ``",
+		},
+		{
+			input:    "\\# not heading\n\n\\- not list\n\n1\\. not list\n\n\\> not quote\n\n\\+ not list\n\n\\-# not subtext",
+			expected: "\\# not heading\n\n\\- not list\n\n1\\. not list\n\n\\> not quote\n\n\\+ not list\n\n\\-# not subtext",
+		},
+		{
+			input:    "```go\nfunc main() {}\n```",
+			expected: "```go\nfunc main() {}\n```",
+		},
+		{
+			input:    "* one\n* two\n  * nested",
+			expected: "- one\n- two\n  - nested",
+		},
+		{
+			input:    "1. one\n1. two",
+			expected: "1. one\n2. two",
+		},
+		{
+			input:    "> quoted\n> lines",
+			expected: "> quoted\n> lines",
+		},
+		{
+			input:    "above\n\n---\n\nbelow",
+			expected: "above\n\n───────────────\n\nbelow",
+		},
+		{
+			input:    "---\ntitle: Notes\n---\n# Notes",
+			expected: "# Notes",
+		},
+	}
+
+	for index, test := range tests {
+		t.Run(fmt.Sprintf("%d", index), func(t *testing.T) {
+			actual, err := converter.Parse([]byte(test.input))
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, string(actual))
+		})
+	}
+}
+
+func Test_Converter_Parse_Links(t *testing.T) {
+
+	tests := []struct {
+		name           string
+		input          string
+		suppressEmbeds bool
+		expected       string
+	}{
+		{
+			name:     "masked",
+			input:    "[evilmonkeyinc](https://github.com/evilmonkeyinc)",
+			expected: "[evilmonkeyinc](https://github.com/evilmonkeyinc)",
+		},
+		{
+			name:     "autolink",
+			input:    "<https://github.com>",
+			expected: "https://github.com",
+		},
+		{
+			name:     "image",
+			input:    "![logo](https://example.com/logo.png)",
+			expected: "https://example.com/logo.png",
+		},
+		{
+			name:           "suppress_masked",
+			input:          "[evilmonkeyinc](https://github.com/evilmonkeyinc)",
+			suppressEmbeds: true,
+			expected:       "[evilmonkeyinc](<https://github.com/evilmonkeyinc>)",
+		},
+		{
+			name:           "suppress_autolink",
+			input:          "<https://github.com>",
+			suppressEmbeds: true,
+			expected:       "<https://github.com>",
+		},
+		{
+			name:           "suppress_image",
+			input:          "![logo](https://example.com/logo.png)",
+			suppressEmbeds: true,
+			expected:       "<https://example.com/logo.png>",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			converter := New()
+			converter.SuppressEmbeds = test.suppressEmbeds
+			actual, err := converter.Parse([]byte(test.input))
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, string(actual))
+		})
+	}
+
+	converter := New()
+	converter.Links = &links.Rewriter{
+		BaseURL:        "https://example.com/docs/",
+		MarkdownToHTML: true,
+	}
+	actual, err := converter.Parse([]byte("[setup](./setup.md)"))
+	assert.Nil(t, err)
+	assert.Equal(t, "[setup](https://example.com/docs/setup.html)", string(actual))
+
+	converter.Links.BaseURL = "invalid"
+	_, err = converter.Parse([]byte("[setup](./setup.md)"))
+	assert.ErrorIs(t, err, links.ErrInvalidBaseURL)
+}
+
+func Test_Converter_Parse_Tables(t *testing.T) {
+
+	input := "| Name | Value |\n| --- | --- |\n| short | 1 |\n| longer name | 22 |"
+
+	tests := []struct {
+		tables   string
+		expected string
+	}{
+		{
+			tables:   TableCode,
+			expected: "```\nName         Value\nshort        1\nlonger name  22\n```",
+		},
+		{
+			tables:   TableList,
+			expected: "- **Name**: short, **Value**: 1\n- **Name**: longer name, **Value**: 22",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tables, func(t *testing.T) {
+			converter := New()
+			converter.Tables = test.tables
+			actual, err := converter.Parse([]byte(input))
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, string(actual))
+		})
+	}
+}
+
+func Test_Converter_Parse_Tables_Code(t *testing.T) {
+	actual, err := New().Parse([]byte("| 名前 | Value |\n| --- | --- |\n| **snake_case** | `1` |"))
+	assert.Nil(t, err)
+	assert.Equal(t, "```\n名前        Value\nsnake_case  1\n```", string(actual))
+}
+
+func Test_Converter_Parse_Admonitions(t *testing.T) {
+	actual, err := New().Parse([]byte("> [!WARNING]\n> Be *careful*\n> out there"))
+	assert.Nil(t, err)
+	assert.Equal(t, "> ⚠️ **Warning**\n> Be *careful*\n> out there", string(actual))
+}
+
+func Test_Converter_Parse_Diagrams(t *testing.T) {
+	actual, err := New().Parse([]byte("```mermaid\ngraph TD\n  A --> B\n```"))
+	assert.Nil(t, err)
+	assert.Equal(t, "**Mermaid diagram**\n```mermaid\ngraph TD\n  A --> B\n```", string(actual))
+}
+
+func Test_Converter_Messages(t *testing.T) {
+	converter := New()
+	converter.MessageLimit = 20
+
+	actual, err := converter.Messages([]byte("# Title\n\nfirst paragraph\n\nsecond paragraph"))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"# Title", "first paragraph", "second paragraph"}, actual)
+}
+
+func Test_Split(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		text     string
+		limit    int
+		expected []string
+	}{
+		{
+			name:     "empty",
+			text:     "",
+			limit:    10,
+			expected: nil,
+		},
+		{
+			name:     "under_limit",
+			text:     "short\ntext",
+			limit:    10,
+			expected: []string{"short\ntext"},
+		},
+		{
+			name:     "no_limit",
+			text:     "short\ntext",
+			limit:    0,
+			expected: []string{"short\ntext"},
+		},
+		{
+			name:     "lines",
+			text:     "first line\nsecond line\n\nthird line",
+			limit:    22,
+			expected: []string{"first line\nsecond line", "third line"},
+		},
+		{
+			name:     "long_line",
+			text:     "a line that is much too long",
+			limit:    12,
+			expected: []string{"a line that", "is much too", "long"},
+		},
+		{
+			name:     "long_word",
+			text:     "abcdefghijklmnop",
+			limit:    10,
+			expected: []string{"abcdefghij", "klmnop"},
+		},
+		{
+			name:     "code_block",
+			text:     "intro\n```go\nline one\nline two\nline three\n```\nafter",
+			limit:    30,
+			expected: []string{"intro\n```go\nline one\n```", "```go\nline two\nline three\n```", "after"},
+		},
+		{
+			name:     "quoted_code_block",
+			text:     "> intro\n> ```go\n> line one\n> line two\n> ```\nafter",
+			limit:    32,
+			expected: []string{"> intro\n> ```go\n> line one\n> ```", "> ```go\n> line two\n> ```\nafter"},
+		},
+		{
+			name:     "quoted_long_line",
+			text:     "> a quoted line that is long",
+			limit:    12,
+			expected: []string{"> a quoted", "> line that", "> is long"},
+		},
+		{
+			name:     "runes",
+			text:     "héllo wörld",
+			limit:    5,
+			expected: []string{"héllo", "wörld"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := Split(test.text, test.limit)
+			assert.Equal(t, test.expected, actual)
+			for _, message := range actual {
+				if test.limit > 0 {
+					assert.LessOrEqual(t, len([]rune(message)), test.limit, strings.ReplaceAll(message, "\n", "\\n"))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package discord
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/evilmonkeyinc/markdownconverter"
+	"github.com/gomarkdown/markdown/ast"
+)
+
+const (
+	// maxHeadingLevel is the smallest heading Discord supports, smaller
+	// headings are shown at this level
+	maxHeadingLevel int = 3
+
+	horizontalRule string = "───────────────"
+)
+
+// markdownEscaper escapes the characters Discord would treat as formatting
+// in plain text. Pipes are left alone so spoilers written as ||text|| work.
+var markdownEscaper *strings.Replacer = strings.NewReplacer(
+	`\`, `\\`,
+	`*`, `\*`,
+	`_`, `\_`,
+	`~`, `\~`,
+	"`", "\\`",
+)
+
+// lineStarts match the text at the start of a line that Discord would treat as
+// a heading, subtext, quote, or list, with the character to escape as the
+// first submatch
+var lineStarts []*regexp.Regexp = []*regexp.Regexp{
+	regexp.MustCompile(`^(#)#{0,2} `),
+	regexp.MustCompile(`^(-)# `),
+	regexp.MustCompile(`^(>)`),
+	regexp.MustCompile(`^([-+]) `),
+	regexp.MustCompile(`^\d+(\.) `),
+}
+
+type renderer struct {
+	converter *Converter
+}
+
+// blocks renders each block node joined by the separator
+func (renderer *renderer) blocks(nodes []ast.Node, separator string) string {
+	parts := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		if text := renderer.block(node); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, separator)
+}
+
+func (renderer *renderer) block(node ast.Node) string {
+	switch node := node.(type) {
+	case *ast.Heading:
+		level := node.Level
+		if level > maxHeadingLevel {
+			level = maxHeadingLevel
+		}
+		return strings.Repeat("#", level) + " " + renderer.inline(node)
+	case *ast.Paragraph:
+		return escapeLines(renderer.inline(node))
+	case *ast.BlockQuote:
+		return quote(renderer.blocks(node.Children, "\n\n"))
+	case *markdownconverter.Admonition:
+		content := node.Emoji() + " **" + node.Label() + "**"
+		if children := renderer.blocks(node.Children, "\n\n"); children != "" {
+			content += "\n" + children
+		}
+		return quote(content)
+	case *ast.CodeBlock:
+		info := string(node.Info)
+		if diagramType := markdownconverter.DiagramType(node.Info); diagramType != "" {
+			return "**" + markdownconverter.DiagramLabel(diagramType) + " diagram**\n" + codeBlock(info, string(node.Literal))
+		}
+		return codeBlock(info, string(node.Literal))
+	case *ast.MathBlock:
+		return codeBlock("", string(node.Literal))
+	case *ast.List:
+		return renderer.list(node)
+	case *ast.HorizontalRule:
+		return horizontalRule
+	case *ast.Table:
+		return renderer.table(node)
+	case *ast.HTMLBlock:
+		return ""
+	default:
+		if container := node.AsContainer(); container != nil {
+			return renderer.blocks(container.Children, "\n\n")
+		}
+		return strings.TrimSpace(string(node.AsLeaf().Literal))
+	}
+}
+
+func (renderer *renderer) list(node *ast.List) string {
+	items := make([]string, 0, len(node.Children))
+	start := node.Start
+	if start == 0 {
+		start = 1
+	}
+	for index, child := range node.Children {
+		marker := "- "
+		if node.ListFlags&ast.ListTypeOrdered != 0 {
+			marker = fmt.Sprintf("%d. ", start+index)
+		}
+		content := renderer.blocks(child.GetChildren(), "\n")
+		items = append(items, indent(content, marker, strings.Repeat(" ", len(marker))))
+	}
+	return strings.Join(items, "\n")
+}
+
+// table renders the table in the configured fallback style, with formatted
+// cells in a list or plain text cells in a code block
+func (renderer *renderer) table(node *ast.Table) string {
+	rows := make([][]string, 0)
+	ast.WalkFunc(node, func(child ast.Node, entering bool) ast.WalkStatus {
+		row, ok := child.(*ast.TableRow)
+		if !ok || !entering {
+			return ast.GoToNext
+		}
+		cells := make([]string, 0, len(row.Children))
+		for _, cell := range row.Children {
+			if renderer.converter.Tables == TableList {
+				cells = append(cells, renderer.inline(cell))
+			} else {
+				cells = append(cells, markdownconverter.PlainText(cell))
+			}
+		}
+		rows = append(rows, cells)
+		return ast.SkipChildren
+	})
+	if len(rows) == 0 {
+		return ""
+	}
+
+	if renderer.converter.Tables == TableList {
+		items := make([]string, 0, len(rows)-1)
+		for _, row := range rows[1:] {
+			pairs := make([]string, 0, len(row))
+			for index, value := range row {
+				header := ""
+				if index < len(rows[0]) {
+					header = rows[0][index]
+				}
+				pairs = append(pairs, "**"+header+"**: "+value)
+			}
+			items = append(items, "- "+strings.Join(pairs, ", "))
+		}
+		return strings.Join(items, "\n")
+	}
+
+	return codeBlock("", markdownconverter.AlignColumns(rows, 2))
+}
+
+// inline renders the inline children of the node
+func (renderer *renderer) inline(node ast.Node) string {
+	builder := &strings.Builder{}
+	for _, child := range node.GetChildren() {
+		builder.WriteString(renderer.inlineNode(child))
+	}
+	return strings.TrimSpace(builder.String())
+}
+
+func (renderer *renderer) inlineNode(node ast.Node) string {
+	switch node := node.(type) {
+	case *ast.Text:
+		return markdownEscaper.Replace(string(node.Literal))
+	case *ast.Code:
+		return code(string(node.Literal))
+	case *ast.Math:
+		return code(strings.TrimSpace(string(node.Literal)))
+	case *ast.Emph:
+		return "*" + renderer.inline(node) + "*"
+	case *ast.Strong:
+		return "**" + renderer.inline(node) + "**"
+	case *ast.Del:
+		return "~~" + renderer.inline(node) + "~~"
+	case *ast.Hardbreak:
+		return "\n"
+	case *ast.Link:
+		return renderer.link(renderer.inline(node), string(node.Destination))
+	case *ast.Image:
+		destination := string(node.Destination)
+		if renderer.converter.SuppressEmbeds {
+			destination = "<" + destination + ">"
+		}
+		return destination
+	case *ast.HTMLSpan:
+		if _, ok := markdownconverter.UnderlineTag(node); ok {
+			return "__"
+		}
+		return ""
+	default:
+		if node.AsContainer() != nil {
+			return renderer.inline(node)
+		}
+		return markdownEscaper.Replace(string(node.AsLeaf().Literal))
+	}
+}
+
+// link renders a masked link, or just the URL if the text is the URL
+func (renderer *renderer) link(text, destination string) string {
+	target := destination
+	if renderer.converter.SuppressEmbeds {
+		target = "<" + destination + ">"
+	}
+	if text == "" || text == markdownEscaper.Replace(destination) || "mailto:"+text == destination {
+		return target
+	}
+	return "[" + text + "](" + target + ")"
+}
+
+// code returns the inline code between more backticks than any run of them in
+// the code, with spaces inside the backticks if it starts or ends with one
+func code(text string) string {
+	backticks := "`"
+	for strings.Contains(text, backticks) {
+		backticks += "`"
+	}
+	if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+		text = " " + text + " "
+	}
+	return backticks + text + backticks
+}
+
+// escapeLines adds a backslash before the characters at the start of each
+// line that would start a heading, quote, or list
+func escapeLines(text string) string {
+	lines := strings.Split(text, "\n")
+	for index, line := range lines {
+		for _, lineStart := range lineStarts {
+			if match := lineStart.FindStringSubmatchIndex(line); match != nil {
+				lines[index] = line[:match[2]] + `\` + line[match[2]:]
+				break
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// codeBlock returns the code in a fenced code block
+func codeBlock(language, code string) string {
+	return "```" + language + "\n" + strings.TrimRight(code, "\n") + "\n```"
+}
+
+// quote prefixes every line of the text with a quote marker
+func quote(text string) string {
+	lines := strings.Split(text, "\n")
+	for index, line := range lines {
+		lines[index] = strings.TrimRight("> "+line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// indent adds the first prefix to the first line of the text and the other
+// prefix to every following line
+func indent(text, first, other string) string {
+	lines := strings.Split(text, "\n")
+	for index, line := range lines {
+		prefix := other
+		if index == 0 {
+			prefix = first
+		}
+		if line == "" {
+			lines[index] = ""
+		} else {
+			lines[index] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
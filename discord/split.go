@@ -0,0 +1,165 @@
+package discord
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// fence is the line that opens and closes a code block
+const fence string = "```"
+
+// quotePrefix matches the quote markers at the start of a line, which come
+// before the fence of a code block in a quote
+var quotePrefix *regexp.Regexp = regexp.MustCompile(`^(?:> ?)+`)
+
+// Split breaks the text into messages of no more than limit characters,
+// splitting between lines where possible. Code blocks that are split are
+// closed at the end of one message and opened again, with the same language
+// and in the same quote, at the start of the next. A limit of zero or less
+// returns the text as it is.
+func Split(text string, limit int) []string {
+	if text == "" {
+		return nil
+	}
+	if limit <= 0 || utf8.RuneCountInString(text) <= limit {
+		return []string{text}
+	}
+
+	splitter := &splitter{limit: limit}
+	for _, line := range strings.Split(text, "\n") {
+		splitter.add(line)
+	}
+	splitter.flush()
+	return splitter.messages
+}
+
+type splitter struct {
+	limit    int
+	messages []string
+	// lines are the lines of the current message
+	lines []string
+	// size is the number of characters in the current message
+	size int
+	// content is true once the current message has more than the reopened
+	// code block fence
+	content bool
+	// opening is the fence line of the code block the current line is in, or
+	// empty if it is not in a code block
+	opening string
+	// closing is the fence line that closes the code block, after the quote
+	// markers of the opening fence line
+	closing string
+}
+
+// add appends the line to the current message, starting a new message when
+// it would exceed the limit
+func (splitter *splitter) add(line string) {
+	prefix := quotePrefix.FindString(line)
+	trimmed := strings.TrimSpace(line[len(prefix):])
+	opens := splitter.opening == "" && strings.HasPrefix(trimmed, fence)
+	closes := splitter.opening != "" && trimmed == fence
+
+	// leave room to close the code block if the message ends inside it
+	closing := splitter.closing
+	if opens {
+		closing = prefix + fence
+	}
+	available := splitter.limit
+	if opens || (splitter.opening != "" && !closes) {
+		available -= utf8.RuneCountInString(closing) + 1
+	}
+
+	cut := false
+	for splitter.size+splitter.separator()+utf8.RuneCountInString(line) > available {
+		if splitter.content {
+			splitter.flush()
+			continue
+		}
+		if cut && line == "" {
+			break
+		}
+		// only the text after the quote markers is cut, and the rest of the
+		// line starts with the quote markers again
+		room := available - splitter.size - splitter.separator() - utf8.RuneCountInString(prefix)
+		if room < 1 {
+			room = 1
+		}
+		piece, rest := cutLine(line[len(prefix):], room, splitter.opening == "")
+		splitter.append(prefix + piece)
+		splitter.flush()
+		if rest != "" {
+			rest = prefix + rest
+		}
+		line, cut = rest, true
+	}
+	// blank lines are not needed at the start of a message, and nothing is
+	// left of a line that was cut to the end
+	if line != "" || (!cut && (splitter.content || splitter.opening != "")) {
+		splitter.append(line)
+	}
+
+	if opens {
+		splitter.opening = prefix + trimmed
+		splitter.closing = closing
+	} else if closes {
+		splitter.opening = ""
+		splitter.closing = ""
+	}
+}
+
+// append adds the line to the current message
+func (splitter *splitter) append(line string) {
+	splitter.size += splitter.separator() + utf8.RuneCountInString(line)
+	splitter.lines = append(splitter.lines, line)
+	splitter.content = true
+}
+
+// separator returns the number of characters needed before the next line
+func (splitter *splitter) separator() int {
+	if len(splitter.lines) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// flush finishes the current message, closing any open code block and
+// opening it again in the next message
+func (splitter *splitter) flush() {
+	if !splitter.content {
+		return
+	}
+
+	message := strings.Join(splitter.lines, "\n")
+	if splitter.opening != "" {
+		message += "\n" + splitter.closing
+	} else {
+		message = strings.TrimRight(message, "\n")
+	}
+	splitter.messages = append(splitter.messages, message)
+
+	splitter.lines = nil
+	splitter.size = 0
+	splitter.content = false
+	if splitter.opening != "" {
+		splitter.append(splitter.opening)
+		splitter.content = false
+	}
+}
+
+// cutLine splits the line after at most size characters, at the last space
+// before then if words is true
+func cutLine(line string, size int, words bool) (string, string) {
+	runes := []rune(line)
+	if len(runes) <= size {
+		return line, ""
+	}
+	if words {
+		for index := size; index > 0; index-- {
+			if runes[index] == ' ' {
+				return string(runes[:index]), string(runes[index+1:])
+			}
+		}
+	}
+	return string(runes[:size]), string(runes[size:])
+}
@@ -0,0 +1,83 @@
+// Package email converts markdown to HTML suitable for email clients, using a
+// table based layout with every style inlined onto the elements
+package email
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/evilmonkeyinc/markdownconverter/http"
+	"github.com/gomarkdown/markdown/html"
+	"github.com/gomarkdown/markdown/parser"
+)
+
+// New returns a new instance of Converter
+func New() *Converter {
+	htmlConverter := http.New()
+	htmlConverter.HighlightInline = true
+
+	return &Converter{
+		HTML:  htmlConverter,
+		Theme: DefaultTheme(),
+	}
+}
+
+// Converter is the email safe HTML Converter implementation
+type Converter struct {
+	// HTML is the converter used to render the markdown before the styles are
+	// inlined, its Standalone and StripFrontMatter options are ignored
+	HTML *http.Converter
+	// Theme is the layout and element styles used for the email
+	Theme *Theme
+}
+
+// Format returns a unique name for the converter
+func (converter *Converter) Format() string {
+	return "html-email"
+}
+
+// Parse will parse the standard markdown and return the converted data
+func (converter *Converter) Parse(markdwn []byte) ([]byte, error) {
+	fragment, err := converter.fragment(markdwn)
+	if err != nil {
+		return nil, err
+	}
+
+	theme := converter.Theme
+	buffer := &bytes.Buffer{}
+	buffer.WriteString("<!DOCTYPE html>\n<html>\n<head>\n")
+	buffer.WriteString("<meta charset=\"utf-8\">\n")
+	buffer.WriteString("<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\">\n")
+	buffer.WriteString("<title>")
+	html.EscapeHTML(buffer, []byte(converter.HTML.Title(markdwn)))
+	buffer.WriteString("</title>\n</head>\n")
+	fmt.Fprintf(buffer, "<body style=\"margin: 0; padding: 0; background-color: %s\">\n", theme.Background)
+	fmt.Fprintf(buffer, "<table role=\"presentation\" width=\"100%%\" cellpadding=\"0\" cellspacing=\"0\" border=\"0\" style=\"background-color: %s\">\n", theme.Background)
+	buffer.WriteString("<tr>\n<td align=\"center\" style=\"padding: 24px 12px\">\n")
+	fmt.Fprintf(buffer, "<table role=\"presentation\" width=\"%d\" cellpadding=\"0\" cellspacing=\"0\" border=\"0\" style=\"width: 100%%; max-width: %dpx; background-color: %s\">\n", theme.Width, theme.Width, theme.ContentBackground)
+	fmt.Fprintf(buffer, "<tr>\n<td style=\"padding: 24px; font-family: %s; font-size: %s; line-height: 1.5; color: %s\">\n", theme.FontFamily, theme.FontSize, theme.Color)
+	buffer.Write(fragment)
+	buffer.WriteString("\n</td>\n</tr>\n</table>\n</td>\n</tr>\n</table>\n</body>\n</html>\n")
+	return buffer.Bytes(), nil
+}
+
+// Text returns the plain text alternative of the markdown, for email clients
+// that do not display HTML
+func (converter *Converter) Text(markdwn []byte) ([]byte, error) {
+	return plainText(markdwn, converter.HTML.Extensions, converter.HTML.Links)
+}
+
+// fragment renders the markdown as HTML with the theme styles inlined
+func (converter *Converter) fragment(markdwn []byte) ([]byte, error) {
+	htmlConverter := *converter.HTML
+	htmlConverter.Standalone = false
+	htmlConverter.StripFrontMatter = true
+	// number ordered lists from their start, as in the plain text alternative
+	htmlConverter.Extensions |= parser.OrderedListStart
+
+	rendered, err := htmlConverter.Parse(markdwn)
+	if err != nil {
+		return nil, err
+	}
+	return http.InlineStyles(rendered, converter.Theme.Styles), nil
+}
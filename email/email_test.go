@@ -0,0 +1,145 @@
+package email
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/evilmonkeyinc/markdownconverter/links"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Converter_Format(t *testing.T) {
+	assert.Equal(t, "html-email", New().Format())
+}
+
+func Test_Converter_Parse(t *testing.T) {
+	converter := New()
+	converter.Theme.Width = 480
+	converter.Theme.Styles = map[string]string{
+		"h1": "font-size: 24px",
+		"p":  "margin: 0",
+	}
+	converter.HTML.Standalone = true
+
+	actual, err := converter.Parse([]byte("---\ntitle: Release <1.2>\n---\n# Heading\n\ntext"))
+	assert.Nil(t, err)
+
+	output := string(actual)
+	assert.Contains(t, output, "<title>Release &lt;1.2&gt;</title>\n")
+	assert.Contains(t, output, "<table role=\"presentation\" width=\"480\"")
+	assert.Contains(t, output, "max-width: 480px")
+	assert.Contains(t, output, "<h1 style=\"font-size: 24px\">Heading</h1>\n\n<p style=\"margin: 0\">text</p>\n</td>")
+	assert.NotContains(t, output, "<style>")
+	assert.NotContains(t, output, "title: ")
+	assert.Equal(t, 1, strings.Count(output, "<body"))
+	assert.True(t, converter.HTML.Standalone)
+}
+
+func Test_Converter_Parse_Highlight(t *testing.T) {
+	converter := New()
+	converter.HTML.Highlight = true
+
+	actual, err := converter.Parse([]byte("```go\nfunc main() {}\n```"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(actual), "<span style=\"")
+	assert.NotContains(t, string(actual), "class=\"hl-")
+}
+
+func Test_Converter_Parse_Error(t *testing.T) {
+	converter := New()
+	converter.HTML.Highlight = true
+	converter.HTML.HighlightTheme = "invalid"
+
+	actual, err := converter.Parse([]byte("text"))
+	assert.Nil(t, actual)
+	assert.NotNil(t, err)
+}
+
+func Test_Converter_Text(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "headings",
+			input:    "---\ntitle: Notes\n---\n# Title\n\n## Section\n\n### Sub *section*",
+			expected: "Title\n=====\n\nSection\n-------\n\nSub section\n",
+		},
+		{
+			name:     "inline",
+			input:    "Some **bold**, `code`, a [link](https://example.com), <https://example.com>, and ![alt](image.png)",
+			expected: "Some bold, code, a link (https://example.com), https://example.com, and alt (image.png)\n",
+		},
+		{
+			name:     "lists",
+			input:    "- one\n- two\n  1. three\n  2. four",
+			expected: "- one\n- two\n  1. three\n  2. four\n",
+		},
+		{
+			name:     "ordered_list_start",
+			input:    "3. third\n4. fourth",
+			expected: "3. third\n4. fourth\n",
+		},
+		{
+			name:     "blockquote",
+			input:    "> first\n>\n> second",
+			expected: "> first\n>\n> second\n",
+		},
+		{
+			name:     "admonition",
+			input:    "> [!WARNING]\n> Be careful",
+			expected: "> Warning:\n> Be careful\n",
+		},
+		{
+			name:     "code_block",
+			input:    "```go\nfunc main() {\n}\n```",
+			expected: "    func main() {\n    }\n",
+		},
+		{
+			name:     "table",
+			input:    "| a | b |\n| --- | --- |\n| 1 | 2 |",
+			expected: "a | b\n1 | 2\n",
+		},
+		{
+			name:     "rule_and_html",
+			input:    "before\n\n---\n\n<div>html</div>\n\nafter <b>bold</b>",
+			expected: "before\n\n--------------------\n\nafter bold\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := New().Text([]byte(test.input))
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, string(actual))
+		})
+	}
+}
+
+func Test_Converter_Text_Links(t *testing.T) {
+	converter := New()
+	converter.HTML.Links = &links.Rewriter{
+		BaseURL:        "https://example.com/docs/",
+		MarkdownToHTML: true,
+	}
+
+	actual, err := converter.Text([]byte("[setup](./setup.md)"))
+	assert.Nil(t, err)
+	assert.Equal(t, "setup (https://example.com/docs/setup.html)\n", string(actual))
+
+	converter.HTML.Links.BaseURL = "invalid"
+	_, err = converter.Text([]byte("[setup](./setup.md)"))
+	assert.ErrorIs(t, err, links.ErrInvalidBaseURL)
+}
+
+func Test_Converter_Parse_Admonition(t *testing.T) {
+	actual, err := New().Parse([]byte("> [!TIP]\n> Try this\n\n3. third"))
+	assert.Nil(t, err)
+
+	output := string(actual)
+	assert.Contains(t, output, `<div class="admonition tip" style="margin: 0 0 16px; padding: 8px 16px; border-left: 4px solid #d0d7de; border-left-color: #1a7f37">`)
+	assert.Contains(t, output, `<p class="admonition-title" style="margin: 0 0 16px; font-weight: bold">Tip</p>`)
+	assert.Contains(t, output, `<ol start="3"`)
+}
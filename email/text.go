@@ -0,0 +1,164 @@
+package email
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/evilmonkeyinc/markdownconverter"
+	"github.com/evilmonkeyinc/markdownconverter/links"
+	"github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/gomarkdown/markdown/parser"
+)
+
+// plainText renders the markdown as readable plain text, with the links
+// rewritten by the rewriter if it is not nil
+func plainText(markdwn []byte, extensions parser.Extensions, rewriter *links.Rewriter) ([]byte, error) {
+	_, body := markdownconverter.FrontMatter(markdwn)
+	document := markdown.Parse(body, parser.NewWithExtensions(extensions|parser.OrderedListStart))
+	markdownconverter.Admonitions(document)
+	if rewriter != nil {
+		if err := rewriter.Rewrite(document); err != nil {
+			return nil, err
+		}
+	}
+	return []byte(strings.Trim(blocks(document.GetChildren()), "\n") + "\n"), nil
+}
+
+// blocks renders each block node separated by a blank line
+func blocks(nodes []ast.Node) string {
+	parts := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		if text := block(node); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func block(node ast.Node) string {
+	switch node := node.(type) {
+	case *ast.Heading:
+		text := inline(node)
+		switch node.Level {
+		case 1:
+			return text + "\n" + strings.Repeat("=", len([]rune(text)))
+		case 2:
+			return text + "\n" + strings.Repeat("-", len([]rune(text)))
+		default:
+			return text
+		}
+	case *ast.Paragraph:
+		return inline(node)
+	case *ast.BlockQuote:
+		return markdownconverter.PrefixLines(blocks(node.Children), "> ", "> ")
+	case *markdownconverter.Admonition:
+		content := node.Label() + ":"
+		if children := blocks(node.Children); children != "" {
+			content += "\n" + children
+		}
+		return markdownconverter.PrefixLines(content, "> ", "> ")
+	case *ast.CodeBlock:
+		return markdownconverter.PrefixLines(strings.TrimRight(string(node.Literal), "\n"), "    ", "    ")
+	case *ast.List:
+		return list(node)
+	case *ast.HorizontalRule:
+		return strings.Repeat("-", 20)
+	case *ast.Table:
+		return table(node)
+	case *ast.HTMLBlock:
+		return ""
+	default:
+		if container := node.AsContainer(); container != nil {
+			return blocks(container.Children)
+		}
+		return strings.TrimSpace(string(node.AsLeaf().Literal))
+	}
+}
+
+func list(node *ast.List) string {
+	start := node.Start
+	if start == 0 {
+		start = 1
+	}
+
+	items := make([]string, 0, len(node.Children))
+	for index, child := range node.Children {
+		marker := "- "
+		if node.ListFlags&ast.ListTypeOrdered != 0 {
+			marker = fmt.Sprintf("%d. ", index+start)
+		}
+
+		separator := "\n"
+		if !node.Tight {
+			separator = "\n\n"
+		}
+		parts := make([]string, 0)
+		for _, grandchild := range child.GetChildren() {
+			if text := block(grandchild); text != "" {
+				parts = append(parts, text)
+			}
+		}
+		items = append(items, markdownconverter.PrefixLines(strings.Join(parts, separator), marker, strings.Repeat(" ", len(marker))))
+	}
+
+	if node.Tight {
+		return strings.Join(items, "\n")
+	}
+	return strings.Join(items, "\n\n")
+}
+
+func table(node *ast.Table) string {
+	rows := make([]string, 0)
+	ast.WalkFunc(node, func(child ast.Node, entering bool) ast.WalkStatus {
+		row, ok := child.(*ast.TableRow)
+		if !ok || !entering {
+			return ast.GoToNext
+		}
+		cells := make([]string, 0, len(row.Children))
+		for _, cell := range row.Children {
+			cells = append(cells, inline(cell))
+		}
+		rows = append(rows, strings.Join(cells, " | "))
+		return ast.SkipChildren
+	})
+	return strings.Join(rows, "\n")
+}
+
+// inline renders the text of the node and its inline children
+func inline(node ast.Node) string {
+	builder := &strings.Builder{}
+	for _, child := range node.GetChildren() {
+		switch child := child.(type) {
+		case *ast.Text:
+			builder.Write(child.Literal)
+		case *ast.Code:
+			builder.Write(child.Literal)
+		case *ast.Hardbreak:
+			builder.WriteString("\n")
+		case *ast.Link:
+			text := inline(child)
+			destination := string(child.Destination)
+			if text == "" || text == destination || "mailto:"+text == destination {
+				builder.WriteString(destination)
+			} else {
+				fmt.Fprintf(builder, "%s (%s)", text, destination)
+			}
+		case *ast.Image:
+			if text := inline(child); text != "" {
+				fmt.Fprintf(builder, "%s (%s)", text, child.Destination)
+			} else {
+				builder.Write(child.Destination)
+			}
+		case *ast.HTMLSpan:
+			continue
+		default:
+			if child.AsContainer() != nil {
+				builder.WriteString(inline(child))
+			} else {
+				builder.Write(child.AsLeaf().Literal)
+			}
+		}
+	}
+	return strings.TrimSpace(builder.String())
+}
@@ -0,0 +1,61 @@
+package email
+
+// Theme holds the layout settings and the styles inlined on each element
+type Theme struct {
+	// Background is the color around the email content
+	Background string
+	// ContentBackground is the color behind the email content
+	ContentBackground string
+	// Color is the default text color
+	Color string
+	// FontFamily is the default CSS font family
+	FontFamily string
+	// FontSize is the default CSS font size
+	FontSize string
+	// Width is the maximum width of the email content in pixels
+	Width int
+	// Styles maps element names to the CSS declarations inlined on them, a key
+	// such as "pre code" applies to code elements within pre elements
+	Styles map[string]string
+}
+
+// DefaultTheme returns a new instance of the default email theme
+func DefaultTheme() *Theme {
+	return &Theme{
+		Background:        "#f4f5f7",
+		ContentBackground: "#ffffff",
+		Color:             "#24292f",
+		FontFamily:        "-apple-system, BlinkMacSystemFont, 'Segoe UI', Helvetica, Arial, sans-serif",
+		FontSize:          "16px",
+		Width:             600,
+		Styles: map[string]string{
+			".admonition":       "margin: 0 0 16px; padding: 8px 16px; border-left: 4px solid #d0d7de",
+			".admonition-title": "font-weight: bold",
+			".caution":          "border-left-color: #cf222e",
+			".important":        "border-left-color: #8250df",
+			".note":             "border-left-color: #0969da",
+			".tip":              "border-left-color: #1a7f37",
+			".warning":          "border-left-color: #9a6700",
+			"a":                 "color: #0969da; text-decoration: underline",
+			"blockquote":        "margin: 0 0 16px; padding: 0 16px; color: #57606a; border-left: 4px solid #d0d7de",
+			"code":              "padding: 2px 4px; font-family: Menlo, Consolas, monospace; font-size: 14px; background-color: #f6f8fa",
+			"h1":                "margin: 24px 0 16px; font-size: 28px; line-height: 1.25; font-weight: bold",
+			"h2":                "margin: 24px 0 16px; font-size: 22px; line-height: 1.25; font-weight: bold",
+			"h3":                "margin: 24px 0 16px; font-size: 18px; line-height: 1.25; font-weight: bold",
+			"h4":                "margin: 24px 0 16px; font-size: 16px; line-height: 1.25; font-weight: bold",
+			"h5":                "margin: 24px 0 16px; font-size: 14px; line-height: 1.25; font-weight: bold",
+			"h6":                "margin: 24px 0 16px; font-size: 13px; line-height: 1.25; font-weight: bold; color: #57606a",
+			"hr":                "height: 1px; margin: 24px 0; border: 0; background-color: #d0d7de",
+			"img":               "max-width: 100%; height: auto; border: 0",
+			"li":                "margin: 0 0 4px",
+			"ol":                "margin: 0 0 16px; padding-left: 24px",
+			"p":                 "margin: 0 0 16px",
+			"pre":               "margin: 0 0 16px; padding: 16px; overflow: auto; font-family: Menlo, Consolas, monospace; font-size: 14px; line-height: 1.45; background-color: #f6f8fa",
+			"pre code":          "padding: 0; background-color: transparent",
+			"table":             "margin: 0 0 16px; border-collapse: collapse",
+			"td":                "padding: 6px 12px; border: 1px solid #d0d7de",
+			"th":                "padding: 6px 12px; border: 1px solid #d0d7de; font-weight: bold; background-color: #f6f8fa",
+			"ul":                "margin: 0 0 16px; padding-left: 24px",
+		},
+	}
+}
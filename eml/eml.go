@@ -0,0 +1,274 @@
+// Package eml converts markdown to a MIME email message, with HTML and plain
+// text alternatives and local images attached inline
+package eml
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/evilmonkeyinc/markdownconverter"
+	"github.com/evilmonkeyinc/markdownconverter/email"
+	"github.com/evilmonkeyinc/markdownconverter/http"
+	"github.com/evilmonkeyinc/markdownconverter/links"
+)
+
+const crlf string = "\r\n"
+
+// ErrInvalidAddress is returned when the to or from front matter cannot be
+// parsed as a list of email addresses
+var ErrInvalidAddress error = fmt.Errorf("invalid address")
+
+// New returns a new instance of Converter
+func New() *Converter {
+	return &Converter{
+		Email:        email.New(),
+		AttachImages: true,
+	}
+}
+
+// Converter is the MIME email message Converter implementation
+type Converter struct {
+	// Email is the converter used to render the HTML and plain text parts, its
+	// images are always attached rather than embedded
+	Email *email.Converter
+	// AttachImages will attach local images to the message and reference them
+	// with cid URLs
+	AttachImages bool
+}
+
+// Format returns a unique name for the converter
+func (converter *Converter) Format() string {
+	return "eml"
+}
+
+// Parse will parse the standard markdown and return the converted data
+func (converter *Converter) Parse(markdwn []byte) ([]byte, error) {
+	frontMatter, _ := markdownconverter.FrontMatter(markdwn)
+
+	buffer := &bytes.Buffer{}
+	buffer.WriteString("MIME-Version: 1.0" + crlf)
+	messageID, err := newMessageID()
+	if err != nil {
+		return nil, err
+	}
+	buffer.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + crlf)
+	buffer.WriteString("Message-ID: <" + messageID + ">" + crlf)
+	for _, header := range []string{"From", "To"} {
+		if value, ok := frontMatter[strings.ToLower(header)]; ok {
+			addresses, err := mail.ParseAddressList(value)
+			if err != nil {
+				return nil, fmt.Errorf("%w '%s' %s", ErrInvalidAddress, value, err)
+			}
+			formatted := make([]string, len(addresses))
+			for i, address := range addresses {
+				formatted[i] = address.String()
+			}
+			buffer.WriteString(header + ": " + strings.Join(formatted, ", ") + crlf)
+		}
+	}
+	subject, ok := frontMatter["subject"]
+	if !ok {
+		subject = converter.Email.HTML.Title(markdwn)
+	}
+	if subject != "" {
+		buffer.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", subject) + crlf)
+	}
+
+	attachments := &attachments{}
+	emailConverter := converter.Email
+	if converter.AttachImages {
+		if emailConverter, err = converter.attachImages(attachments); err != nil {
+			return nil, err
+		}
+	}
+	htmlPart, err := emailConverter.Parse(markdwn)
+	if err != nil {
+		return nil, err
+	}
+	textPart, err := converter.Email.Text(markdwn)
+	if err != nil {
+		return nil, err
+	}
+
+	alternative := multipart.NewWriter(buffer)
+	buffer.WriteString("Content-Type: multipart/alternative; boundary=" + alternative.Boundary() + crlf + crlf)
+
+	if err := writeText(alternative, "text/plain", textPart); err != nil {
+		return nil, err
+	}
+	if len(attachments.images) == 0 {
+		if err := writeText(alternative, "text/html", htmlPart); err != nil {
+			return nil, err
+		}
+	} else {
+		related := &bytes.Buffer{}
+		relatedWriter := multipart.NewWriter(related)
+		if err := writeText(relatedWriter, "text/html", htmlPart); err != nil {
+			return nil, err
+		}
+		for _, image := range attachments.images {
+			if err := writeImage(relatedWriter, image); err != nil {
+				return nil, err
+			}
+		}
+		if err := relatedWriter.Close(); err != nil {
+			return nil, err
+		}
+
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", "multipart/related; boundary="+relatedWriter.Boundary()+"; type=\"text/html\"")
+		part, err := alternative.CreatePart(header)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := part.Write(related.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := alternative.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// attachImages returns a copy of the email converter that replaces the
+// destination of each local image with a cid URL and adds it to the
+// attachments. Images are attached before any other rewrite, as resolving them
+// against the base URL would stop them being local.
+func (converter *Converter) attachImages(attachments *attachments) (*email.Converter, error) {
+	htmlConverter := *converter.Email.HTML
+	htmlConverter.EmbedImages = false
+
+	rewriter := htmlConverter.Links
+	if rewriter == nil {
+		rewriter = &links.Rewriter{}
+	}
+	// check the base URL now, as the rewrite function cannot return an error
+	if _, err := rewriter.Destination("", false); err != nil {
+		return nil, err
+	}
+	htmlConverter.Links = &links.Rewriter{
+		Func: func(destination string, image bool) string {
+			if image {
+				if contentID, ok := attachments.add(&htmlConverter, destination); ok {
+					return "cid:" + contentID
+				}
+			}
+			rewritten, _ := rewriter.Destination(destination, image)
+			return rewritten
+		},
+	}
+
+	if htmlConverter.Sanitizer != nil {
+		policy := *htmlConverter.Sanitizer
+		policy.URLSchemes = append(append([]string{}, policy.URLSchemes...), "cid")
+		htmlConverter.Sanitizer = &policy
+	}
+
+	emailConverter := *converter.Email
+	emailConverter.HTML = &htmlConverter
+	return &emailConverter, nil
+}
+
+// newMessageID returns a random message ID
+func newMessageID() (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(random) + "@markdownconverter", nil
+}
+
+// writeText adds a quoted-printable encoded UTF-8 text part
+func writeText(writer *multipart.Writer, contentType string, content []byte) error {
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", contentType+"; charset=utf-8")
+	header.Set("Content-Transfer-Encoding", "quoted-printable")
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return err
+	}
+
+	encoder := quotedprintable.NewWriter(part)
+	if _, err := encoder.Write(content); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// writeImage adds a base64 encoded inline image part
+func writeImage(writer *multipart.Writer, image *image) error {
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", mime.FormatMediaType(image.mimeType, map[string]string{"name": image.filename}))
+	header.Set("Content-Transfer-Encoding", "base64")
+	header.Set("Content-ID", "<"+image.contentID+">")
+	header.Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": image.filename}))
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return err
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(image.data)
+	for len(encoded) > 76 {
+		if _, err := part.Write([]byte(encoded[:76] + crlf)); err != nil {
+			return err
+		}
+		encoded = encoded[76:]
+	}
+	_, err = part.Write([]byte(encoded + crlf))
+	return err
+}
+
+// image is a local image attached to the message
+type image struct {
+	destination string
+	contentID   string
+	filename    string
+	mimeType    string
+	data        []byte
+}
+
+// attachments are the images attached to the message, each image is only
+// attached once no matter how many times it is used
+type attachments struct {
+	images []*image
+}
+
+// add attaches the local image at the destination and returns its content ID,
+// returning false if the destination is not a local image
+func (attachments *attachments) add(converter *http.Converter, destination string) (string, bool) {
+	for _, image := range attachments.images {
+		if image.destination == destination {
+			return image.contentID, true
+		}
+	}
+
+	mimeType, data, ok := converter.LocalImage(destination)
+	if !ok {
+		return "", false
+	}
+	filename := path.Base(destination)
+	if index := strings.IndexAny(filename, "?#"); index >= 0 {
+		filename = filename[:index]
+	}
+	attachments.images = append(attachments.images, &image{
+		destination: destination,
+		contentID:   fmt.Sprintf("image%d@markdownconverter", len(attachments.images)+1),
+		filename:    filename,
+		mimeType:    mimeType,
+		data:        data,
+	})
+	return attachments.images[len(attachments.images)-1].contentID, true
+}
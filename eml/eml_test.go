@@ -0,0 +1,191 @@
+package eml
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evilmonkeyinc/markdownconverter/http"
+	"github.com/evilmonkeyinc/markdownconverter/links"
+	"github.com/stretchr/testify/assert"
+)
+
+type part struct {
+	contentType string
+	header      map[string][]string
+	body        string
+}
+
+// readParts returns the leaf parts of the multipart body in order
+func readParts(t *testing.T, contentType string, body io.Reader) []part {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	assert.Nil(t, err)
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		data, _ := ioutil.ReadAll(body)
+		return []part{{contentType: mediaType, body: string(data)}}
+	}
+
+	parts := make([]part, 0)
+	reader := multipart.NewReader(body, params["boundary"])
+	for {
+		next, err := reader.NextRawPart()
+		if errors.Is(err, io.EOF) {
+			return parts
+		}
+		assert.Nil(t, err)
+
+		var content io.Reader = next
+		if next.Header.Get("Content-Transfer-Encoding") == "quoted-printable" {
+			content = quotedprintable.NewReader(next)
+		}
+		for _, child := range readParts(t, next.Header.Get("Content-Type"), content) {
+			if child.header == nil {
+				child.header = next.Header
+			}
+			parts = append(parts, child)
+		}
+	}
+}
+
+func Test_Converter_Format(t *testing.T) {
+	assert.Equal(t, "eml", New().Format())
+}
+
+func Test_Converter_Parse(t *testing.T) {
+	input := "---\nsubject: Release ünïcode\nto: Team <team@example.com>, other@example.com\nfrom: bot@example.com\n---\n# Heading\n\ntext"
+	actual, err := New().Parse([]byte(input))
+	assert.Nil(t, err)
+
+	message, err := mail.ReadMessage(bytes.NewReader(actual))
+	assert.Nil(t, err)
+	assert.Equal(t, "1.0", message.Header.Get("MIME-Version"))
+	date, err := message.Header.Date()
+	assert.Nil(t, err)
+	assert.WithinDuration(t, time.Now(), date, time.Minute)
+	messageID, err := mail.ParseAddress(message.Header.Get("Message-ID"))
+	assert.Nil(t, err)
+	assert.True(t, strings.HasSuffix(messageID.Address, "@markdownconverter"))
+	assert.Equal(t, "\"Team\" <team@example.com>, <other@example.com>", message.Header.Get("To"))
+	assert.Equal(t, "<bot@example.com>", message.Header.Get("From"))
+	subject, _ := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+	assert.Equal(t, "Release ünïcode", subject)
+	assert.True(t, strings.HasPrefix(message.Header.Get("Content-Type"), "multipart/alternative;"))
+
+	parts := readParts(t, message.Header.Get("Content-Type"), message.Body)
+	assert.Len(t, parts, 2)
+	assert.Equal(t, "text/plain", parts[0].contentType)
+	assert.Equal(t, "Heading\r\n=======\r\n\r\ntext\r\n", parts[0].body)
+	assert.Equal(t, "text/html", parts[1].contentType)
+	assert.Contains(t, parts[1].body, "<title>Heading</title>")
+	assert.Contains(t, parts[1].body, ">text</p>")
+}
+
+func Test_Converter_Parse_Subject(t *testing.T) {
+	actual, err := New().Parse([]byte("text\n\n## Title *heading*"))
+	assert.Nil(t, err)
+
+	message, err := mail.ReadMessage(bytes.NewReader(actual))
+	assert.Nil(t, err)
+	assert.Equal(t, "Title heading", message.Header.Get("Subject"))
+	assert.Equal(t, "", message.Header.Get("To"))
+}
+
+func Test_Converter_Parse_InvalidAddress(t *testing.T) {
+	actual, err := New().Parse([]byte("---\nto: not an address\n---\ntext"))
+	assert.Nil(t, actual)
+	assert.ErrorIs(t, err, ErrInvalidAddress)
+}
+
+func Test_Converter_Parse_AttachImagesDisabled(t *testing.T) {
+	converter := New()
+	converter.AttachImages = false
+	converter.Email.HTML.ImageDir = "../http/testdata"
+
+	actual, err := converter.Parse([]byte("![one](pixel.png)"))
+	assert.Nil(t, err)
+
+	message, err := mail.ReadMessage(bytes.NewReader(actual))
+	assert.Nil(t, err)
+	parts := readParts(t, message.Header.Get("Content-Type"), message.Body)
+	assert.Len(t, parts, 2)
+	assert.Contains(t, parts[1].body, "<img src=\"pixel.png\" alt=\"one\"")
+}
+
+func Test_Converter_Parse_Images(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		sanitize bool
+	}{
+		{
+			name: "default",
+		},
+		{
+			name:     "sanitize",
+			sanitize: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			converter := New()
+			converter.Email.HTML.ImageDir = "../http/testdata"
+			if test.sanitize {
+				converter.Email.HTML.Sanitizer = http.DefaultPolicy()
+			}
+
+			actual, err := converter.Parse([]byte("![one](pixel.png) ![two](pixel.png) ![svg](icon.svg) ![remote](https://example.com/a.png) ![missing](nope.png)"))
+			assert.Nil(t, err)
+
+			message, err := mail.ReadMessage(bytes.NewReader(actual))
+			assert.Nil(t, err)
+			parts := readParts(t, message.Header.Get("Content-Type"), message.Body)
+			assert.Len(t, parts, 4)
+
+			assert.Equal(t, "text/plain", parts[0].contentType)
+			assert.Equal(t, "text/html", parts[1].contentType)
+			assert.Contains(t, parts[1].body, "<img src=\"cid:image1@markdownconverter\" alt=\"one\"")
+			assert.Contains(t, parts[1].body, "<img src=\"cid:image1@markdownconverter\" alt=\"two\"")
+			assert.Contains(t, parts[1].body, "<img src=\"cid:image2@markdownconverter\" alt=\"svg\"")
+			assert.Contains(t, parts[1].body, "<img src=\"https://example.com/a.png\" alt=\"remote\"")
+			assert.Contains(t, parts[1].body, "<img src=\"nope.png\" alt=\"missing\"")
+
+			assert.Equal(t, "image/png", parts[2].contentType)
+			assert.Equal(t, "<image1@markdownconverter>", parts[2].header["Content-Id"][0])
+			assert.Equal(t, "inline; filename=pixel.png", parts[2].header["Content-Disposition"][0])
+			assert.Equal(t, "image/svg+xml", parts[3].contentType)
+			assert.Equal(t, "<image2@markdownconverter>", parts[3].header["Content-Id"][0])
+		})
+	}
+}
+
+func Test_Converter_Parse_BaseURL(t *testing.T) {
+	converter := New()
+	converter.Email.HTML.ImageDir = "../http/testdata"
+	converter.Email.HTML.Links = &links.Rewriter{BaseURL: "https://example.com/docs/", MarkdownToHTML: true}
+
+	actual, err := converter.Parse([]byte("![one](pixel.png) ![missing](nope.png) [setup](setup.md)"))
+	assert.Nil(t, err)
+
+	message, err := mail.ReadMessage(bytes.NewReader(actual))
+	assert.Nil(t, err)
+	parts := readParts(t, message.Header.Get("Content-Type"), message.Body)
+	assert.Len(t, parts, 3)
+	assert.Contains(t, parts[1].body, "<img src=\"cid:image1@markdownconverter\" alt=\"one\"")
+	assert.Contains(t, parts[1].body, "<img src=\"https://example.com/docs/nope.png\" alt=\"missing\"")
+	assert.Contains(t, parts[1].body, "<a href=\"https://example.com/docs/setup.html\"")
+	assert.Equal(t, "image/png", parts[2].contentType)
+
+	converter.Email.HTML.Links.BaseURL = "invalid"
+	actual, err = converter.Parse([]byte("![one](pixel.png)"))
+	assert.Nil(t, actual)
+	assert.ErrorIs(t, err, links.ErrInvalidBaseURL)
+}
@@ -0,0 +1,53 @@
+package markdownconverter
+
+import (
+	"bytes"
+	"strings"
+)
+
+const frontMatterDelimiter string = "---"
+
+// FrontMatter splits the front matter block, delimited by "---" lines at the
+// very start of the markdown, from the body and returns its simple "key: value"
+// pairs with lower case keys. If there is no front matter block the markdown is
+// returned unchanged.
+func FrontMatter(markdown []byte) (map[string]string, []byte) {
+	values := make(map[string]string)
+
+	data := bytes.ReplaceAll(markdown, []byte("\r\n"), []byte("\n"))
+	if !bytes.HasPrefix(data, []byte(frontMatterDelimiter+"\n")) {
+		return values, markdown
+	}
+
+	lines := strings.Split(string(data), "\n")
+	for index, line := range lines[1:] {
+		line = strings.TrimSpace(line)
+		if line == frontMatterDelimiter {
+			body := strings.Join(lines[index+2:], "\n")
+			return values, []byte(body)
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		separator := strings.Index(line, ":")
+		if separator < 1 {
+			// not front matter, so leave the markdown as it is
+			return make(map[string]string), markdown
+		}
+		key := strings.ToLower(strings.TrimSpace(line[:separator]))
+		values[key] = unquote(strings.TrimSpace(line[separator+1:]))
+	}
+
+	return make(map[string]string), markdown
+}
+
+func unquote(value string) string {
+	if len(value) < 2 {
+		return value
+	}
+	if (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
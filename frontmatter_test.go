@@ -0,0 +1,74 @@
+package markdownconverter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FrontMatter(t *testing.T) {
+
+	tests := []struct {
+		name           string
+		input          string
+		expectedValues map[string]string
+		expectedBody   string
+	}{
+		{
+			name:           "no_front_matter",
+			input:          "# Heading\n\ntext",
+			expectedValues: map[string]string{},
+			expectedBody:   "# Heading\n\ntext",
+		},
+		{
+			name:           "front_matter",
+			input:          "---\ntitle: Release Notes\nSubject: \"Quoted: value\"\nto: 'team@example.com'\n---\n# Heading",
+			expectedValues: map[string]string{"title": "Release Notes", "subject": "Quoted: value", "to": "team@example.com"},
+			expectedBody:   "# Heading",
+		},
+		{
+			name:           "comments_and_blank_lines",
+			input:          "---\n# comment\n\ntitle: Title\n---\ntext",
+			expectedValues: map[string]string{"title": "Title"},
+			expectedBody:   "text",
+		},
+		{
+			name:           "windows_line_endings",
+			input:          "---\r\ntitle: Title\r\n---\r\ntext",
+			expectedValues: map[string]string{"title": "Title"},
+			expectedBody:   "text",
+		},
+		{
+			name:           "empty_front_matter",
+			input:          "---\n---\ntext",
+			expectedValues: map[string]string{},
+			expectedBody:   "text",
+		},
+		{
+			name:           "unclosed",
+			input:          "---\ntitle: Title\ntext",
+			expectedValues: map[string]string{},
+			expectedBody:   "---\ntitle: Title\ntext",
+		},
+		{
+			name:           "horizontal_rule",
+			input:          "---\nsome text\n---\n",
+			expectedValues: map[string]string{},
+			expectedBody:   "---\nsome text\n---\n",
+		},
+		{
+			name:           "not_at_start",
+			input:          "text\n---\ntitle: Title\n---\n",
+			expectedValues: map[string]string{},
+			expectedBody:   "text\n---\ntitle: Title\n---\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			values, body := FrontMatter([]byte(test.input))
+			assert.Equal(t, test.expectedValues, values)
+			assert.Equal(t, test.expectedBody, string(body))
+		})
+	}
+}
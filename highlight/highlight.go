@@ -0,0 +1,261 @@
+// Package highlight provides simple syntax highlighting for code blocks, with
+// lexers for common languages and themes that can be applied with CSS classes
+// or inline styles
+package highlight
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gomarkdown/markdown/html"
+)
+
+// ErrUnknownTheme is returned when a theme name is not recognised
+var ErrUnknownTheme error = fmt.Errorf("unknown theme")
+
+// TokenType identifies what a piece of code represents
+type TokenType int
+
+// The token types produced by the lexers
+const (
+	Text TokenType = iota
+	Comment
+	Keyword
+	Builtin
+	String
+	Number
+	Literal
+	Operator
+	Punctuation
+	Variable
+	Property
+	Inserted
+	Deleted
+	Heading
+	Meta
+)
+
+var tokenTypeNames map[TokenType]string = map[TokenType]string{
+	Text:        "text",
+	Comment:     "comment",
+	Keyword:     "keyword",
+	Builtin:     "builtin",
+	String:      "string",
+	Number:      "number",
+	Literal:     "literal",
+	Operator:    "operator",
+	Punctuation: "punctuation",
+	Variable:    "variable",
+	Property:    "property",
+	Inserted:    "inserted",
+	Deleted:     "deleted",
+	Heading:     "heading",
+	Meta:        "meta",
+}
+
+// String returns the name of the token type
+func (tokenType TokenType) String() string {
+	return tokenTypeNames[tokenType]
+}
+
+// Class returns the CSS class used for the token type
+func (tokenType TokenType) Class() string {
+	return "hl-" + tokenType.String()
+}
+
+// Token is a piece of code and its type
+type Token struct {
+	Type  TokenType
+	Value string
+}
+
+// Lexer splits code into tokens
+type Lexer interface {
+	// Tokenize returns the tokens that make up the code
+	Tokenize(code string) []Token
+}
+
+var lexers map[string]Lexer = make(map[string]Lexer)
+
+// register adds the lexer under each of the language names
+func register(lexer Lexer, names ...string) {
+	for _, name := range names {
+		lexers[name] = lexer
+	}
+}
+
+// Get returns the lexer for the language, returning false if the language is
+// not supported
+func Get(language string) (Lexer, bool) {
+	lexer, ok := lexers[strings.ToLower(language)]
+	return lexer, ok
+}
+
+// Languages returns the sorted names of the supported languages
+func Languages() []string {
+	names := make([]string, 0, len(lexers))
+	for name := range lexers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Tokenize returns the tokens that make up the code, if the language is not
+// supported the code is returned as a single Text token
+func Tokenize(language, code string) []Token {
+	if lexer, ok := Get(language); ok {
+		return lexer.Tokenize(code)
+	}
+	return []Token{{Type: Text, Value: code}}
+}
+
+// HTML writes the tokens as escaped HTML, wrapping each token that is not
+// plain text in a span. If the theme is nil the spans are given the token
+// type class, otherwise the theme style is added inline.
+func HTML(w io.Writer, tokens []Token, theme *Theme) {
+	for _, token := range tokens {
+		if token.Type == Text {
+			html.EscapeHTML(w, []byte(token.Value))
+			continue
+		}
+
+		if theme == nil {
+			fmt.Fprintf(w, `<span class="%s">`, token.Type.Class())
+		} else if style := theme.Styles[token.Type]; style != "" {
+			fmt.Fprintf(w, `<span style="%s">`, style)
+		} else {
+			html.EscapeHTML(w, []byte(token.Value))
+			continue
+		}
+		html.EscapeHTML(w, []byte(token.Value))
+		io.WriteString(w, "</span>")
+	}
+}
+
+// rule matches a token at the current position. When types has more than one
+// entry each is used for the matching capture group, and any text not in a
+// group is plain text.
+type rule struct {
+	pattern   *regexp.Regexp
+	types     []TokenType
+	classify  func(value string) TokenType
+	lineStart bool
+}
+
+func newRule(pattern string, types ...TokenType) rule {
+	return rule{
+		pattern: regexp.MustCompile(`\A(?:` + pattern + `)`),
+		types:   types,
+	}
+}
+
+// newWordRule returns a rule that uses the function to decide the token type
+// of the matched value
+func newWordRule(pattern string, classify func(value string) TokenType) rule {
+	return rule{
+		pattern:  regexp.MustCompile(`\A(?:` + pattern + `)`),
+		classify: classify,
+	}
+}
+
+// atLineStart returns a copy of the rule that only matches at the start of a line
+func (rule rule) atLineStart() rule {
+	rule.lineStart = true
+	return rule
+}
+
+// words returns a classify function that looks up the value in the map,
+// returning the fallback type for values that are not found
+func words(values map[string]TokenType, fallback TokenType, caseInsensitive bool) func(string) TokenType {
+	return func(value string) TokenType {
+		if caseInsensitive {
+			value = strings.ToLower(value)
+		}
+		if tokenType, ok := values[value]; ok {
+			return tokenType
+		}
+		return fallback
+	}
+}
+
+// wordSet returns a map with each of the space separated words mapped to the type
+func wordSet(tokenType TokenType, values string, into map[string]TokenType) map[string]TokenType {
+	if into == nil {
+		into = make(map[string]TokenType)
+	}
+	for _, value := range strings.Fields(values) {
+		into[value] = tokenType
+	}
+	return into
+}
+
+// ruleLexer is a Lexer that tries each rule in order at the current position,
+// treating any character that no rule matches as plain text
+type ruleLexer struct {
+	rules []rule
+}
+
+// Tokenize returns the tokens that make up the code
+func (lexer *ruleLexer) Tokenize(code string) []Token {
+	tokens := make([]Token, 0)
+	emit := func(tokenType TokenType, value string) {
+		if value == "" {
+			return
+		}
+		if last := len(tokens) - 1; last >= 0 && tokens[last].Type == tokenType {
+			tokens[last].Value += value
+			return
+		}
+		tokens = append(tokens, Token{Type: tokenType, Value: value})
+	}
+
+	position := 0
+	for position < len(code) {
+		matched := false
+		for _, rule := range lexer.rules {
+			if rule.lineStart && position > 0 && code[position-1] != '\n' {
+				continue
+			}
+			match := rule.pattern.FindStringSubmatchIndex(code[position:])
+			if match == nil || match[1] == 0 {
+				continue
+			}
+
+			value := code[position : position+match[1]]
+			switch {
+			case rule.classify != nil:
+				emit(rule.classify(value), value)
+			case len(rule.types) == 1:
+				emit(rule.types[0], value)
+			default:
+				end := 0
+				for group, tokenType := range rule.types {
+					start, stop := match[(group+1)*2], match[(group+1)*2+1]
+					if start < 0 {
+						continue
+					}
+					emit(Text, value[end:start])
+					emit(tokenType, value[start:stop])
+					end = stop
+				}
+				emit(Text, value[end:])
+			}
+
+			position += match[1]
+			matched = true
+			break
+		}
+
+		if !matched {
+			_, size := utf8.DecodeRuneInString(code[position:])
+			emit(Text, code[position:position+size])
+			position += size
+		}
+	}
+	return tokens
+}
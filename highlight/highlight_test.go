@@ -0,0 +1,159 @@
+package highlight
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tokenize(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		language string
+		input    string
+		expected []Token
+	}{
+		{
+			name:     "unknown_language",
+			language: "brainfuck",
+			input:    "++[>+<-]",
+			expected: []Token{{Text, "++[>+<-]"}},
+		},
+		{
+			name:     "go",
+			language: "go",
+			input:    "// Add numbers\nfunc add(x int) int {\n\treturn x + 0x1F // done\n}",
+			expected: []Token{
+				{Comment, "// Add numbers"}, {Text, "\n"}, {Keyword, "func"}, {Text, " add"}, {Punctuation, "("}, {Text, "x "}, {Builtin, "int"}, {Punctuation, ")"}, {Text, " "}, {Builtin, "int"}, {Text, " "}, {Punctuation, "{"},
+				{Text, "\n\t"}, {Keyword, "return"}, {Text, " x "}, {Operator, "+"}, {Text, " "}, {Number, "0x1F"}, {Text, " "}, {Comment, "// done"}, {Text, "\n"}, {Punctuation, "}"},
+			},
+		},
+		{
+			name:     "go_strings",
+			language: "golang",
+			input:    "s := \"a\\\"b\" + `raw` + string('c') + nil",
+			expected: []Token{
+				{Text, "s "}, {Operator, ":="}, {Text, " "}, {String, "\"a\\\"b\""}, {Text, " "}, {Operator, "+"}, {Text, " "}, {String, "`raw`"}, {Text, " "}, {Operator, "+"}, {Text, " "}, {Builtin, "string"}, {Punctuation, "("}, {String, "'c'"}, {Punctuation, ")"}, {Text, " "}, {Operator, "+"}, {Text, " "}, {Literal, "nil"},
+			},
+		},
+		{
+			name:     "go_unterminated_comment",
+			language: "go",
+			input:    "/* open",
+			expected: []Token{{Comment, "/* open"}},
+		},
+		{
+			name:     "shell",
+			language: "bash",
+			input:    "# comment\nexport NAME=value\nif [ -f \"$FILE\" ]; then echo ${HOME} | grep x > /dev/null; fi",
+			expected: []Token{
+				{Comment, "# comment"}, {Text, "\n"}, {Keyword, "export"}, {Text, " "}, {Variable, "NAME"}, {Operator, "="}, {Text, "value\n"},
+				{Keyword, "if"}, {Text, " [ -f "}, {String, "\"$FILE\""}, {Text, " ]"}, {Operator, ";"}, {Text, " "}, {Keyword, "then"}, {Text, " "}, {Builtin, "echo"}, {Text, " "}, {Variable, "${HOME}"}, {Text, " "}, {Operator, "|"}, {Text, " grep x "}, {Operator, ">"}, {Text, " /dev/null"}, {Operator, ";"}, {Text, " "}, {Keyword, "fi"},
+			},
+		},
+		{
+			name:     "json",
+			language: "json",
+			input:    "{\"name\": \"value\", \"count\": -1.5e3, \"ok\": true, \"list\": [null]}",
+			expected: []Token{
+				{Punctuation, "{"}, {Property, "\"name\""}, {Punctuation, ":"}, {Text, " "}, {String, "\"value\""}, {Punctuation, ","}, {Text, " "},
+				{Property, "\"count\""}, {Punctuation, ":"}, {Text, " "}, {Number, "-1.5e3"}, {Punctuation, ","}, {Text, " "},
+				{Property, "\"ok\""}, {Punctuation, ":"}, {Text, " "}, {Literal, "true"}, {Punctuation, ","}, {Text, " "},
+				{Property, "\"list\""}, {Punctuation, ":"}, {Text, " "}, {Punctuation, "["}, {Literal, "null"}, {Punctuation, "]}"},
+			},
+		},
+		{
+			name:     "yaml",
+			language: "yaml",
+			input:    "---\n# comment\nname: app\nversion: 1.2.3\ncount: 3\nitems:\n  - one\n  - key: 'value'\n    enabled: [true, no]\nref: *anchor",
+			expected: []Token{
+				{Meta, "---\n"}, {Comment, "# comment"}, {Text, "\n"},
+				{Property, "name"}, {Punctuation, ":"}, {Text, " app\n"},
+				{Property, "version"}, {Punctuation, ":"}, {Text, " 1.2.3\n"},
+				{Property, "count"}, {Punctuation, ":"}, {Text, " "}, {Number, "3"}, {Text, "\n"},
+				{Property, "items"}, {Punctuation, ":"}, {Text, "\n  "}, {Punctuation, "-"}, {Text, " one\n  "},
+				{Punctuation, "-"}, {Text, " "}, {Property, "key"}, {Punctuation, ":"}, {Text, " "}, {String, "'value'"}, {Text, "\n    "},
+				{Property, "enabled"}, {Punctuation, ":"}, {Text, " "}, {Punctuation, "["}, {Literal, "true"}, {Punctuation, ","}, {Text, " "}, {Literal, "no"}, {Punctuation, "]"}, {Text, "\n"},
+				{Property, "ref"}, {Punctuation, ":"}, {Text, " "}, {Variable, "*anchor"},
+			},
+		},
+		{
+			name:     "sql",
+			language: "SQL",
+			input:    "select id FROM users WHERE name = 'O''Brien' AND age > $1; -- comment",
+			expected: []Token{
+				{Keyword, "select"}, {Text, " id "}, {Keyword, "FROM"}, {Text, " users "}, {Keyword, "WHERE"}, {Text, " name "}, {Operator, "="}, {Text, " "}, {String, "'O''Brien'"}, {Text, " "},
+				{Keyword, "AND"}, {Text, " age "}, {Operator, ">"}, {Text, " "}, {Variable, "$1"}, {Punctuation, ";"}, {Text, " "}, {Comment, "-- comment"},
+			},
+		},
+		{
+			name:     "diff",
+			language: "diff",
+			input:    "--- a/file\n+++ b/file\n@@ -1,2 +1,2 @@\n context\n-old\n+new",
+			expected: []Token{
+				{Heading, "--- a/file"}, {Text, "\n"}, {Heading, "+++ b/file"}, {Text, "\n"}, {Meta, "@@ -1,2 +1,2 @@"}, {Text, "\n context\n"}, {Deleted, "-old"}, {Text, "\n"}, {Inserted, "+new"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := Tokenize(test.language, test.input)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func Test_HTML(t *testing.T) {
+	tokens := []Token{{Keyword, "if"}, {Text, " a < b "}, {String, "\"&\""}}
+
+	buffer := &bytes.Buffer{}
+	HTML(buffer, tokens, nil)
+	assert.Equal(t, "<span class=\"hl-keyword\">if</span> a &lt; b <span class=\"hl-string\">&quot;&amp;&quot;</span>", buffer.String())
+
+	buffer.Reset()
+	HTML(buffer, tokens, Themes["github"])
+	assert.Equal(t, "<span style=\"color: #d73a49\">if</span> a &lt; b <span style=\"color: #032f62\">&quot;&amp;&quot;</span>", buffer.String())
+
+	buffer.Reset()
+	HTML(buffer, tokens, &Theme{Styles: map[TokenType]string{Keyword: "font-weight: bold"}})
+	assert.Equal(t, "<span style=\"font-weight: bold\">if</span> a &lt; b &quot;&amp;&quot;", buffer.String())
+}
+
+func Test_GetTheme(t *testing.T) {
+	theme, err := GetTheme("")
+	assert.Nil(t, err)
+	assert.Equal(t, Themes[DefaultTheme], theme)
+
+	theme, err = GetTheme("monokai")
+	assert.Nil(t, err)
+	assert.Equal(t, Themes["monokai"], theme)
+
+	_, err = GetTheme("invalid")
+	assert.ErrorIs(t, err, ErrUnknownTheme)
+}
+
+func Test_Theme_CSS(t *testing.T) {
+	theme := &Theme{
+		Background: "#fff",
+		Foreground: "#000",
+		Styles: map[TokenType]string{
+			String:  "color: blue",
+			Comment: "color: grey",
+		},
+	}
+	assert.Equal(t, ".highlight { background-color: #fff; color: #000; }\n.highlight .hl-comment { color: grey; }\n.highlight .hl-string { color: blue; }\n", theme.CSS())
+}
+
+func Test_Get(t *testing.T) {
+	for _, language := range Languages() {
+		_, ok := Get(language)
+		assert.True(t, ok, language)
+	}
+	_, ok := Get("Go")
+	assert.True(t, ok)
+	_, ok = Get("unknown")
+	assert.False(t, ok)
+}
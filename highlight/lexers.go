@@ -0,0 +1,121 @@
+package highlight
+
+func init() {
+	register(goLexer(), "go", "golang")
+	register(shellLexer(), "shell", "sh", "bash", "zsh", "console")
+	register(jsonLexer(), "json")
+	register(yamlLexer(), "yaml", "yml")
+	register(sqlLexer(), "sql")
+	register(diffLexer(), "diff", "patch")
+}
+
+func goLexer() Lexer {
+	goWords := wordSet(Keyword, "break case chan const continue default defer else fallthrough for func go goto if import interface map package range return select struct switch type var", nil)
+	goWords = wordSet(Builtin, "any bool byte comparable complex64 complex128 error float32 float64 int int8 int16 int32 int64 rune string uint uint8 uint16 uint32 uint64 uintptr append cap close complex copy delete imag len make new panic print println real recover", goWords)
+	goWords = wordSet(Literal, "true false nil iota", goWords)
+
+	return &ruleLexer{
+		rules: []rule{
+			newRule(`\s+`, Text),
+			newRule(`//[^\n]*`, Comment),
+			newRule(`/\*[\s\S]*?(?:\*/|\z)`, Comment),
+			newRule("`[^`]*`?", String),
+			newRule(`"(?:\\.|[^"\\\n])*"?`, String),
+			newRule(`'(?:\\.|[^'\\\n])+'`, String),
+			newRule(`0[xX][0-9a-fA-F_]+|0[bB][01_]+|0[oO][0-7_]+|(?:\d[\d_]*(?:\.[\d_]*)?|\.\d[\d_]*)(?:[eE][+-]?\d+)?i?`, Number),
+			newWordRule(`[\pL_][\pL\pN_]*`, words(goWords, Text, false)),
+			newRule(`[-+*/%&|^<>=!:.~]+`, Operator),
+			newRule(`[{}()\[\],;]`, Punctuation),
+		},
+	}
+}
+
+func shellLexer() Lexer {
+	shellWords := wordSet(Keyword, "if then else elif fi for while until do done case esac in function select time return exit break continue local export readonly declare unset shift source", nil)
+	shellWords = wordSet(Builtin, "alias bg cd command echo eval exec fg getopts hash jobs kill printf pwd read set test trap true false type ulimit umask unalias wait", shellWords)
+
+	return &ruleLexer{
+		rules: []rule{
+			newRule(`\s+`, Text),
+			newRule(`#[^\n]*`, Comment),
+			newRule(`"(?:\\.|[^"\\])*"?`, String),
+			newRule(`'[^']*'?`, String),
+			newRule(`\$(?:\{[^}\n]*\}|[A-Za-z_][A-Za-z0-9_]*|[0-9@#?$!*-])`, Variable),
+			newRule(`([A-Za-z_][A-Za-z0-9_]*)(=)`, Variable, Operator),
+			newRule(`\d+\b`, Number),
+			newWordRule(`[A-Za-z_][\w-]*\b`, words(shellWords, Text, false)),
+			newRule(`\$\(|[|&;<>()]+`, Operator),
+			newRule(`[^\s#"'$|&;<>(){}\[\]]+`, Text),
+		},
+	}
+}
+
+func jsonLexer() Lexer {
+	return &ruleLexer{
+		rules: []rule{
+			newRule(`\s+`, Text),
+			newRule(`("(?:\\.|[^"\\\n])*")(\s*)(:)`, Property, Text, Punctuation),
+			newRule(`"(?:\\.|[^"\\\n])*"?`, String),
+			newRule(`-?(?:0|[1-9]\d*)(?:\.\d+)?(?:[eE][+-]?\d+)?`, Number),
+			newRule(`(?:true|false|null)\b`, Literal),
+			newRule(`[{}\[\],:]`, Punctuation),
+		},
+	}
+}
+
+// yamlValueEnd matches the end of a plain YAML value, so that the start of a
+// longer value is not mistaken for a number or literal
+const yamlValueEnd string = `(?:[ \t]*([,\]}])|[ \t]*(?:\n|\z)|[ \t]+)`
+
+func yamlLexer() Lexer {
+	return &ruleLexer{
+		rules: []rule{
+			newRule(`(?:---|\.\.\.)[ \t]*(?:\n|\z)`, Meta).atLineStart(),
+			newRule(`([ \t]*)(?:(-)[ \t]+)?([^\s#'"{}\[\],&*!|>%@`+"`"+`-][^\n#:]*?|"(?:\\.|[^"\\\n])*"|'[^'\n]*')[ \t]*(:)(?:[ \t]|\n|\z)`, Text, Punctuation, Property, Punctuation).atLineStart(),
+			newRule(`([ \t]*)(-)(?:[ \t]|\n|\z)`, Text, Punctuation).atLineStart(),
+			newRule(`[ \t]+|\n`, Text),
+			newRule(`#[^\n]*`, Comment),
+			newRule(`"(?:\\.|[^"\\\n])*"?|'(?:''|[^'\n])*'?`, String),
+			newRule(`[&*][\w-]+`, Variable),
+			newRule(`![\w!/.-]*`, Builtin),
+			newRule(`(?:[|>][-+0-9]*)[ \t]*(?:\n|\z)`, Operator),
+			newRule(`((?i:true|false|null|yes|no|on|off)|~)`+yamlValueEnd, Literal, Punctuation),
+			newRule(`([-+]?(?:\d[\d_]*(?:\.\d*)?(?:[eE][-+]?\d+)?|\.inf|\.nan))`+yamlValueEnd, Number, Punctuation),
+			newRule(`[{}\[\],]`, Punctuation),
+			newRule(`[^\s#,\[\]{}][^\n#,\[\]{}]*`, Text),
+		},
+	}
+}
+
+func sqlLexer() Lexer {
+	sqlWords := wordSet(Keyword, "add all alter and as asc begin between by case check column commit constraint create cross database default delete desc distinct drop else end exists foreign from full group having if in index inner insert intersect into is join key left like limit not offset on or order outer primary references replace returning right rollback select set table then transaction truncate union unique update using values view when where with", nil)
+	sqlWords = wordSet(Builtin, "bigint binary blob boolean char date datetime decimal double float int integer json numeric real serial smallint text time timestamp uuid varchar avg coalesce count lower max min now sum upper", sqlWords)
+	sqlWords = wordSet(Literal, "null true false", sqlWords)
+
+	return &ruleLexer{
+		rules: []rule{
+			newRule(`\s+`, Text),
+			newRule(`--[^\n]*`, Comment),
+			newRule(`/\*[\s\S]*?(?:\*/|\z)`, Comment),
+			newRule(`'(?:''|[^'])*'?`, String),
+			newRule(`"(?:""|[^"])*"|`+"`[^`]*`", Property),
+			newRule(`\d+(?:\.\d+)?(?:[eE][-+]?\d+)?`, Number),
+			newRule(`[@:$][A-Za-z_]\w*|\$\d+|\?`, Variable),
+			newWordRule(`[A-Za-z_][\w$]*`, words(sqlWords, Text, true)),
+			newRule(`[-+*/%<>=!|&^~]+`, Operator),
+			newRule(`[(),;.]`, Punctuation),
+		},
+	}
+}
+
+func diffLexer() Lexer {
+	return &ruleLexer{
+		rules: []rule{
+			newRule(`(?:diff|index|\+\+\+|---)[^\n]*`, Heading).atLineStart(),
+			newRule(`@@[^\n]*`, Meta).atLineStart(),
+			newRule(`\+[^\n]*`, Inserted).atLineStart(),
+			newRule(`-[^\n]*`, Deleted).atLineStart(),
+			newRule(`[^\n]+`, Text),
+		},
+	}
+}
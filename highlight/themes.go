@@ -0,0 +1,128 @@
+package highlight
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultTheme is the name of the theme used when none is chosen
+const DefaultTheme string = "github"
+
+// Theme holds the colors and CSS declarations used for each token type
+type Theme struct {
+	// Background is the background color of the code block
+	Background string
+	// Foreground is the color of plain text
+	Foreground string
+	// Styles maps the token types to the CSS declarations used to style them
+	Styles map[TokenType]string
+}
+
+// Themes holds the available themes by name
+var Themes map[string]*Theme = map[string]*Theme{
+	"github": {
+		Background: "#f6f8fa",
+		Foreground: "#24292e",
+		Styles: map[TokenType]string{
+			Comment:     "color: #6a737d; font-style: italic",
+			Keyword:     "color: #d73a49",
+			Builtin:     "color: #6f42c1",
+			String:      "color: #032f62",
+			Number:      "color: #005cc5",
+			Literal:     "color: #005cc5",
+			Operator:    "color: #d73a49",
+			Punctuation: "color: #24292e",
+			Variable:    "color: #e36209",
+			Property:    "color: #005cc5",
+			Inserted:    "color: #22863a; background-color: #f0fff4",
+			Deleted:     "color: #b31d28; background-color: #ffeef0",
+			Heading:     "color: #24292e; font-weight: bold",
+			Meta:        "color: #6f42c1; font-weight: bold",
+		},
+	},
+	"github-dark": {
+		Background: "#0d1117",
+		Foreground: "#c9d1d9",
+		Styles: map[TokenType]string{
+			Comment:     "color: #8b949e; font-style: italic",
+			Keyword:     "color: #ff7b72",
+			Builtin:     "color: #d2a8ff",
+			String:      "color: #a5d6ff",
+			Number:      "color: #79c0ff",
+			Literal:     "color: #79c0ff",
+			Operator:    "color: #ff7b72",
+			Punctuation: "color: #c9d1d9",
+			Variable:    "color: #ffa657",
+			Property:    "color: #79c0ff",
+			Inserted:    "color: #aff5b4; background-color: #033a16",
+			Deleted:     "color: #ffdcd7; background-color: #67060c",
+			Heading:     "color: #c9d1d9; font-weight: bold",
+			Meta:        "color: #d2a8ff; font-weight: bold",
+		},
+	},
+	"monokai": {
+		Background: "#272822",
+		Foreground: "#f8f8f2",
+		Styles: map[TokenType]string{
+			Comment:     "color: #75715e; font-style: italic",
+			Keyword:     "color: #f92672",
+			Builtin:     "color: #66d9ef",
+			String:      "color: #e6db74",
+			Number:      "color: #ae81ff",
+			Literal:     "color: #ae81ff",
+			Operator:    "color: #f92672",
+			Punctuation: "color: #f8f8f2",
+			Variable:    "color: #fd971f",
+			Property:    "color: #a6e22e",
+			Inserted:    "color: #a6e22e",
+			Deleted:     "color: #f92672",
+			Heading:     "color: #f8f8f2; font-weight: bold",
+			Meta:        "color: #66d9ef; font-weight: bold",
+		},
+	},
+}
+
+// GetTheme returns the named theme, or the default theme if the name is empty
+func GetTheme(name string) (*Theme, error) {
+	if name == "" {
+		name = DefaultTheme
+	}
+	theme, ok := Themes[name]
+	if !ok {
+		return nil, fmt.Errorf("%w '%s', expected: (%s)", ErrUnknownTheme, name, strings.Join(ThemeNames(), ", "))
+	}
+	return theme, nil
+}
+
+// ThemeNames returns the sorted names of the available themes
+func ThemeNames() []string {
+	names := make([]string, 0, len(Themes))
+	for name := range Themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PreStyle returns the CSS declarations for the element containing the code
+func (theme *Theme) PreStyle() string {
+	return fmt.Sprintf("background-color: %s; color: %s", theme.Background, theme.Foreground)
+}
+
+// CSS returns a stylesheet that styles the token classes used by HTML within
+// an element with the "highlight" class
+func (theme *Theme) CSS() string {
+	builder := &strings.Builder{}
+	fmt.Fprintf(builder, ".highlight { %s; }\n", theme.PreStyle())
+
+	types := make([]int, 0, len(theme.Styles))
+	for tokenType := range theme.Styles {
+		types = append(types, int(tokenType))
+	}
+	sort.Ints(types)
+	for _, tokenType := range types {
+		fmt.Fprintf(builder, ".highlight .%s { %s; }\n", TokenType(tokenType).Class(), theme.Styles[TokenType(tokenType)])
+	}
+	return builder.String()
+}
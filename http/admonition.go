@@ -0,0 +1,24 @@
+package http
+
+import (
+	"io"
+
+	"github.com/evilmonkeyinc/markdownconverter"
+	"github.com/gomarkdown/markdown/html"
+)
+
+// renderAdmonition writes the admonition as a div with the admonition classes,
+// starting with a title paragraph
+func renderAdmonition(w io.Writer, renderer *html.Renderer, admonition *markdownconverter.Admonition, entering bool) {
+	if !entering {
+		renderer.Outs(w, "</div>")
+		renderer.CR(w)
+		return
+	}
+
+	renderer.CR(w)
+	renderer.Outs(w, `<div class="admonition `+admonition.Type+`">`)
+	renderer.CR(w)
+	renderer.Outs(w, `<p class="admonition-title">`+admonition.Label()+"</p>")
+	renderer.CR(w)
+}
@@ -0,0 +1,28 @@
+package http
+
+import (
+	"io"
+
+	"github.com/evilmonkeyinc/markdownconverter"
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/gomarkdown/markdown/html"
+)
+
+// renderDiagram writes a Mermaid, PlantUML, or Graphviz block as a pre element
+// with the class expected by client side renderers, returning false if the
+// code block is not a diagram
+func renderDiagram(w io.Writer, renderer *html.Renderer, codeBlock *ast.CodeBlock) bool {
+	diagramType := markdownconverter.DiagramType(codeBlock.Info)
+	if diagramType == "" {
+		return false
+	}
+
+	renderer.CR(w)
+	renderer.Outs(w, `<pre class="`+diagramType+`">`)
+	html.EscapeHTML(w, codeBlock.Literal)
+	renderer.Outs(w, "</pre>")
+	if _, ok := codeBlock.Parent.(*ast.ListItem); !ok {
+		renderer.CR(w)
+	}
+	return true
+}
@@ -0,0 +1,73 @@
+package http
+
+import (
+	"bytes"
+	_ "embed" // required for the default stylesheet
+
+	"github.com/evilmonkeyinc/markdownconverter"
+	"github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/gomarkdown/markdown/html"
+	"github.com/gomarkdown/markdown/parser"
+)
+
+// DefaultStylesheet is the stylesheet embedded in standalone documents when
+// DefaultStyle is enabled
+//
+//go:embed style.css
+var DefaultStylesheet string
+
+// document wraps the rendered HTML fragment in a complete HTML document, with
+// any additional styles required by the fragment
+func (converter *Converter) document(title, styles, fragment string) []byte {
+	buffer := &bytes.Buffer{}
+	buffer.WriteString("<!DOCTYPE html>\n<html>\n<head>\n")
+	buffer.WriteString("<meta charset=\"utf-8\">\n")
+	buffer.WriteString("<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\">\n")
+	buffer.WriteString("<title>")
+	html.EscapeHTML(buffer, []byte(title))
+	buffer.WriteString("</title>\n")
+	if converter.DefaultStyle {
+		styles = DefaultStylesheet + styles
+	}
+	if styles != "" {
+		buffer.WriteString("<style>\n")
+		buffer.WriteString(styles)
+		buffer.WriteString("</style>\n")
+	}
+	if converter.Stylesheet != "" {
+		buffer.WriteString("<link rel=\"stylesheet\" href=\"")
+		html.EscapeHTML(buffer, []byte(converter.Stylesheet))
+		buffer.WriteString("\">\n")
+	}
+	buffer.WriteString("</head>\n<body>\n")
+	buffer.WriteString(fragment)
+	buffer.WriteString("\n</body>\n</html>\n")
+	return buffer.Bytes()
+}
+
+// Title returns the title of the markdown, taken from the title in the front
+// matter or the text of the first heading
+func (converter *Converter) Title(markdwn []byte) string {
+	frontMatter, body := markdownconverter.FrontMatter(markdwn)
+	document := markdown.Parse(body, parser.NewWithExtensions(converter.Extensions))
+	return documentTitle(frontMatter, document)
+}
+
+// documentTitle returns the title from the front matter, falling back to the
+// text of the first heading in the document
+func documentTitle(frontMatter map[string]string, document ast.Node) string {
+	if title, ok := frontMatter["title"]; ok {
+		return title
+	}
+
+	title := ""
+	ast.WalkFunc(document, func(node ast.Node, entering bool) ast.WalkStatus {
+		if heading, ok := node.(*ast.Heading); ok && entering {
+			title = markdownconverter.PlainText(heading)
+			return ast.Terminate
+		}
+		return ast.GoToNext
+	})
+	return title
+}
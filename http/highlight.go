@@ -0,0 +1,51 @@
+package http
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/evilmonkeyinc/markdownconverter/highlight"
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/gomarkdown/markdown/html"
+)
+
+// codeLanguage returns the language from the code block info string
+func codeLanguage(info []byte) string {
+	if end := bytes.IndexAny(info, "\t "); end >= 0 {
+		info = info[:end]
+	}
+	return string(info)
+}
+
+// renderHighlighted writes the code block with syntax highlighting, returning
+// false if the language is not supported so the default rendering can be used
+func (converter *Converter) renderHighlighted(w io.Writer, renderer *html.Renderer, codeBlock *ast.CodeBlock, theme *highlight.Theme) bool {
+	language := codeLanguage(codeBlock.Info)
+	lexer, ok := highlight.Get(language)
+	if !ok {
+		return false
+	}
+
+	renderer.CR(w)
+	if converter.HighlightInline {
+		renderer.Outs(w, `<pre style="`+theme.PreStyle()+`">`)
+	} else {
+		renderer.Outs(w, `<pre class="highlight">`)
+	}
+	renderer.Outs(w, `<code class="language-`)
+	html.EscapeHTML(w, []byte(language))
+	renderer.Outs(w, `">`)
+
+	tokens := lexer.Tokenize(string(codeBlock.Literal))
+	if converter.HighlightInline {
+		highlight.HTML(w, tokens, theme)
+	} else {
+		highlight.HTML(w, tokens, nil)
+	}
+
+	renderer.Outs(w, "</code></pre>")
+	if _, ok := codeBlock.Parent.(*ast.ListItem); !ok {
+		renderer.CR(w)
+	}
+	return true
+}
@@ -1,18 +1,118 @@
 package http
 
 import (
+	"fmt"
+	"io"
+	"sort"
 	"strings"
 
+	"github.com/evilmonkeyinc/markdownconverter"
+	"github.com/evilmonkeyinc/markdownconverter/highlight"
+	"github.com/evilmonkeyinc/markdownconverter/links"
 	"github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/gomarkdown/markdown/html"
+	"github.com/gomarkdown/markdown/parser"
+)
+
+const disablePrefix string = "no-"
+
+var (
+	// ErrUnknownExtension is returned when an extension name is not recognised
+	ErrUnknownExtension error = fmt.Errorf("unknown extension")
+	// ErrUnknownFlag is returned when a renderer flag name is not recognised
+	ErrUnknownFlag error = fmt.Errorf("unknown flag")
+
+	extensions map[string]parser.Extensions = map[string]parser.Extensions{
+		"auto-heading-ids": parser.AutoHeadingIDs,
+		"autolinks":        parser.Autolink,
+		"definition-lists": parser.DefinitionLists,
+		"fenced-code":      parser.FencedCode,
+		"footnotes":        parser.Footnotes,
+		"hard-line-breaks": parser.HardLineBreak,
+		"heading-ids":      parser.HeadingIDs,
+		"math":             parser.MathJax,
+		"strikethrough":    parser.Strikethrough,
+		"super-subscript":  parser.SuperSubscript,
+		"tables":           parser.Tables,
+	}
+
+	flags map[string]html.Flags = map[string]html.Flags{
+		"footnote-return-links": html.FootnoteReturnLinks,
+		"lazy-load-images":      html.LazyLoadImages,
+		"nofollow":              html.NofollowLinks,
+		"noopener":              html.NoopenerLinks,
+		"noreferrer":            html.NoreferrerLinks,
+		"safe-links":            html.Safelink,
+		"skip-html":             html.SkipHTML,
+		"skip-images":           html.SkipImages,
+		"smart-dashes":          html.SmartypantsDashes,
+		"smart-fractions":       html.SmartypantsFractions,
+		"smartypants":           html.Smartypants,
+		"target-blank":          html.HrefTargetBlank,
+		"xhtml":                 html.UseXHTML,
+	}
 )
 
 // New returns a new instace of Converter
 func New() *Converter {
-	return &Converter{}
+	return &Converter{
+		Extensions:     parser.CommonExtensions,
+		Flags:          html.CommonFlags,
+		ImageSizeLimit: DefaultImageSizeLimit,
+	}
 }
 
-// Converter is the Slack markdwn Converter implementation
+// Converter is the HTML Converter implementation
 type Converter struct {
+	// Extensions are the parser extensions used when reading the markdown
+	Extensions parser.Extensions
+	// Flags are the renderer flags used when writing the HTML
+	Flags html.Flags
+	// Standalone will wrap the output in a complete HTML document, titled by
+	// the front matter title or the first heading
+	Standalone bool
+	// StripFrontMatter will remove front matter from the start of the markdown,
+	// it is always removed from standalone documents
+	StripFrontMatter bool
+	// DefaultStyle will embed the DefaultStylesheet in standalone documents
+	DefaultStyle bool
+	// Stylesheet is the path or URL of a stylesheet linked by standalone documents
+	Stylesheet string
+	// HeadingIDs will give every heading a unique GitHub compatible ID
+	HeadingIDs bool
+	// HeadingAnchors will add a link to itself in every heading, enables HeadingIDs
+	HeadingAnchors bool
+	// TableOfContents will replace a "[TOC]" paragraph, or the start of the
+	// document if there is none, with a list of links to every heading, enables
+	// HeadingIDs
+	TableOfContents bool
+	// Highlight will add syntax highlighting to code blocks in supported languages
+	Highlight bool
+	// HighlightTheme is the name of the theme used for syntax highlighting,
+	// defaults to highlight.DefaultTheme
+	HighlightTheme string
+	// HighlightInline will add the theme styles inline rather than using classes,
+	// when using classes standalone documents will include the theme stylesheet
+	HighlightInline bool
+	// MathML will render math written in TeX, between $ or $$ delimiters, as
+	// MathML, math that cannot be translated is left for MathJax or KaTeX
+	MathML bool
+	// EmbedImages will replace local images with data URIs, so the output does
+	// not depend on any other files
+	EmbedImages bool
+	// ImageDir is the directory that relative image paths are resolved from
+	// when embedding images, usually the directory of the markdown file
+	ImageDir string
+	// ImageSizeLimit is the size in bytes above which images are not embedded,
+	// zero for no limit
+	ImageSizeLimit int64
+	// Links is used to rewrite the destinations of links and images, leave nil
+	// to keep them as they are
+	Links *links.Rewriter
+	// Sanitizer is the policy used to sanitize the rendered HTML, leave nil to
+	// keep the HTML as it is rendered
+	Sanitizer *Policy
 }
 
 // Format returns a unique name for the converter
@@ -22,7 +122,146 @@ func (converter *Converter) Format() string {
 
 // Parse will parse the standard markdown and return the converted data
 func (converter *Converter) Parse(markdwn []byte) ([]byte, error) {
-	bytes := markdown.ToHTML(markdwn, nil, nil)
+	frontMatter, body := markdownconverter.FrontMatter(markdwn)
+	if !converter.Standalone && !converter.StripFrontMatter {
+		body = markdwn
+	}
+
+	var theme *highlight.Theme
+	if converter.Highlight {
+		var err error
+		if theme, err = highlight.GetTheme(converter.HighlightTheme); err != nil {
+			return nil, err
+		}
+	}
+
+	parser := parser.NewWithExtensions(converter.Extensions)
+	document := markdown.Parse(body, parser)
+	markdownconverter.Admonitions(document)
+	display := displayMath(document)
+	if converter.EmbedImages {
+		converter.embedImages(document)
+	}
+	if converter.Links != nil {
+		if err := converter.Links.Rewrite(document); err != nil {
+			return nil, err
+		}
+	}
+
+	if converter.HeadingIDs || converter.HeadingAnchors || converter.TableOfContents {
+		headings := addHeadingIDs(document)
+		if converter.TableOfContents {
+			addTableOfContents(document, headings)
+		}
+	}
+
+	var renderer *html.Renderer
+	renderer = html.NewRenderer(html.RendererOptions{
+		Flags: converter.Flags,
+		RenderNodeHook: func(w io.Writer, node ast.Node, entering bool) (ast.WalkStatus, bool) {
+			switch node := node.(type) {
+			case *ast.Heading:
+				if converter.HeadingAnchors && entering {
+					renderHeadingAnchor(w, renderer, node)
+					return ast.GoToNext, true
+				}
+			case *ast.CodeBlock:
+				if renderDiagram(w, renderer, node) {
+					return ast.GoToNext, true
+				}
+				if theme != nil && converter.renderHighlighted(w, renderer, node, theme) {
+					return ast.GoToNext, true
+				}
+			case *ast.Math:
+				if converter.MathML && renderMath(w, renderer, node, display[node], entering) {
+					return ast.GoToNext, true
+				}
+				if display[node] {
+					renderDisplayMath(w, node)
+					return ast.GoToNext, true
+				}
+			case *ast.MathBlock:
+				if converter.MathML && renderMath(w, renderer, node, true, entering) {
+					return ast.GoToNext, true
+				}
+			case *markdownconverter.Admonition:
+				renderAdmonition(w, renderer, node, entering)
+				return ast.GoToNext, true
+			case *tableOfContents:
+				node.render(w, renderer)
+				return ast.GoToNext, true
+			}
+			return ast.GoToNext, false
+		},
+	})
+
+	bytes := markdown.Render(document, renderer)
+	if converter.Sanitizer != nil {
+		bytes = converter.Sanitizer.Sanitize(bytes)
+	}
 	clean := strings.TrimSpace(string(bytes))
+	if converter.Standalone {
+		styles := ""
+		if theme != nil && !converter.HighlightInline {
+			styles = theme.CSS()
+		}
+		return converter.document(documentTitle(frontMatter, document), styles, clean), nil
+	}
 	return []byte(clean), nil
 }
+
+// SetExtension enables the named parser extension, or disables it if the name
+// is prefixed with "no-"
+func (converter *Converter) SetExtension(name string) error {
+	disable := strings.HasPrefix(name, disablePrefix)
+	extension, ok := extensions[strings.TrimPrefix(name, disablePrefix)]
+	if !ok {
+		return fmt.Errorf("%w '%s', expected: (%s)", ErrUnknownExtension, name, strings.Join(ExtensionNames(), ", "))
+	}
+
+	if disable {
+		converter.Extensions &^= extension
+	} else {
+		converter.Extensions |= extension
+	}
+	return nil
+}
+
+// SetFlag enables the named renderer flag, or disables it if the name is
+// prefixed with "no-"
+func (converter *Converter) SetFlag(name string) error {
+	disable := strings.HasPrefix(name, disablePrefix)
+	flag, ok := flags[strings.TrimPrefix(name, disablePrefix)]
+	if !ok {
+		return fmt.Errorf("%w '%s', expected: (%s)", ErrUnknownFlag, name, strings.Join(FlagNames(), ", "))
+	}
+
+	if disable {
+		converter.Flags &^= flag
+	} else {
+		converter.Flags |= flag
+	}
+	return nil
+}
+
+// ExtensionNames returns the sorted names of the parser extensions that can be
+// passed to SetExtension
+func ExtensionNames() []string {
+	names := make([]string, 0, len(extensions))
+	for name := range extensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FlagNames returns the sorted names of the renderer flags that can be passed
+// to SetFlag
+func FlagNames() []string {
+	names := make([]string, 0, len(flags))
+	for name := range flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -4,6 +4,10 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/evilmonkeyinc/markdownconverter/highlight"
+	"github.com/evilmonkeyinc/markdownconverter/links"
+	"github.com/gomarkdown/markdown/html"
+	"github.com/gomarkdown/markdown/parser"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -187,3 +191,579 @@ _This is italic text_
 		})
 	}
 }
+
+func Test_Converter_Parse_Options(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		extensions []string
+		flags      []string
+		input      string
+		expected   string
+	}{
+		{
+			name:     "defaults",
+			input:    "line one\nline two 1/2",
+			expected: "<p>line one\nline two <sup>1</sup>&frasl;<sub>2</sub></p>",
+		},
+		{
+			name:       "hard_line_breaks",
+			extensions: []string{"hard-line-breaks"},
+			input:      "line one\nline two",
+			expected:   "<p>line one<br>\nline two</p>",
+		},
+		{
+			name:       "footnotes",
+			extensions: []string{"footnotes"},
+			input:      "text[^1]\n\n[^1]: note",
+			expected:   "<p>text<sup class=\"footnote-ref\" id=\"fnref:1\"><a href=\"#fn:1\">1</a></sup></p>\n\n<div class=\"footnotes\">\n\n<hr>\n\n<ol>\n<li id=\"fn:1\">note</li>\n</ol>\n\n</div>",
+		},
+		{
+			name:       "auto_heading_ids",
+			extensions: []string{"auto-heading-ids"},
+			input:      "# Heading One",
+			expected:   "<h1 id=\"heading-one\">Heading One</h1>",
+		},
+		{
+			name:     "heading_ids",
+			input:    "# Heading One {#custom}",
+			expected: "<h1 id=\"custom\">Heading One</h1>",
+		},
+		{
+			name:       "disable_tables",
+			extensions: []string{"no-tables"},
+			input:      "| a |\n| --- |\n| b |",
+			expected:   "<p>| a |\n| &mdash; |\n| b |</p>",
+		},
+		{
+			name:       "disable_autolinks",
+			extensions: []string{"no-autolinks"},
+			input:      "https://github.com",
+			expected:   "<p>https://github.com</p>",
+		},
+		{
+			name:     "target_blank",
+			flags:    []string{"target-blank"},
+			input:    "[evilmonkeyinc](https://github.com/evilmonkeyinc)",
+			expected: "<p><a href=\"https://github.com/evilmonkeyinc\" target=\"_blank\">evilmonkeyinc</a></p>",
+		},
+		{
+			name:     "nofollow",
+			flags:    []string{"safe-links", "nofollow"},
+			input:    "[evilmonkeyinc](https://github.com/evilmonkeyinc)",
+			expected: "<p><a href=\"https://github.com/evilmonkeyinc\" rel=\"nofollow\">evilmonkeyinc</a></p>",
+		},
+		{
+			name:     "safe_links",
+			flags:    []string{"safe-links"},
+			input:    "[click](javascript:alert(1))",
+			expected: "<p><tt>click</tt></p>",
+		},
+		{
+			name:     "disable_smart_fractions",
+			flags:    []string{"no-smart-fractions"},
+			input:    "1/2",
+			expected: "<p>&frac12;</p>",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			converter := New()
+			for _, extension := range test.extensions {
+				assert.Nil(t, converter.SetExtension(extension))
+			}
+			for _, flag := range test.flags {
+				assert.Nil(t, converter.SetFlag(flag))
+			}
+			actual, _ := converter.Parse([]byte(test.input))
+			assert.Equal(t, test.expected, string(actual))
+		})
+	}
+}
+
+func Test_Converter_SetExtension(t *testing.T) {
+	converter := New()
+
+	assert.Nil(t, converter.SetExtension("footnotes"))
+	assert.NotZero(t, converter.Extensions&parser.Footnotes)
+
+	assert.Nil(t, converter.SetExtension("no-footnotes"))
+	assert.Zero(t, converter.Extensions&parser.Footnotes)
+
+	err := converter.SetExtension("invalid")
+	assert.ErrorIs(t, err, ErrUnknownExtension)
+}
+
+func Test_Converter_SetFlag(t *testing.T) {
+	converter := New()
+
+	assert.Nil(t, converter.SetFlag("target-blank"))
+	assert.NotZero(t, converter.Flags&html.HrefTargetBlank)
+
+	assert.Nil(t, converter.SetFlag("no-target-blank"))
+	assert.Zero(t, converter.Flags&html.HrefTargetBlank)
+
+	err := converter.SetFlag("invalid")
+	assert.ErrorIs(t, err, ErrUnknownFlag)
+}
+
+func Test_Converter_Parse_Standalone(t *testing.T) {
+
+	header := "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\">\n"
+
+	tests := []struct {
+		name         string
+		defaultStyle bool
+		stylesheet   string
+		input        string
+		expected     string
+	}{
+		{
+			name:     "title_from_heading",
+			input:    "text\n\n## First `heading`\n\n# Second heading",
+			expected: header + "<title>First heading</title>\n</head>\n<body>\n<p>text</p>\n\n<h2>First <code>heading</code></h2>\n\n<h1>Second heading</h1>\n</body>\n</html>\n",
+		},
+		{
+			name:     "title_from_front_matter",
+			input:    "---\ntitle: Release <Notes>\n---\n# Heading",
+			expected: header + "<title>Release &lt;Notes&gt;</title>\n</head>\n<body>\n<h1>Heading</h1>\n</body>\n</html>\n",
+		},
+		{
+			name:     "no_title",
+			input:    "text",
+			expected: header + "<title></title>\n</head>\n<body>\n<p>text</p>\n</body>\n</html>\n",
+		},
+		{
+			name:       "linked_stylesheet",
+			stylesheet: "css/style.css?v=1&x=2",
+			input:      "text",
+			expected:   header + "<title></title>\n<link rel=\"stylesheet\" href=\"css/style.css?v=1&amp;x=2\">\n</head>\n<body>\n<p>text</p>\n</body>\n</html>\n",
+		},
+		{
+			name:         "default_style",
+			defaultStyle: true,
+			input:        "text",
+			expected:     header + "<title></title>\n<style>\n" + DefaultStylesheet + "</style>\n</head>\n<body>\n<p>text</p>\n</body>\n</html>\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			converter := New()
+			converter.Standalone = true
+			converter.DefaultStyle = test.defaultStyle
+			converter.Stylesheet = test.stylesheet
+			actual, _ := converter.Parse([]byte(test.input))
+			assert.Equal(t, test.expected, string(actual))
+		})
+	}
+}
+
+func Test_Converter_Parse_FrontMatter(t *testing.T) {
+	input := []byte("---\ntitle: Title\n---\n# Heading")
+
+	converter := New()
+	actual, _ := converter.Parse(input)
+	assert.Equal(t, "<hr>\n\n<h2>title: Title</h2>\n\n<h1>Heading</h1>", string(actual))
+
+	converter.StripFrontMatter = true
+	actual, _ = converter.Parse(input)
+	assert.Equal(t, "<h1>Heading</h1>", string(actual))
+
+	converter.StripFrontMatter = false
+	converter.Standalone = true
+	actual, _ = converter.Parse(input)
+	assert.NotContains(t, string(actual), "title: Title")
+	assert.Contains(t, string(actual), "<h1>Heading</h1>")
+}
+
+func Test_Converter_Parse_Headings(t *testing.T) {
+
+	tests := []struct {
+		name            string
+		headingIDs      bool
+		headingAnchors  bool
+		tableOfContents bool
+		input           string
+		expected        string
+	}{
+		{
+			name:       "heading_ids",
+			headingIDs: true,
+			input:      "# Heading One\n\n## Setup & Install!\n\n## Heading One\n\n### Café `code`",
+			expected:   "<h1 id=\"heading-one\">Heading One</h1>\n\n<h2 id=\"setup--install\">Setup &amp; Install!</h2>\n\n<h2 id=\"heading-one-1\">Heading One</h2>\n\n<h3 id=\"café-code\">Café <code>code</code></h3>",
+		},
+		{
+			name:       "heading_ids_keep_explicit_ids",
+			headingIDs: true,
+			input:      "# Custom {#heading}\n\n# Heading",
+			expected:   "<h1 id=\"heading\">Custom</h1>\n\n<h1 id=\"heading-1\">Heading</h1>",
+		},
+		{
+			name:           "heading_anchors",
+			headingAnchors: true,
+			input:          "## Heading",
+			expected:       "<h2 id=\"heading\"><a class=\"anchor\" href=\"#heading\" aria-hidden=\"true\">#</a>Heading</h2>",
+		},
+		{
+			name:            "table_of_contents_placeholder",
+			tableOfContents: true,
+			input:           "text\n\n[TOC]\n\n# One\n\n## Two\n\n### Three\n\n## Four\n\n# Five",
+			expected:        "<p>text</p>\n\n<nav class=\"toc\">\n<ul>\n<li><a href=\"#one\">One</a>\n<ul>\n<li><a href=\"#two\">Two</a>\n<ul>\n<li><a href=\"#three\">Three</a></li>\n</ul>\n</li>\n<li><a href=\"#four\">Four</a></li>\n</ul>\n</li>\n<li><a href=\"#five\">Five</a></li>\n</ul>\n</nav>\n\n<h1 id=\"one\">One</h1>\n\n<h2 id=\"two\">Two</h2>\n\n<h3 id=\"three\">Three</h3>\n\n<h2 id=\"four\">Four</h2>\n\n<h1 id=\"five\">Five</h1>",
+		},
+		{
+			name:            "table_of_contents_skipped_levels",
+			tableOfContents: true,
+			input:           "### Three\n\n# One\n\n### Three",
+			expected:        "<nav class=\"toc\">\n<ul>\n<li><a href=\"#three\">Three</a></li>\n<li><a href=\"#one\">One</a>\n<ul>\n<li><a href=\"#three-1\">Three</a></li>\n</ul>\n</li>\n</ul>\n</nav>\n\n<h3 id=\"three\">Three</h3>\n\n<h1 id=\"one\">One</h1>\n\n<h3 id=\"three-1\">Three</h3>",
+		},
+		{
+			name:            "table_of_contents_no_headings",
+			tableOfContents: true,
+			input:           "[TOC]\n\ntext",
+			expected:        "<p>text</p>",
+		},
+		{
+			name:     "placeholder_ignored_when_disabled",
+			input:    "[TOC]",
+			expected: "<p>[TOC]</p>",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			converter := New()
+			converter.HeadingIDs = test.headingIDs
+			converter.HeadingAnchors = test.headingAnchors
+			converter.TableOfContents = test.tableOfContents
+			actual, _ := converter.Parse([]byte(test.input))
+			assert.Equal(t, test.expected, string(actual))
+		})
+	}
+}
+
+func Test_Converter_Parse_Highlight(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		theme    string
+		inline   bool
+		input    string
+		expected string
+	}{
+		{
+			name:     "classes",
+			input:    "```go\nx := \"<a>\"\n```",
+			expected: "<pre class=\"highlight\"><code class=\"language-go\">x <span class=\"hl-operator\">:=</span> <span class=\"hl-string\">&quot;&lt;a&gt;&quot;</span>\n</code></pre>",
+		},
+		{
+			name:     "inline",
+			inline:   true,
+			input:    "```json\n[true]\n```",
+			expected: "<pre style=\"background-color: #f6f8fa; color: #24292e\"><code class=\"language-json\"><span style=\"color: #24292e\">[</span><span style=\"color: #005cc5\">true</span><span style=\"color: #24292e\">]</span>\n</code></pre>",
+		},
+		{
+			name:     "inline_theme",
+			theme:    "monokai",
+			inline:   true,
+			input:    "```sql\nSELECT\n```",
+			expected: "<pre style=\"background-color: #272822; color: #f8f8f2\"><code class=\"language-sql\"><span style=\"color: #f92672\">SELECT</span>\n</code></pre>",
+		},
+		{
+			name:     "unknown_language",
+			input:    "```unknown\nx := 1\n```",
+			expected: "<pre><code class=\"language-unknown\">x := 1\n</code></pre>",
+		},
+		{
+			name:     "no_language",
+			input:    "```\nx := 1\n```",
+			expected: "<pre><code>x := 1\n</code></pre>",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			converter := New()
+			converter.Highlight = true
+			converter.HighlightTheme = test.theme
+			converter.HighlightInline = test.inline
+			actual, err := converter.Parse([]byte(test.input))
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, string(actual))
+		})
+	}
+}
+
+func Test_Converter_Parse_Highlight_Standalone(t *testing.T) {
+	converter := New()
+	converter.Standalone = true
+	converter.Highlight = true
+	converter.HighlightTheme = "github-dark"
+
+	actual, err := converter.Parse([]byte("text"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(actual), "<style>\n"+highlight.Themes["github-dark"].CSS()+"</style>\n")
+
+	converter.HighlightInline = true
+	actual, err = converter.Parse([]byte("text"))
+	assert.Nil(t, err)
+	assert.NotContains(t, string(actual), "<style>")
+}
+
+func Test_Converter_Parse_Highlight_UnknownTheme(t *testing.T) {
+	converter := New()
+	converter.Highlight = true
+	converter.HighlightTheme = "invalid"
+
+	_, err := converter.Parse([]byte("text"))
+	assert.ErrorIs(t, err, highlight.ErrUnknownTheme)
+}
+
+func Test_Converter_Parse_Links(t *testing.T) {
+	converter := New()
+	converter.Links = &links.Rewriter{
+		BaseURL:        "https://example.com/docs/",
+		MarkdownToHTML: true,
+	}
+
+	actual, err := converter.Parse([]byte("[setup](./setup.md) ![arch](img/arch.png)"))
+	assert.Nil(t, err)
+	assert.Equal(t, "<p><a href=\"https://example.com/docs/setup.html\">setup</a> <img src=\"https://example.com/docs/img/arch.png\" alt=\"arch\" /></p>", string(actual))
+
+	converter.Links.BaseURL = "invalid"
+	_, err = converter.Parse([]byte("[setup](./setup.md)"))
+	assert.ErrorIs(t, err, links.ErrInvalidBaseURL)
+}
+
+func Test_Converter_Parse_EmbedImages(t *testing.T) {
+
+	png := "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAAC0lEQVR4nGNgAAIAAAUAAXpeqz8AAAAASUVORK5CYII="
+	svg := "data:image/svg+xml;base64,PHN2ZyB4bWxucz0iaHR0cDovL3d3dy53My5vcmcvMjAwMC9zdmciIHdpZHRoPSIxIiBoZWlnaHQ9IjEiPjwvc3ZnPgo="
+
+	tests := []struct {
+		name      string
+		imageDir  string
+		sizeLimit int64
+		input     string
+		expected  string
+	}{
+		{
+			name:     "png",
+			imageDir: "testdata",
+			input:    "![pixel](pixel.png)",
+			expected: "<p><img src=\"" + png + "\" alt=\"pixel\" /></p>",
+		},
+		{
+			name:     "svg_by_extension",
+			imageDir: "testdata",
+			input:    "![icon](./icon.svg)",
+			expected: "<p><img src=\"" + svg + "\" alt=\"icon\" /></p>",
+		},
+		{
+			name:     "relative_to_current_directory",
+			input:    "![pixel](testdata/pixel.png)",
+			expected: "<p><img src=\"" + png + "\" alt=\"pixel\" /></p>",
+		},
+		{
+			name:     "not_an_image",
+			imageDir: "testdata",
+			input:    "![notes](notes.txt)",
+			expected: "<p><img src=\"notes.txt\" alt=\"notes\" /></p>",
+		},
+		{
+			name:     "missing",
+			imageDir: "testdata",
+			input:    "![missing](missing.png)",
+			expected: "<p><img src=\"missing.png\" alt=\"missing\" /></p>",
+		},
+		{
+			name:     "remote",
+			imageDir: "testdata",
+			input:    "![remote](https://example.com/pixel.png)",
+			expected: "<p><img src=\"https://example.com/pixel.png\" alt=\"remote\" /></p>",
+		},
+		{
+			name:      "over_size_limit",
+			imageDir:  "testdata",
+			sizeLimit: 10,
+			input:     "![pixel](pixel.png)",
+			expected:  "<p><img src=\"pixel.png\" alt=\"pixel\" /></p>",
+		},
+		{
+			name:      "no_size_limit",
+			imageDir:  "testdata",
+			sizeLimit: 0,
+			input:     "![pixel](pixel.png)",
+			expected:  "<p><img src=\"" + png + "\" alt=\"pixel\" /></p>",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			converter := New()
+			converter.EmbedImages = true
+			converter.ImageDir = test.imageDir
+			converter.ImageSizeLimit = test.sizeLimit
+			actual, _ := converter.Parse([]byte(test.input))
+			assert.Equal(t, test.expected, string(actual))
+		})
+	}
+}
+
+func Test_Converter_Parse_EmbedImages_Sanitize(t *testing.T) {
+	converter := New()
+	converter.EmbedImages = true
+	converter.ImageDir = "testdata"
+	converter.Sanitizer = DefaultPolicy()
+
+	actual, _ := converter.Parse([]byte("![pixel](pixel.png)"))
+	assert.Equal(t, "<p><img alt=\"pixel\" /></p>", string(actual))
+
+	converter.Sanitizer.DataImages = true
+	actual, _ = converter.Parse([]byte("![pixel](pixel.png) [link](data:image/png;base64,AAAA)"))
+	assert.Equal(t, "<p><img src=\"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAAC0lEQVR4nGNgAAIAAAUAAXpeqz8AAAAASUVORK5CYII=\" alt=\"pixel\" /> <a>link</a></p>", string(actual))
+}
+
+func Test_Converter_Parse_MathML(t *testing.T) {
+
+	inline := `<math xmlns="http://www.w3.org/1998/Math/MathML"><semantics><msup><mi>x</mi><mn>2</mn></msup><annotation encoding="application/x-tex">x^2</annotation></semantics></math>`
+	display := `<math xmlns="http://www.w3.org/1998/Math/MathML" display="block"><semantics><mfrac><mn>1</mn><mn>2</mn></mfrac><annotation encoding="application/x-tex">\frac{1}{2}</annotation></semantics></math>`
+
+	tests := []struct {
+		name     string
+		mathML   bool
+		input    string
+		expected string
+	}{
+		{
+			name:     "disabled",
+			input:    "a $x^2$ b",
+			expected: "<p>a <span class=\"math inline\">\\(x^2\\)</span> b</p>",
+		},
+		{
+			name:     "inline",
+			mathML:   true,
+			input:    "a $x^2$ b",
+			expected: "<p>a " + inline + " b</p>",
+		},
+		{
+			name:     "display",
+			mathML:   true,
+			input:    "$$\n\\frac{1}{2}\n$$",
+			expected: display,
+		},
+		{
+			name:     "display_in_paragraph",
+			mathML:   true,
+			input:    "a $$\\frac{1}{2}$$ b",
+			expected: "<p>a " + display + " b</p>",
+		},
+		{
+			name:     "display_in_paragraph_disabled",
+			input:    "a $$\\frac{1}{2}$$ b",
+			expected: "<p>a <span class=\"math display\">\\[\\frac{1}{2}\\]</span> b</p>",
+		},
+		{
+			name:     "unsupported",
+			mathML:   true,
+			input:    "$\\unknown$ and\n\n$$\\unknown$$",
+			expected: "<p><span class=\"math inline\">\\(\\unknown\\)</span> and</p>\n<p><span class=\"math display\">\\[\\unknown\\]</span></p>",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			converter := New()
+			converter.MathML = test.mathML
+			actual, _ := converter.Parse([]byte(test.input))
+			assert.Equal(t, test.expected, string(actual))
+		})
+	}
+}
+
+func Test_Converter_Parse_MathML_Sanitize(t *testing.T) {
+	converter := New()
+	converter.MathML = true
+	converter.Sanitizer = DefaultPolicy()
+
+	actual, _ := converter.Parse([]byte("$x^2$"))
+	assert.Equal(t, "<p>x2x^2</p>", string(actual))
+
+	converter.Sanitizer.MathML = true
+	actual, _ = converter.Parse([]byte("$x^2$ <math><mi onclick=\"alert(1)\">y</mi></math>"))
+	assert.Equal(t, "<p><math xmlns=\"http://www.w3.org/1998/Math/MathML\"><semantics><msup><mi>x</mi><mn>2</mn></msup><annotation encoding=\"application/x-tex\">x^2</annotation></semantics></math> <math><mi>y</mi></math></p>", string(actual))
+}
+
+func Test_Converter_Parse_Diagrams(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		highlight bool
+		input     string
+		expected  string
+	}{
+		{
+			name:     "mermaid",
+			input:    "```mermaid\ngraph TD\n  A-->B\n```",
+			expected: "<pre class=\"mermaid\">graph TD\n  A--&gt;B\n</pre>",
+		},
+		{
+			name:     "plantuml",
+			input:    "```plantuml\n@startuml\nA -> B\n@enduml\n```",
+			expected: "<pre class=\"plantuml\">@startuml\nA -&gt; B\n@enduml\n</pre>",
+		},
+		{
+			name:      "dot",
+			highlight: true,
+			input:     "```dot\ndigraph { a -> b }\n```",
+			expected:  "<pre class=\"graphviz\">digraph { a -&gt; b }\n</pre>",
+		},
+		{
+			name:     "other",
+			input:    "```text\nplain\n```",
+			expected: "<pre><code class=\"language-text\">plain\n</code></pre>",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			converter := New()
+			converter.Highlight = test.highlight
+			actual, _ := converter.Parse([]byte(test.input))
+			assert.Equal(t, test.expected, string(actual))
+		})
+	}
+}
+
+func Test_Converter_Parse_Admonitions(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "warning",
+			input:    "> [!WARNING]\n> Be *careful*",
+			expected: "<div class=\"admonition warning\">\n<p class=\"admonition-title\">Warning</p>\n<p>Be <em>careful</em></p>\n</div>",
+		},
+		{
+			name:     "paragraphs",
+			input:    "> [!tip]\n>\n> first\n>\n> second",
+			expected: "<div class=\"admonition tip\">\n<p class=\"admonition-title\">Tip</p>\n<p>first</p>\n\n<p>second</p>\n</div>",
+		},
+		{
+			name:     "block_quote",
+			input:    "> [!NOTE] not on its own line",
+			expected: "<blockquote>\n<p>[!NOTE] not on its own line</p>\n</blockquote>",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, _ := New().Parse([]byte(test.input))
+			assert.Equal(t, test.expected, string(actual))
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package http
+
+import (
+	"encoding/base64"
+	"mime"
+	nethttp "net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gomarkdown/markdown/ast"
+)
+
+// DefaultImageSizeLimit is the size in bytes above which images are not embedded
+const DefaultImageSizeLimit int64 = 1 << 20
+
+// embedImages replaces the destination of each local image in the document
+// with a data URI containing the image
+func (converter *Converter) embedImages(document ast.Node) {
+	ast.WalkFunc(document, func(node ast.Node, entering bool) ast.WalkStatus {
+		if image, ok := node.(*ast.Image); ok && entering {
+			if dataURI, ok := converter.dataURI(string(image.Destination)); ok {
+				image.Destination = []byte(dataURI)
+			}
+		}
+		return ast.GoToNext
+	})
+}
+
+// dataURI returns the data URI for the local image, returning false if the
+// destination is not a local image or it is larger than the size limit
+func (converter *Converter) dataURI(destination string) (string, bool) {
+	mimeType, data, ok := converter.LocalImage(destination)
+	if !ok {
+		return "", false
+	}
+	return "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data), true
+}
+
+// LocalImage reads the local image at the destination, resolved relative to
+// the ImageDir, and returns its MIME type and content. False is returned if
+// the destination is not a local image or it is larger than the size limit.
+func (converter *Converter) LocalImage(destination string) (string, []byte, bool) {
+	parsed, err := url.Parse(destination)
+	if err != nil || parsed.Scheme != "" || parsed.Host != "" || parsed.Path == "" {
+		return "", nil, false
+	}
+
+	filename := filepath.FromSlash(parsed.Path)
+	if !filepath.IsAbs(filename) {
+		filename = filepath.Join(converter.ImageDir, filename)
+	}
+
+	info, err := os.Stat(filename)
+	if err != nil || info.IsDir() {
+		return "", nil, false
+	}
+	if converter.ImageSizeLimit > 0 && info.Size() > converter.ImageSizeLimit {
+		return "", nil, false
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return "", nil, false
+	}
+	mimeType := imageType(filename, data)
+	if mimeType == "" {
+		return "", nil, false
+	}
+	return mimeType, data, true
+}
+
+// imageType returns the MIME type of the image, sniffed from its content or
+// from the file extension for text based formats such as SVG. An empty string
+// is returned if the file is not an image.
+func imageType(filename string, data []byte) string {
+	if sniffed := nethttp.DetectContentType(data); strings.HasPrefix(sniffed, "image/") {
+		return sniffed
+	}
+	if byExtension := mime.TypeByExtension(filepath.Ext(filename)); strings.HasPrefix(byExtension, "image/") {
+		mediaType, _, err := mime.ParseMediaType(byExtension)
+		if err == nil {
+			return mediaType
+		}
+	}
+	return ""
+}
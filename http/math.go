@@ -0,0 +1,77 @@
+package http
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/evilmonkeyinc/markdownconverter/mathml"
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/gomarkdown/markdown/html"
+)
+
+// renderMath writes the TeX of the math node as MathML, displayed as a block
+// if display is true. False is returned if the TeX cannot be translated, so the
+// default rendering can be used instead, this is checked again when exiting a
+// block so both sides are rendered the same way.
+func renderMath(w io.Writer, renderer *html.Renderer, node ast.Node, display, entering bool) bool {
+	tex, block := []byte(nil), false
+	switch node := node.(type) {
+	case *ast.Math:
+		tex = node.Literal
+	case *ast.MathBlock:
+		tex, block = node.Literal, true
+	}
+
+	translated, err := mathml.Translate(string(tex), display)
+	if err != nil {
+		return false
+	}
+	if !entering {
+		return true
+	}
+
+	if block {
+		renderer.CR(w)
+	}
+	io.WriteString(w, translated)
+	if block {
+		renderer.CR(w)
+	}
+	return true
+}
+
+// renderDisplayMath writes math between $$ delimiters in a paragraph for
+// MathJax or KaTeX to display, the same as a math block but without the
+// paragraph around it
+func renderDisplayMath(w io.Writer, node *ast.Math) {
+	io.WriteString(w, `<span class="math display">\[`)
+	html.EscapeHTML(w, node.Literal)
+	io.WriteString(w, `\]</span>`)
+}
+
+// displayMath finds math between $$ delimiters in the middle of a paragraph,
+// which the parser reads as inline math between two dollar signs, removes the
+// dollar signs, and returns the math nodes that should be displayed
+func displayMath(document ast.Node) map[*ast.Math]bool {
+	display := map[*ast.Math]bool{}
+	ast.WalkFunc(document, func(node ast.Node, entering bool) ast.WalkStatus {
+		math, ok := node.(*ast.Math)
+		if !ok || !entering {
+			return ast.GoToNext
+		}
+		before, ok := ast.GetPrevNode(math).(*ast.Text)
+		if !ok || !bytes.HasSuffix(before.Literal, []byte("$")) {
+			return ast.GoToNext
+		}
+		after, ok := ast.GetNextNode(math).(*ast.Text)
+		if !ok || !bytes.HasPrefix(after.Literal, []byte("$")) {
+			return ast.GoToNext
+		}
+
+		before.Literal = before.Literal[:len(before.Literal)-1]
+		after.Literal = after.Literal[1:]
+		display[math] = true
+		return ast.GoToNext
+	})
+	return display
+}
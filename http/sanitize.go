@@ -0,0 +1,507 @@
+package http
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	// rawTextElements are removed along with all of their content
+	rawTextElements map[string]bool = map[string]bool{
+		"iframe":   true,
+		"noembed":  true,
+		"noframes": true,
+		"noscript": true,
+		"object":   true,
+		"script":   true,
+		"style":    true,
+		"template": true,
+		"textarea": true,
+		"title":    true,
+		"xmp":      true,
+	}
+
+	// voidElements have no content or end tag
+	voidElements map[string]bool = map[string]bool{
+		"area":  true,
+		"base":  true,
+		"br":    true,
+		"col":   true,
+		"embed": true,
+		"hr":    true,
+		"img":   true,
+		"input": true,
+		"link":  true,
+		"meta":  true,
+		"param": true,
+		"wbr":   true,
+	}
+
+	// mathElements are the MathML elements, and their attributes, produced when
+	// rendering math
+	mathElements map[string][]string = map[string][]string{
+		"annotation": {"encoding"},
+		"math":       {"display", "xmlns"},
+		"mfrac":      {"linethickness"},
+		"mi":         {"mathvariant"},
+		"mn":         {"mathvariant"},
+		"mo":         {"accent", "fence", "largeop", "stretchy"},
+		"mover":      {"accent"},
+		"mroot":      {},
+		"mrow":       {},
+		"mspace":     {"width"},
+		"msqrt":      {},
+		"mstyle":     {"displaystyle"},
+		"msub":       {},
+		"msubsup":    {},
+		"msup":       {},
+		"mtable":     {"columnalign"},
+		"mtd":        {},
+		"mtext":      {},
+		"mtr":        {},
+		"munder":     {"accentunder"},
+		"munderover": {},
+		"semantics":  {},
+	}
+
+	// highlightElements are the elements given a style attribute by inline
+	// syntax highlighting
+	highlightElements map[string]bool = map[string]bool{
+		"pre":  true,
+		"span": true,
+	}
+
+	// highlightProperties are the CSS properties used by inline syntax
+	// highlighting
+	highlightProperties map[string]bool = map[string]bool{
+		"background-color": true,
+		"color":            true,
+		"font-style":       true,
+		"font-weight":      true,
+		"text-decoration":  true,
+	}
+
+	// highlightValue matches the colors and keywords used as the values of the
+	// highlight properties, which cannot contain URLs or functions
+	highlightValue *regexp.Regexp = regexp.MustCompile(`^#?[a-zA-Z0-9-]+$`)
+
+	// urlAttributes have their values checked against the allowed URL schemes
+	urlAttributes map[string]bool = map[string]bool{
+		"action":     true,
+		"background": true,
+		"cite":       true,
+		"formaction": true,
+		"href":       true,
+		"longdesc":   true,
+		"poster":     true,
+		"src":        true,
+	}
+)
+
+// Policy is the allowlist used to sanitize the rendered HTML. Elements that are
+// not allowed are removed but their content is kept, unless they are script or
+// similar elements whose content is also removed. Attributes that are not
+// allowed, or URLs that do not use an allowed scheme, are removed.
+type Policy struct {
+	// Elements maps the allowed element names to the attributes allowed on them
+	Elements map[string][]string
+	// Attributes are the attributes allowed on every allowed element
+	Attributes []string
+	// URLSchemes are the schemes allowed in URL attributes, relative URLs are
+	// always allowed
+	URLSchemes []string
+	// DataImages will allow image data URIs as the source of img elements, such
+	// as those added when embedding images
+	DataImages bool
+	// MathML will allow the MathML elements produced when rendering math
+	MathML bool
+	// HighlightStyles will allow the colors and font styles added to code by
+	// inline syntax highlighting
+	HighlightStyles bool
+}
+
+// DefaultPolicy returns a new Policy that allows the elements produced from
+// standard markdown and only http, https, and mailto URLs
+func DefaultPolicy() *Policy {
+	return &Policy{
+		Elements: map[string][]string{
+			"a":          {"href", "name", "rel", "target"},
+			"abbr":       {},
+			"b":          {},
+			"blockquote": {"cite"},
+			"br":         {},
+			"caption":    {},
+			"cite":       {},
+			"code":       {},
+			"dd":         {},
+			"del":        {"cite"},
+			"details":    {"open"},
+			"div":        {},
+			"dl":         {},
+			"dt":         {},
+			"em":         {},
+			"figcaption": {},
+			"figure":     {},
+			"h1":         {},
+			"h2":         {},
+			"h3":         {},
+			"h4":         {},
+			"h5":         {},
+			"h6":         {},
+			"hr":         {},
+			"i":          {},
+			"img":        {"alt", "height", "loading", "src", "width"},
+			"ins":        {"cite"},
+			"kbd":        {},
+			"li":         {"value"},
+			"mark":       {},
+			"nav":        {},
+			"ol":         {"start", "type"},
+			"p":          {},
+			"pre":        {},
+			"q":          {"cite"},
+			"s":          {},
+			"samp":       {},
+			"small":      {},
+			"span":       {},
+			"strike":     {},
+			"strong":     {},
+			"sub":        {},
+			"summary":    {},
+			"sup":        {},
+			"table":      {},
+			"tbody":      {},
+			"td":         {"align", "colspan", "rowspan"},
+			"tfoot":      {},
+			"th":         {"align", "colspan", "rowspan"},
+			"thead":      {},
+			"tr":         {},
+			"tt":         {},
+			"u":          {},
+			"ul":         {},
+			"var":        {},
+		},
+		Attributes: []string{"class", "id", "title"},
+		URLSchemes: []string{"http", "https", "mailto"},
+	}
+}
+
+// Sanitize returns the HTML with everything not allowed by the policy removed
+func (policy *Policy) Sanitize(input []byte) []byte {
+	tokenizer := &tokenizer{input: string(input)}
+	buffer := &bytes.Buffer{}
+	open := make([]string, 0)
+
+	for {
+		token := tokenizer.next()
+		switch token.kind {
+		case tokenEOF:
+			for i := len(open) - 1; i >= 0; i-- {
+				buffer.WriteString("</" + open[i] + ">")
+			}
+			return buffer.Bytes()
+		case tokenText:
+			buffer.WriteString(token.data)
+		case tokenStartTag:
+			if rawTextElements[token.data] {
+				tokenizer.skipRawText(token.data)
+				continue
+			}
+			if !policy.allowsElement(token.data) {
+				continue
+			}
+			buffer.WriteString("<" + token.data)
+			for _, attribute := range token.attributes {
+				if !policy.allowsAttribute(token.data, attribute) {
+					continue
+				}
+				writeAttribute(buffer, attribute)
+			}
+			if token.selfClosing {
+				buffer.WriteString(" />")
+			} else {
+				buffer.WriteString(">")
+			}
+			if !voidElements[token.data] && !token.selfClosing {
+				open = append(open, token.data)
+			}
+		case tokenEndTag:
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] != token.data {
+					continue
+				}
+				for j := len(open) - 1; j >= i; j-- {
+					buffer.WriteString("</" + open[j] + ">")
+				}
+				open = open[:i]
+				break
+			}
+		}
+	}
+}
+
+func (policy *Policy) allowsElement(name string) bool {
+	if _, ok := policy.Elements[name]; ok {
+		return true
+	}
+	_, ok := mathElements[name]
+	return ok && policy.MathML
+}
+
+func (policy *Policy) allowsAttribute(element string, attribute attribute) bool {
+	if policy.HighlightStyles && highlightElements[element] && attribute.name == "style" {
+		return isHighlightStyle(attribute.value)
+	}
+	allowed := contains(policy.Elements[element], attribute.name) || contains(policy.Attributes, attribute.name)
+	if policy.MathML && contains(mathElements[element], attribute.name) {
+		allowed = true
+	}
+	if !allowed {
+		return false
+	}
+	if policy.DataImages && element == "img" && attribute.name == "src" && isDataImage(attribute.value) {
+		return true
+	}
+	if urlAttributes[attribute.name] {
+		return policy.allowsURL(attribute.value)
+	}
+	return true
+}
+
+// allowsURL returns true if the URL is relative or uses an allowed scheme
+func (policy *Policy) allowsURL(url string) bool {
+	// browsers ignore whitespace and control characters within the scheme
+	clean := strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, url)
+
+	separator := strings.IndexAny(clean, ":/?#")
+	if separator == -1 || clean[separator] != ':' {
+		return true
+	}
+	scheme := strings.ToLower(clean[:separator])
+	return contains(policy.URLSchemes, scheme)
+}
+
+// isDataImage returns true if the URL is a data URI with an image MIME type
+func isDataImage(url string) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(url)), "data:image/")
+}
+
+// isHighlightStyle returns true if the style only sets the highlight properties
+// to colors or keywords
+func isHighlightStyle(style string) bool {
+	for _, declaration := range strings.Split(style, ";") {
+		if strings.TrimSpace(declaration) == "" {
+			continue
+		}
+		separator := strings.IndexByte(declaration, ':')
+		if separator == -1 {
+			return false
+		}
+		property := strings.ToLower(strings.TrimSpace(declaration[:separator]))
+		value := strings.TrimSpace(declaration[separator+1:])
+		if !highlightProperties[property] || !highlightValue.MatchString(value) {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenText
+	tokenStartTag
+	tokenEndTag
+)
+
+type attribute struct {
+	name  string
+	value string
+}
+
+type token struct {
+	kind        tokenKind
+	data        string
+	attributes  []attribute
+	selfClosing bool
+}
+
+// tokenizer is a minimal HTML tokenizer that splits the input into text, start
+// tags, and end tags. Comments, doctypes, and processing instructions are
+// dropped, and any '<' that does not start a tag is escaped.
+type tokenizer struct {
+	input string
+	pos   int
+}
+
+func (tokenizer *tokenizer) next() token {
+	for tokenizer.pos < len(tokenizer.input) {
+		start := tokenizer.pos
+		if tokenizer.input[start] != '<' {
+			end := strings.IndexByte(tokenizer.input[start:], '<')
+			if end == -1 {
+				end = len(tokenizer.input) - start
+			}
+			tokenizer.pos = start + end
+			return token{kind: tokenText, data: tokenizer.input[start:tokenizer.pos]}
+		}
+
+		rest := tokenizer.input[start:]
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			tokenizer.skipPast("-->")
+			continue
+		case strings.HasPrefix(rest, "<!"), strings.HasPrefix(rest, "<?"):
+			tokenizer.skipPast(">")
+			continue
+		case len(rest) > 2 && rest[1] == '/' && isASCIILetter(rest[2]):
+			tokenizer.pos += 2
+			name := tokenizer.readName()
+			tokenizer.skipPast(">")
+			return token{kind: tokenEndTag, data: name}
+		case len(rest) > 1 && isASCIILetter(rest[1]):
+			if token, ok := tokenizer.readStartTag(); ok {
+				return token
+			}
+			tokenizer.pos = start
+		}
+
+		tokenizer.pos = start + 1
+		return token{kind: tokenText, data: "&lt;"}
+	}
+	return token{kind: tokenEOF}
+}
+
+// readStartTag reads the start tag at the current position, returning false if
+// the tag is not terminated
+func (tokenizer *tokenizer) readStartTag() (token, bool) {
+	tokenizer.pos++
+	tag := token{kind: tokenStartTag, data: tokenizer.readName()}
+	for tokenizer.pos < len(tokenizer.input) {
+		char := tokenizer.input[tokenizer.pos]
+		switch {
+		case char == '>':
+			tokenizer.pos++
+			return tag, true
+		case char == '/':
+			tokenizer.pos++
+			if tokenizer.pos < len(tokenizer.input) && tokenizer.input[tokenizer.pos] == '>' {
+				tag.selfClosing = true
+			}
+		case isSpace(char):
+			tokenizer.pos++
+		default:
+			tag.attributes = append(tag.attributes, tokenizer.readAttribute())
+		}
+	}
+	return tag, false
+}
+
+func (tokenizer *tokenizer) readAttribute() attribute {
+	start := tokenizer.pos
+	for tokenizer.pos < len(tokenizer.input) {
+		char := tokenizer.input[tokenizer.pos]
+		if isSpace(char) || char == '=' || char == '>' || (char == '/' && tokenizer.pos > start) {
+			break
+		}
+		tokenizer.pos++
+	}
+	attr := attribute{name: strings.ToLower(tokenizer.input[start:tokenizer.pos])}
+
+	tokenizer.skipSpace()
+	if tokenizer.pos >= len(tokenizer.input) || tokenizer.input[tokenizer.pos] != '=' {
+		return attr
+	}
+	tokenizer.pos++
+	tokenizer.skipSpace()
+	if tokenizer.pos >= len(tokenizer.input) {
+		return attr
+	}
+
+	quote := tokenizer.input[tokenizer.pos]
+	if quote == '"' || quote == '\'' {
+		tokenizer.pos++
+		end := strings.IndexByte(tokenizer.input[tokenizer.pos:], quote)
+		if end == -1 {
+			end = len(tokenizer.input) - tokenizer.pos
+		}
+		attr.value = html.UnescapeString(tokenizer.input[tokenizer.pos : tokenizer.pos+end])
+		tokenizer.pos += end
+		if tokenizer.pos < len(tokenizer.input) {
+			tokenizer.pos++
+		}
+		return attr
+	}
+
+	start = tokenizer.pos
+	for tokenizer.pos < len(tokenizer.input) && !isSpace(tokenizer.input[tokenizer.pos]) && tokenizer.input[tokenizer.pos] != '>' {
+		tokenizer.pos++
+	}
+	attr.value = html.UnescapeString(tokenizer.input[start:tokenizer.pos])
+	return attr
+}
+
+func (tokenizer *tokenizer) readName() string {
+	start := tokenizer.pos
+	for tokenizer.pos < len(tokenizer.input) {
+		char := tokenizer.input[tokenizer.pos]
+		if isSpace(char) || char == '/' || char == '>' {
+			break
+		}
+		tokenizer.pos++
+	}
+	return strings.ToLower(tokenizer.input[start:tokenizer.pos])
+}
+
+// skipRawText moves past the content and end tag of the named element
+func (tokenizer *tokenizer) skipRawText(name string) {
+	end := strings.Index(strings.ToLower(tokenizer.input[tokenizer.pos:]), "</"+name)
+	if end == -1 {
+		tokenizer.pos = len(tokenizer.input)
+		return
+	}
+	tokenizer.pos += end
+	tokenizer.skipPast(">")
+}
+
+// skipPast moves past the next occurrence of the delimiter, or to the end of
+// the input if there is none
+func (tokenizer *tokenizer) skipPast(delimiter string) {
+	end := strings.Index(tokenizer.input[tokenizer.pos:], delimiter)
+	if end == -1 {
+		tokenizer.pos = len(tokenizer.input)
+		return
+	}
+	tokenizer.pos += end + len(delimiter)
+}
+
+func (tokenizer *tokenizer) skipSpace() {
+	for tokenizer.pos < len(tokenizer.input) && isSpace(tokenizer.input[tokenizer.pos]) {
+		tokenizer.pos++
+	}
+}
+
+func isASCIILetter(char byte) bool {
+	return (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
+}
+
+func isSpace(char byte) bool {
+	return char == ' ' || char == '\t' || char == '\n' || char == '\r' || char == '\f'
+}
@@ -0,0 +1,299 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Policy_Sanitize(t *testing.T) {
+
+	policy := DefaultPolicy()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "allowed_elements",
+			input:    "<p>text <strong>bold</strong> <em>italic</em></p>",
+			expected: "<p>text <strong>bold</strong> <em>italic</em></p>",
+		},
+		{
+			name:     "allowed_attributes",
+			input:    "<h1 id=\"heading\" class=\"title\">Heading</h1>",
+			expected: "<h1 id=\"heading\" class=\"title\">Heading</h1>",
+		},
+		{
+			name:     "disallowed_element_keeps_content",
+			input:    "<font color=\"red\">text</font>",
+			expected: "text",
+		},
+		{
+			name:     "disallowed_attribute",
+			input:    "<p style=\"color: red\" data-x=\"1\">text</p>",
+			expected: "<p>text</p>",
+		},
+		{
+			name:     "unclosed_elements",
+			input:    "<div>text <b>bold",
+			expected: "<div>text <b>bold</b></div>",
+		},
+		{
+			name:     "misnested_elements",
+			input:    "<b><i>text</b></i>",
+			expected: "<b><i>text</i></b>",
+		},
+		{
+			name:     "unopened_end_tag",
+			input:    "text</div>",
+			expected: "text",
+		},
+		{
+			name:     "void_elements",
+			input:    "<br><hr />",
+			expected: "<br><hr />",
+		},
+		{
+			name:     "attribute_escaping",
+			input:    "<a title='\"quoted\" &amp; <b>'>x</a>",
+			expected: "<a title=\"&#34;quoted&#34; &amp; &lt;b&gt;\">x</a>",
+		},
+		{
+			name:     "stray_less_than",
+			input:    "a < b",
+			expected: "a &lt; b",
+		},
+		{
+			name:     "unterminated_tag",
+			input:    "<a href=\"x\"",
+			expected: "&lt;a href=\"x\"",
+		},
+		{
+			name:     "relative_urls",
+			input:    "<a href=\"docs/setup.html#install\">x</a><a href=\"/root?a=b:c\">y</a><a href=\"#top\">z</a>",
+			expected: "<a href=\"docs/setup.html#install\">x</a><a href=\"/root?a=b:c\">y</a><a href=\"#top\">z</a>",
+		},
+		{
+			name:     "allowed_schemes",
+			input:    "<a href=\"HTTPS://example.com\">x</a><a href=\"mailto:team@example.com\">y</a>",
+			expected: "<a href=\"HTTPS://example.com\">x</a><a href=\"mailto:team@example.com\">y</a>",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := policy.Sanitize([]byte(test.input))
+			assert.Equal(t, test.expected, string(actual))
+		})
+	}
+}
+
+func Test_Policy_Sanitize_Custom(t *testing.T) {
+	policy := &Policy{
+		Elements: map[string][]string{
+			"a":    {"href"},
+			"span": {"style"},
+		},
+		URLSchemes: []string{"ftp"},
+	}
+
+	actual := policy.Sanitize([]byte("<p><span style=\"color: red\" class=\"x\"><a href=\"ftp://files\">x</a><a href=\"https://example.com\">y</a></span></p>"))
+	assert.Equal(t, "<span style=\"color: red\"><a href=\"ftp://files\">x</a><a>y</a></span>", string(actual))
+}
+
+func Test_Policy_Sanitize_HighlightStyles(t *testing.T) {
+	policy := DefaultPolicy()
+	input := "<pre style=\"background-color: #ffffff; color: #24292e\"><span style=\"color: #d73a49; font-weight: bold\">if</span>" +
+		"<span style=\"background: url(javascript:alert(1))\">a</span><span style=\"color: expression(alert(1))\">b</span></pre>" +
+		"<div style=\"color: red\">c</div>"
+
+	actual := policy.Sanitize([]byte(input))
+	assert.Equal(t, "<pre><span>if</span><span>a</span><span>b</span></pre><div>c</div>", string(actual))
+
+	policy.HighlightStyles = true
+	actual = policy.Sanitize([]byte(input))
+	assert.Equal(t, "<pre style=\"background-color: #ffffff; color: #24292e\"><span style=\"color: #d73a49; font-weight: bold\">if</span><span>a</span><span>b</span></pre><div>c</div>", string(actual))
+}
+
+// Test_Converter_Parse_XSS checks markdown and raw HTML attack vectors are
+// removed from the output when sanitizing
+func Test_Converter_Parse_XSS(t *testing.T) {
+
+	converter := New()
+	converter.Sanitizer = DefaultPolicy()
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			input:    "<script>alert(1)</script>",
+			expected: "<p></p>",
+		},
+		{
+			input:    "<SCRIPT SRC=https://example.com/xss.js></SCRIPT>",
+			expected: "<p></p>",
+		},
+		{
+			input:    "text\n\n<script>\nalert(1)\n</script>\n\nmore",
+			expected: "<p>text</p>\n\n\n\n<p>more</p>",
+		},
+		{
+			input:    "<img src=x onerror=alert(1)>",
+			expected: "<p><img src=\"x\"></p>",
+		},
+		{
+			input:    "<img src=\"x\" onerror=\"alert(1)\"/>",
+			expected: "<p><img src=\"x\" /></p>",
+		},
+		{
+			input:    "<svg onload=alert(1)>",
+			expected: "<p></p>",
+		},
+		{
+			input:    "<body onload=alert(1)>",
+			expected: "<p></p>",
+		},
+		{
+			input:    "<a href=\"https://example.com\" onclick=\"alert(1)\">x</a>",
+			expected: "<p><a href=\"https://example.com\">x</a></p>",
+		},
+		{
+			input:    "[click](javascript:alert(1))",
+			expected: "<p><a>click</a></p>",
+		},
+		{
+			input:    "[click](JaVaScRiPt:alert(1))",
+			expected: "<p><a>click</a></p>",
+		},
+		{
+			input:    "[click](vbscript:msgbox(1))",
+			expected: "<p><a>click</a></p>",
+		},
+		{
+			input:    "[click](data:text/html;base64,PHNjcmlwdD5hbGVydCgxKTwvc2NyaXB0Pg==)",
+			expected: "<p><a>click</a></p>",
+		},
+		{
+			input:    "![image](javascript:alert(1))",
+			expected: "<p><img alt=\"image\" /></p>",
+		},
+		{
+			input:    "<javascript:alert(1)>",
+			expected: "<p><a>javascript:alert(1)</a></p>",
+		},
+		{
+			input:    "<a href=\"java&#x09;script:alert(1)\">x</a>",
+			expected: "<p><a>x</a></p>",
+		},
+		{
+			input:    "<a href=\"&#106;avascript:alert(1)\">x</a>",
+			expected: "<p><a>x</a></p>",
+		},
+		{
+			input:    "<a href=\"&#x6A;&#x61;&#x76;&#x61;&#x73;&#x63;&#x72;&#x69;&#x70;&#x74;&#x3A;alert(1)\">x</a>",
+			expected: "<p><a>x</a></p>",
+		},
+		{
+			input:    "<a href=\" javascript:alert(1)\">x</a>",
+			expected: "<p><a>x</a></p>",
+		},
+		{
+			input:    "<a href=\"javascript&colon;alert(1)\">x</a>",
+			expected: "<p><a>x</a></p>",
+		},
+		{
+			input:    "<a href=javascript:alert(1)>x</a>",
+			expected: "<p><a>x</a></p>",
+		},
+		{
+			input:    "<iframe src=\"javascript:alert(1)\"></iframe>",
+			expected: "<p></p>",
+		},
+		{
+			input:    "<object data=\"https://example.com/xss.swf\"></object>",
+			expected: "<p></p>",
+		},
+		{
+			input:    "<embed src=\"https://example.com/xss.swf\">",
+			expected: "<p></p>",
+		},
+		{
+			input:    "<style>body { background: url(javascript:alert(1)) }</style>",
+			expected: "<p></p>",
+		},
+		{
+			input:    "<div style=\"background: url(javascript:alert(1))\">x</div>",
+			expected: "<p><div>x</div></p>",
+		},
+		{
+			input:    "<meta http-equiv=\"refresh\" content=\"0;url=javascript:alert(1)\">",
+			expected: "<p></p>",
+		},
+		{
+			input:    "<base href=\"javascript:alert(1)//\">",
+			expected: "<p></p>",
+		},
+		{
+			input:    "<link rel=\"stylesheet\" href=\"javascript:alert(1)\">",
+			expected: "<p></p>",
+		},
+		{
+			input:    "<form action=\"javascript:alert(1)\"><input type=\"submit\"></form>",
+			expected: "<p></p>",
+		},
+		{
+			input:    "<button formaction=\"javascript:alert(1)\">x</button>",
+			expected: "<p>x</p>",
+		},
+		{
+			input:    "<!--<script>alert(1)</script>-->",
+			expected: "<p></p>",
+		},
+		{
+			input:    "<<script>script>alert(1)<</script>/script>",
+			expected: "<p>&lt;/script&gt;</p>",
+		},
+		{
+			input:    "<scr<script>ipt>alert(1)</script>",
+			expected: "<p>ipt&gt;alert(1)</p>",
+		},
+		{
+			input:    "<math><mtext><table><mglyph><style><img src=x onerror=alert(1)>",
+			expected: "<p><table></table></p>",
+		},
+		{
+			input:    "<a href=\"https://example.com\" title=\"&quot;><script>alert(1)</script>\">x</a>",
+			expected: "<p>&lt;a href=\"https://example.com\" title=\"&quot;>&rdquo;&gt;x</p>",
+		},
+		{
+			input:    "<img src=\"x\" alt=\"`onerror=alert(1)\">",
+			expected: "<p><img src=\"x\" alt=\"`onerror=alert(1)\"></p>",
+		},
+		{
+			input:    "<noscript><p title=\"</noscript><img src=x onerror=alert(1)>\">",
+			expected: "<p><img src=\"x\">&rdquo;&gt;</p>",
+		},
+		{
+			input:    "<textarea><script>alert(1)</script></textarea>",
+			expected: "<p></p>",
+		},
+		{
+			input:    "<template><script>alert(1)</script></template>",
+			expected: "<p></p>",
+		},
+		{
+			input:    "<details open ontoggle=alert(1)>",
+			expected: "<p><details open=\"\"></details></p>",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			actual, _ := converter.Parse([]byte(test.input))
+			assert.Equal(t, test.expected, string(actual))
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"bytes"
+	"html"
+	"strings"
+)
+
+// InlineStyles adds the CSS declarations for each element to its style
+// attribute, ahead of any declarations it already has so they take priority.
+// A key of two element names separated by a space, such as "pre code", applies
+// to the second element when it is within the first, and a key of a class,
+// such as ".note" or "div.note", applies to the elements with that class.
+func InlineStyles(input []byte, styles map[string]string) []byte {
+	tokenizer := &tokenizer{input: string(input)}
+	buffer := &bytes.Buffer{}
+	open := make([]string, 0)
+
+	for {
+		token := tokenizer.next()
+		switch token.kind {
+		case tokenEOF:
+			return buffer.Bytes()
+		case tokenText:
+			buffer.WriteString(token.data)
+		case tokenStartTag:
+			declarations := make([]string, 0)
+			if style, ok := styles[token.data]; ok {
+				declarations = append(declarations, style)
+			}
+			for _, parent := range open {
+				if style, ok := styles[parent+" "+token.data]; ok {
+					declarations = append(declarations, style)
+				}
+			}
+			for _, attribute := range token.attributes {
+				if attribute.name != "class" {
+					continue
+				}
+				for _, class := range strings.Fields(attribute.value) {
+					for _, selector := range []string{"." + class, token.data + "." + class} {
+						if style, ok := styles[selector]; ok {
+							declarations = append(declarations, style)
+						}
+					}
+				}
+			}
+
+			buffer.WriteString("<" + token.data)
+			for _, attribute := range token.attributes {
+				if attribute.name == "style" {
+					declarations = append(declarations, attribute.value)
+					continue
+				}
+				writeAttribute(buffer, attribute)
+			}
+			if len(declarations) > 0 {
+				writeAttribute(buffer, attribute{name: "style", value: joinDeclarations(declarations)})
+			}
+			if token.selfClosing {
+				buffer.WriteString(" />")
+			} else {
+				buffer.WriteString(">")
+			}
+
+			if !voidElements[token.data] && !token.selfClosing {
+				open = append(open, token.data)
+			}
+		case tokenEndTag:
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] == token.data {
+					open = open[:i]
+					break
+				}
+			}
+			buffer.WriteString("</" + token.data + ">")
+		}
+	}
+}
+
+// joinDeclarations combines the CSS declaration lists into one
+func joinDeclarations(declarations []string) string {
+	clean := make([]string, 0, len(declarations))
+	for _, declaration := range declarations {
+		declaration = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(declaration), ";"))
+		if declaration != "" {
+			clean = append(clean, declaration)
+		}
+	}
+	return strings.Join(clean, "; ")
+}
+
+func writeAttribute(buffer *bytes.Buffer, attribute attribute) {
+	buffer.WriteString(" " + attribute.name + "=\"" + html.EscapeString(attribute.value) + "\"")
+}
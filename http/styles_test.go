@@ -0,0 +1,94 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_InlineStyles(t *testing.T) {
+
+	styles := map[string]string{
+		"p":        "margin: 0",
+		"code":     "font-family: monospace;",
+		"pre code": "padding: 0",
+		"img":      "border: 0",
+		".note":    "color: blue",
+		"p.title":  "font-weight: bold",
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "element",
+			input:    "<p>text</p>",
+			expected: "<p style=\"margin: 0\">text</p>",
+		},
+		{
+			name:     "unstyled",
+			input:    "<h1 id=\"a\">text</h1>",
+			expected: "<h1 id=\"a\">text</h1>",
+		},
+		{
+			name:     "descendant",
+			input:    "<p><code>a</code></p><pre><code>b</code></pre><code>c</code>",
+			expected: "<p style=\"margin: 0\"><code style=\"font-family: monospace\">a</code></p><pre><code style=\"font-family: monospace; padding: 0\">b</code></pre><code style=\"font-family: monospace\">c</code>",
+		},
+		{
+			name:     "existing_style",
+			input:    "<p style=\"color: red;\" class=\"x\">text</p>",
+			expected: "<p class=\"x\" style=\"margin: 0; color: red\">text</p>",
+		},
+		{
+			name:     "void",
+			input:    "<img src=\"a.png\" alt=\"a &amp; b\"><br />",
+			expected: "<img src=\"a.png\" alt=\"a &amp; b\" style=\"border: 0\"><br />",
+		},
+		{
+			name:     "class",
+			input:    "<div class=\"admonition note\"><p class=\"title\">Note</p><span class=\"title\">a</span></div>",
+			expected: "<div class=\"admonition note\" style=\"color: blue\"><p class=\"title\" style=\"margin: 0; font-weight: bold\">Note</p><span class=\"title\">a</span></div>",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := InlineStyles([]byte(test.input), styles)
+			assert.Equal(t, test.expected, string(actual))
+		})
+	}
+}
+
+func Test_Converter_Title(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "front_matter",
+			input:    "---\ntitle: Release Notes\n---\n# Heading",
+			expected: "Release Notes",
+		},
+		{
+			name:     "heading",
+			input:    "text\n\n## First *heading*",
+			expected: "First heading",
+		},
+		{
+			name:     "none",
+			input:    "text",
+			expected: "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, New().Title([]byte(test.input)))
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package http
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/evilmonkeyinc/markdownconverter"
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/gomarkdown/markdown/html"
+)
+
+// tocPlaceholder is replaced with the table of contents when enabled
+const tocPlaceholder string = "[TOC]"
+
+// tableOfContents is the node that is rendered as the table of contents
+type tableOfContents struct {
+	ast.Leaf
+
+	headings []*ast.Heading
+}
+
+// addHeadingIDs sets a unique ID on each heading that does not already have
+// one and returns the headings in document order
+func addHeadingIDs(document ast.Node) []*ast.Heading {
+	headings := make([]*ast.Heading, 0)
+	ast.WalkFunc(document, func(node ast.Node, entering bool) ast.WalkStatus {
+		if heading, ok := node.(*ast.Heading); ok && entering {
+			headings = append(headings, heading)
+		}
+		return ast.GoToNext
+	})
+
+	slugger := markdownconverter.NewSlugger()
+	// reserve the IDs that have been set explicitly
+	for _, heading := range headings {
+		if heading.HeadingID != "" {
+			slugger.Reserve(heading.HeadingID)
+		}
+	}
+	for _, heading := range headings {
+		if heading.HeadingID == "" {
+			heading.HeadingID = slugger.Slug(markdownconverter.PlainText(heading))
+		}
+	}
+	return headings
+}
+
+// addTableOfContents replaces the placeholder paragraph with the table of
+// contents, or inserts it at the start of the document if there is none
+func addTableOfContents(document ast.Node, headings []*ast.Heading) {
+	toc := &tableOfContents{
+		headings: headings,
+	}
+
+	for _, child := range document.GetChildren() {
+		if paragraph, ok := child.(*ast.Paragraph); ok && markdownconverter.PlainText(paragraph) == tocPlaceholder {
+			children := document.GetChildren()
+			for index := range children {
+				if children[index] == paragraph {
+					children[index] = toc
+				}
+			}
+			toc.SetParent(document)
+			return
+		}
+	}
+
+	toc.SetParent(document)
+	document.SetChildren(append([]ast.Node{toc}, document.GetChildren()...))
+}
+
+// renderHeadingAnchor writes the heading open tag followed by a link to the
+// heading itself
+func renderHeadingAnchor(w io.Writer, renderer *html.Renderer, heading *ast.Heading) {
+	renderer.Heading(w, heading, true)
+	io.WriteString(w, `<a class="anchor" href="#`)
+	html.EscapeHTML(w, []byte(heading.HeadingID))
+	io.WriteString(w, `" aria-hidden="true">#</a>`)
+}
+
+// render writes the table of contents as nested lists, where each heading is
+// nested under the closest previous heading with a lower level
+func (toc *tableOfContents) render(w io.Writer, renderer *html.Renderer) {
+	if len(toc.headings) == 0 {
+		return
+	}
+
+	buffer := &bytes.Buffer{}
+	io.WriteString(buffer, "<nav class=\"toc\">\n")
+	// levels holds the heading level of each open list
+	levels := make([]int, 0)
+	for _, heading := range toc.headings {
+		if len(levels) == 0 || heading.Level > levels[len(levels)-1] {
+			if len(levels) > 0 {
+				io.WriteString(buffer, "\n")
+			}
+			io.WriteString(buffer, "<ul>\n")
+			levels = append(levels, heading.Level)
+		} else {
+			io.WriteString(buffer, "</li>\n")
+			for len(levels) > 1 && heading.Level <= levels[len(levels)-2] {
+				io.WriteString(buffer, "</ul>\n</li>\n")
+				levels = levels[:len(levels)-1]
+			}
+			levels[len(levels)-1] = heading.Level
+		}
+
+		io.WriteString(buffer, `<li><a href="#`)
+		html.EscapeHTML(buffer, []byte(heading.HeadingID))
+		io.WriteString(buffer, `">`)
+		html.EscapeHTML(buffer, []byte(markdownconverter.PlainText(heading)))
+		io.WriteString(buffer, "</a>")
+	}
+	for range levels {
+		io.WriteString(buffer, "</li>\n</ul>\n")
+	}
+	io.WriteString(buffer, "</nav>")
+
+	renderer.CR(w)
+	renderer.Outs(w, buffer.String())
+	renderer.CR(w)
+}
@@ -0,0 +1,42 @@
+// Package jira converts markdown to Jira wiki markup
+package jira
+
+import (
+	"strings"
+
+	"github.com/evilmonkeyinc/markdownconverter"
+	"github.com/evilmonkeyinc/markdownconverter/links"
+	"github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/parser"
+)
+
+// New returns a new instance of Converter
+func New() *Converter {
+	return &Converter{}
+}
+
+// Converter is the Jira wiki markup Converter implementation
+type Converter struct {
+	// Links is used to rewrite the destinations of links and images, leave nil
+	// to keep them as they are
+	Links *links.Rewriter
+}
+
+// Format returns a unique name for the converter
+func (converter *Converter) Format() string {
+	return "jira"
+}
+
+// Parse will parse the standard markdown and return the converted data
+func (converter *Converter) Parse(markdwn []byte) ([]byte, error) {
+	_, body := markdownconverter.FrontMatter(markdwn)
+	document := markdown.Parse(markdown.NormalizeNewlines(body), parser.New())
+	markdownconverter.Admonitions(document)
+	if converter.Links != nil {
+		if err := converter.Links.Rewrite(document); err != nil {
+			return nil, err
+		}
+	}
+
+	return []byte(strings.TrimSpace(blocks(document.GetChildren(), "\n\n"))), nil
+}
@@ -0,0 +1,132 @@
+package jira
+
+import (
+	"testing"
+
+	"github.com/evilmonkeyinc/markdownconverter/links"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Converter_Format(t *testing.T) {
+	actual := New().Format()
+	assert.Equal(t, "jira", actual)
+}
+
+func Test_Converter_Parse(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "headings",
+			input:    "# Heading 1\n\n###### Heading 6",
+			expected: "h1. Heading 1\n\nh6. Heading 6",
+		},
+		{
+			name:     "formatting",
+			input:    "**bold** _italic_ ~~strikethrough~~ <u>underline</u> `code`",
+			expected: "*bold* _italic_ -strikethrough- +underline+ {{code}}",
+		},
+		{
+			name:     "escaping",
+			input:    "a {macro} [link] a|b 2*3 snake_case !image! -x- `{code}`",
+			expected: `a \{macro\} \[link\] a\|b 2\*3 snake\_case \!image\! \-x\- {{\{code\}}}`,
+		},
+		{
+			name:     "links",
+			input:    "[evilmonkeyinc](https://github.com/evilmonkeyinc) and <https://github.com>",
+			expected: "[evilmonkeyinc|https://github.com/evilmonkeyinc] and [https://github.com]",
+		},
+		{
+			name:     "images",
+			input:    "![The logo](https://example.com/logo.png) ![](https://example.com/icon.png)",
+			expected: "!https://example.com/logo.png|alt=The logo! !https://example.com/icon.png!",
+		},
+		{
+			name:     "code_block",
+			input:    "```go\nfunc main() {}\n```\n\n```\nplain\n```",
+			expected: "{code:go}\nfunc main() {}\n{code}\n\n{code}\nplain\n{code}",
+		},
+		{
+			name:     "diagram",
+			input:    "```mermaid\ngraph TD\n```",
+			expected: "{code:title=Mermaid diagram}\ngraph TD\n{code}",
+		},
+		{
+			name:     "math",
+			input:    "inline $x^2$\n\n$$\ny = mx + c\n$$",
+			expected: "inline {{x\\^2}}\n\n{noformat}\ny = mx + c\n{noformat}",
+		},
+		{
+			name:     "quote",
+			input:    "> quoted\n> text",
+			expected: "{quote}\nquoted\ntext\n{quote}",
+		},
+		{
+			name:     "admonition",
+			input:    "> [!NOTE]\n> Read this",
+			expected: "{panel:title=Note}\nRead this\n{panel}",
+		},
+		{
+			name:     "lists",
+			input:    "* one\n* two\n  * nested\n    1. deeper\n* three",
+			expected: "* one\n* two\n** nested\n**# deeper\n* three",
+		},
+		{
+			name:     "ordered_lists",
+			input:    "1. one\n2. two\n   1. nested",
+			expected: "# one\n# two\n## nested",
+		},
+		{
+			name:     "list_paragraphs",
+			input:    "1. first\n\n    more\n\n2. second",
+			expected: "# first \\\\ more\n# second",
+		},
+		{
+			name:     "list_code_block",
+			input:    "1. step:\n\n        code\n\n2. next",
+			expected: "# step:\n{code}\ncode\n{code}\n# next",
+		},
+		{
+			name:     "table",
+			input:    "| Name | Value |\n| --- | --- |\n| **one** | 1 |\n| two | |",
+			expected: "||Name||Value||\n|*one*|1|\n|two| |",
+		},
+		{
+			name:     "horizontal_rule",
+			input:    "above\n\n---\n\nbelow",
+			expected: "above\n\n----\n\nbelow",
+		},
+		{
+			name:     "front_matter",
+			input:    "---\ntitle: Notes\n---\n# Notes",
+			expected: "h1. Notes",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := New().Parse([]byte(test.input))
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, string(actual))
+		})
+	}
+}
+
+func Test_Converter_Parse_Links(t *testing.T) {
+	converter := New()
+	converter.Links = &links.Rewriter{
+		BaseURL:        "https://example.com/docs/",
+		MarkdownToHTML: true,
+	}
+
+	actual, err := converter.Parse([]byte("[setup](./setup.md)"))
+	assert.Nil(t, err)
+	assert.Equal(t, "[setup|https://example.com/docs/setup.html]", string(actual))
+
+	converter.Links.BaseURL = "invalid"
+	_, err = converter.Parse([]byte("[setup](./setup.md)"))
+	assert.ErrorIs(t, err, links.ErrInvalidBaseURL)
+}
@@ -0,0 +1,204 @@
+package jira
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/evilmonkeyinc/markdownconverter"
+	"github.com/gomarkdown/markdown/ast"
+)
+
+// lineBreak forces a line break, such as between paragraphs in a list item
+const lineBreak string = " \\\\ "
+
+// escaper escapes the characters Jira would treat as markup in text
+var escaper *strings.Replacer = strings.NewReplacer(
+	`\`, `\\`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`[`, `\[`,
+	`]`, `\]`,
+	`*`, `\*`,
+	`_`, `\_`,
+	`+`, `\+`,
+	`-`, `\-`,
+	`^`, `\^`,
+	`~`, `\~`,
+	`|`, `\|`,
+	`!`, `\!`,
+)
+
+// blocks renders each block node joined by the separator
+func blocks(nodes []ast.Node, separator string) string {
+	parts := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		if text := block(node); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, separator)
+}
+
+func block(node ast.Node) string {
+	switch node := node.(type) {
+	case *ast.Heading:
+		return fmt.Sprintf("h%d. %s", node.Level, inline(node))
+	case *ast.Paragraph:
+		return inline(node)
+	case *ast.BlockQuote:
+		return "{quote}\n" + blocks(node.Children, "\n\n") + "\n{quote}"
+	case *markdownconverter.Admonition:
+		return "{panel:title=" + node.Label() + "}\n" + blocks(node.Children, "\n\n") + "\n{panel}"
+	case *ast.CodeBlock:
+		code := strings.TrimRight(string(node.Literal), "\n")
+		if diagramType := markdownconverter.DiagramType(node.Info); diagramType != "" {
+			return "{code:title=" + markdownconverter.DiagramLabel(diagramType) + " diagram}\n" + code + "\n{code}"
+		}
+		if fields := strings.Fields(string(node.Info)); len(fields) > 0 {
+			return "{code:" + fields[0] + "}\n" + code + "\n{code}"
+		}
+		return "{code}\n" + code + "\n{code}"
+	case *ast.MathBlock:
+		return "{noformat}\n" + strings.TrimSpace(string(node.Literal)) + "\n{noformat}"
+	case *ast.List:
+		return list(node, "")
+	case *ast.HorizontalRule:
+		return "----"
+	case *ast.Table:
+		return table(node)
+	case *ast.HTMLBlock:
+		return ""
+	default:
+		if container := node.AsContainer(); container != nil {
+			return blocks(container.Children, "\n\n")
+		}
+		return escaper.Replace(strings.TrimSpace(string(node.AsLeaf().Literal)))
+	}
+}
+
+// list renders the list with each item marked by the markers of its parent
+// lists followed by its own, such as ** for an item in a nested list
+func list(node *ast.List, markers string) string {
+	marker := markers + "*"
+	if node.ListFlags&ast.ListTypeOrdered != 0 {
+		marker = markers + "#"
+	}
+
+	lines := make([]string, 0, len(node.Children))
+	for _, item := range node.Children {
+		content := marker + " "
+		nested := make([]string, 0)
+		// text is true when the content ends with the text of a paragraph
+		text := false
+		for _, child := range item.GetChildren() {
+			if child, ok := child.(*ast.List); ok {
+				nested = append(nested, list(child, marker))
+				continue
+			}
+			rendered := block(child)
+			if rendered == "" {
+				continue
+			}
+			switch child.(type) {
+			case *ast.Paragraph, *ast.Heading:
+				// text stays on the line of the item, with forced line breaks
+				if text {
+					content += lineBreak
+				} else if content != marker+" " {
+					content += "\n"
+				}
+				content += strings.ReplaceAll(rendered, "\n", lineBreak)
+				text = true
+			default:
+				// other blocks, such as code blocks, need their own lines
+				content += "\n" + rendered
+				text = false
+			}
+		}
+		lines = append(lines, strings.TrimRight(content, " "))
+		lines = append(lines, nested...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// table renders the table with the header row cells between double pipes
+func table(node *ast.Table) string {
+	rows := make([]string, 0)
+	ast.WalkFunc(node, func(child ast.Node, entering bool) ast.WalkStatus {
+		row, ok := child.(*ast.TableRow)
+		if !ok || !entering {
+			return ast.GoToNext
+		}
+		separator := "|"
+		if _, header := row.Parent.(*ast.TableHeader); header {
+			separator = "||"
+		}
+		cells := make([]string, 0, len(row.Children))
+		for _, cell := range row.Children {
+			text := inline(cell)
+			if text == "" {
+				// empty cells are merged with the next cell
+				text = " "
+			}
+			cells = append(cells, text)
+		}
+		rows = append(rows, separator+strings.Join(cells, separator)+separator)
+		return ast.SkipChildren
+	})
+	return strings.Join(rows, "\n")
+}
+
+// inline renders the inline children of the node
+func inline(node ast.Node) string {
+	builder := &strings.Builder{}
+	for _, child := range node.GetChildren() {
+		builder.WriteString(inlineNode(child))
+	}
+	return strings.TrimSpace(builder.String())
+}
+
+func inlineNode(node ast.Node) string {
+	switch node := node.(type) {
+	case *ast.Text:
+		return escaper.Replace(string(node.Literal))
+	case *ast.Code:
+		return "{{" + escaper.Replace(string(node.Literal)) + "}}"
+	case *ast.Math:
+		return "{{" + escaper.Replace(strings.TrimSpace(string(node.Literal))) + "}}"
+	case *ast.Emph:
+		return "_" + inline(node) + "_"
+	case *ast.Strong:
+		return "*" + inline(node) + "*"
+	case *ast.Del:
+		return "-" + inline(node) + "-"
+	case *ast.Subscript:
+		return "~" + escaper.Replace(string(node.Literal)) + "~"
+	case *ast.Superscript:
+		return "^" + escaper.Replace(string(node.Literal)) + "^"
+	case *ast.Hardbreak:
+		return "\n"
+	case *ast.Link:
+		destination := string(node.Destination)
+		text := inline(node)
+		if text == "" || text == escaper.Replace(destination) {
+			return "[" + destination + "]"
+		}
+		return "[" + text + "|" + destination + "]"
+	case *ast.Image:
+		destination := string(node.Destination)
+		if alt := markdownconverter.PlainText(node); alt != "" {
+			return "!" + destination + "|alt=" + strings.NewReplacer(",", "", "|", "", "!", "").Replace(alt) + "!"
+		}
+		return "!" + destination + "!"
+	case *ast.HTMLSpan:
+		if _, ok := markdownconverter.UnderlineTag(node); ok {
+			return "+"
+		}
+		return ""
+	default:
+		if node.AsContainer() != nil {
+			return inline(node)
+		}
+		return escaper.Replace(string(node.AsLeaf().Literal))
+	}
+}
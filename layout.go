@@ -0,0 +1,68 @@
+package markdownconverter
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Narrower returns the width reduced by the indentation, keeping zero as no
+// wrapping and at least one column otherwise
+func Narrower(width, indentation int) int {
+	if width <= 0 {
+		return 0
+	}
+	if width-indentation < 1 {
+		return 1
+	}
+	return width - indentation
+}
+
+// PrefixLines adds the first prefix to the first line of the text and the
+// other prefix to every following line, without trailing spaces on empty lines
+func PrefixLines(text, first, other string) string {
+	lines := strings.Split(text, "\n")
+	for index, line := range lines {
+		prefix := other
+		if index == 0 {
+			prefix = first
+		}
+		if line == "" {
+			lines[index] = strings.TrimRight(prefix, " ")
+		} else {
+			lines[index] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// BreakWide splits the word before and after each wide character, where text
+// written without spaces, such as Chinese or Japanese, can be wrapped. Marks
+// that combine with a wide character are kept with it.
+func BreakWide(word string) []string {
+	pieces := make([]string, 0, 1)
+	start := 0
+	for index := 0; index < len(word); {
+		r, size := utf8.DecodeRuneInString(word[index:])
+		if RuneWidth(r) < 2 {
+			index += size
+			continue
+		}
+		if index > start {
+			pieces = append(pieces, word[start:index])
+		}
+		end := index + size
+		for end < len(word) {
+			next, nextSize := utf8.DecodeRuneInString(word[end:])
+			if RuneWidth(next) != 0 {
+				break
+			}
+			end += nextSize
+		}
+		pieces = append(pieces, word[index:end])
+		start, index = end, end
+	}
+	if start < len(word) {
+		pieces = append(pieces, word[start:])
+	}
+	return pieces
+}
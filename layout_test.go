@@ -0,0 +1,39 @@
+package markdownconverter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Narrower(t *testing.T) {
+	assert.Equal(t, 8, Narrower(10, 2))
+	assert.Equal(t, 1, Narrower(2, 4))
+	assert.Equal(t, 0, Narrower(0, 2))
+}
+
+func Test_PrefixLines(t *testing.T) {
+	actual := PrefixLines("one\n\ntwo", "- ", "  ")
+	assert.Equal(t, "- one\n\n  two", actual)
+}
+
+func Test_BreakWide(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		word     string
+		expected []string
+	}{
+		{name: "empty", word: "", expected: []string{}},
+		{name: "ascii", word: "word", expected: []string{"word"}},
+		{name: "wide", word: "日本語", expected: []string{"日", "本", "語"}},
+		{name: "mixed", word: "Go言語です.", expected: []string{"Go", "言", "語", "で", "す", "."}},
+		{name: "combining", word: "か\u3099a", expected: []string{"か\u3099", "a"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, BreakWide(test.word))
+		})
+	}
+}
@@ -0,0 +1,118 @@
+// Package links rewrites the destinations of links and images in a parsed
+// markdown document, so relative links still work once the output is published
+package links
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/gomarkdown/markdown/ast"
+)
+
+// ErrInvalidBaseURL is returned when the base URL cannot be parsed
+var ErrInvalidBaseURL error = fmt.Errorf("invalid base url")
+
+// markdownExtensions are the file extensions rewritten by MarkdownToHTML
+var markdownExtensions []string = []string{".md", ".markdown"}
+
+// RewriteFunc is called with the destination of each link or image and
+// returns the destination to use in its place
+type RewriteFunc func(destination string, image bool) string
+
+// Rewriter rewrites the destinations of links and images. Relative links to
+// markdown files are rewritten first, then relative destinations are resolved
+// against the base URL, and finally the custom function is called.
+type Rewriter struct {
+	// BaseURL is used to resolve relative destinations, leave empty to keep
+	// them relative
+	BaseURL string
+	// MarkdownToHTML will change relative links to markdown files to link to
+	// the HTML file of the same name
+	MarkdownToHTML bool
+	// Func is an optional custom rewrite applied to every destination
+	Func RewriteFunc
+}
+
+// Rewrite updates the destination of every link and image in the document
+func (rewriter *Rewriter) Rewrite(document ast.Node) error {
+	base, err := rewriter.base()
+	if err != nil {
+		return err
+	}
+
+	ast.WalkFunc(document, func(node ast.Node, entering bool) ast.WalkStatus {
+		if !entering {
+			return ast.GoToNext
+		}
+		switch node := node.(type) {
+		case *ast.Link:
+			if node.NoteID == 0 {
+				node.Destination = []byte(rewriter.destination(base, string(node.Destination), false))
+			}
+		case *ast.Image:
+			node.Destination = []byte(rewriter.destination(base, string(node.Destination), true))
+		}
+		return ast.GoToNext
+	})
+	return nil
+}
+
+// Destination returns the rewritten destination of a link, or of an image if
+// image is true
+func (rewriter *Rewriter) Destination(destination string, image bool) (string, error) {
+	base, err := rewriter.base()
+	if err != nil {
+		return "", err
+	}
+	return rewriter.destination(base, destination, image), nil
+}
+
+// base returns the parsed base URL, or nil if there is none
+func (rewriter *Rewriter) base() (*url.URL, error) {
+	if rewriter.BaseURL == "" {
+		return nil, nil
+	}
+	base, err := url.Parse(rewriter.BaseURL)
+	if err != nil || !base.IsAbs() {
+		return nil, fmt.Errorf("%w '%s'", ErrInvalidBaseURL, rewriter.BaseURL)
+	}
+	return base, nil
+}
+
+func (rewriter *Rewriter) destination(base *url.URL, destination string, image bool) string {
+	if destination != "" {
+		if parsed, err := url.Parse(destination); err == nil && isRelative(parsed) {
+			if rewriter.MarkdownToHTML && !image {
+				parsed.Path = markdownToHTML(parsed.Path)
+			}
+			if base != nil {
+				parsed = base.ResolveReference(parsed)
+			}
+			destination = parsed.String()
+		}
+	}
+
+	if rewriter.Func != nil {
+		destination = rewriter.Func(destination, image)
+	}
+	return destination
+}
+
+// isRelative returns true if the URL is a relative path, links to a fragment
+// within the same document are not considered relative
+func isRelative(destination *url.URL) bool {
+	return destination.Scheme == "" && destination.Host == "" && destination.Path != ""
+}
+
+// markdownToHTML replaces a markdown file extension with .html
+func markdownToHTML(filepath string) string {
+	extension := path.Ext(filepath)
+	for _, markdownExtension := range markdownExtensions {
+		if strings.EqualFold(extension, markdownExtension) {
+			return strings.TrimSuffix(filepath, extension) + ".html"
+		}
+	}
+	return filepath
+}
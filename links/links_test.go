@@ -0,0 +1,106 @@
+package links
+
+import (
+	"testing"
+
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/gomarkdown/markdown/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+// destinations returns the destination of every link and image in the document
+func destinations(document ast.Node) []string {
+	values := make([]string, 0)
+	ast.WalkFunc(document, func(node ast.Node, entering bool) ast.WalkStatus {
+		if !entering {
+			return ast.GoToNext
+		}
+		switch node := node.(type) {
+		case *ast.Link:
+			values = append(values, string(node.Destination))
+		case *ast.Image:
+			values = append(values, string(node.Destination))
+		}
+		return ast.GoToNext
+	})
+	return values
+}
+
+func Test_Rewriter_Rewrite(t *testing.T) {
+
+	input := "[setup](./docs/setup.md#install) [readme](../README.markdown) ![arch](img/arch.png) ![diagram](docs/diagram.md) [external](https://example.com/page.md) [mail](mailto:team@example.com) [top](#top) [query](guide.md?v=1) [root](/index.md)"
+
+	tests := []struct {
+		name     string
+		rewriter *Rewriter
+		expected []string
+	}{
+		{
+			name:     "no_changes",
+			rewriter: &Rewriter{},
+			expected: []string{"./docs/setup.md#install", "../README.markdown", "img/arch.png", "docs/diagram.md", "https://example.com/page.md", "mailto:team@example.com", "#top", "guide.md?v=1", "/index.md"},
+		},
+		{
+			name:     "markdown_to_html",
+			rewriter: &Rewriter{MarkdownToHTML: true},
+			expected: []string{"./docs/setup.html#install", "../README.html", "img/arch.png", "docs/diagram.md", "https://example.com/page.md", "mailto:team@example.com", "#top", "guide.html?v=1", "/index.html"},
+		},
+		{
+			name:     "base_url",
+			rewriter: &Rewriter{BaseURL: "https://example.com/project/main/"},
+			expected: []string{"https://example.com/project/main/docs/setup.md#install", "https://example.com/project/README.markdown", "https://example.com/project/main/img/arch.png", "https://example.com/project/main/docs/diagram.md", "https://example.com/page.md", "mailto:team@example.com", "#top", "https://example.com/project/main/guide.md?v=1", "https://example.com/index.md"},
+		},
+		{
+			name:     "base_url_and_markdown_to_html",
+			rewriter: &Rewriter{BaseURL: "https://example.com/docs/", MarkdownToHTML: true},
+			expected: []string{"https://example.com/docs/docs/setup.html#install", "https://example.com/README.html", "https://example.com/docs/img/arch.png", "https://example.com/docs/docs/diagram.md", "https://example.com/page.md", "mailto:team@example.com", "#top", "https://example.com/docs/guide.html?v=1", "https://example.com/index.html"},
+		},
+		{
+			name: "custom_func",
+			rewriter: &Rewriter{
+				MarkdownToHTML: true,
+				Func: func(destination string, image bool) string {
+					if image {
+						return "https://cdn.example.com/" + destination
+					}
+					return destination
+				},
+			},
+			expected: []string{"./docs/setup.html#install", "../README.html", "https://cdn.example.com/img/arch.png", "https://cdn.example.com/docs/diagram.md", "https://example.com/page.md", "mailto:team@example.com", "#top", "guide.html?v=1", "/index.html"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			document := parser.New().Parse([]byte(input))
+			err := test.rewriter.Rewrite(document)
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, destinations(document))
+		})
+	}
+}
+
+func Test_Rewriter_Rewrite_InvalidBaseURL(t *testing.T) {
+	for _, baseURL := range []string{"relative/path", "https://exa mple.com"} {
+		document := parser.New().Parse([]byte("[link](page.md)"))
+		err := (&Rewriter{BaseURL: baseURL}).Rewrite(document)
+		assert.ErrorIs(t, err, ErrInvalidBaseURL)
+	}
+}
+
+func Test_Rewriter_Destination(t *testing.T) {
+	rewriter := &Rewriter{BaseURL: "https://example.com/docs/", MarkdownToHTML: true}
+
+	actual, err := rewriter.Destination("setup.md", false)
+	assert.Nil(t, err)
+	assert.Equal(t, "https://example.com/docs/setup.html", actual)
+
+	actual, err = rewriter.Destination("diagram.md", true)
+	assert.Nil(t, err)
+	assert.Equal(t, "https://example.com/docs/diagram.md", actual)
+
+	rewriter.BaseURL = "relative/path"
+	actual, err = rewriter.Destination("setup.md", false)
+	assert.Equal(t, "", actual)
+	assert.ErrorIs(t, err, ErrInvalidBaseURL)
+}
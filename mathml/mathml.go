@@ -0,0 +1,581 @@
+// Package mathml translates a subset of TeX math, as used in markdown
+// documents, to MathML that browsers can display without any scripts
+package mathml
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+)
+
+const namespace string = "http://www.w3.org/1998/Math/MathML"
+
+var (
+	// ErrUnsupported is returned when the TeX uses a command or environment
+	// that cannot be translated
+	ErrUnsupported error = fmt.Errorf("unsupported command")
+	// ErrSyntax is returned when the TeX is not well formed, such as having
+	// unbalanced braces or a missing argument
+	ErrSyntax error = fmt.Errorf("invalid syntax")
+)
+
+// Translate returns the MathML for the TeX, as a block if display is true or
+// inline with the surrounding text otherwise. The TeX is kept as an annotation
+// so it can be copied from the page.
+func Translate(tex string, display bool) (string, error) {
+	translator := &translator{input: []rune(tex)}
+	content, err := translator.expression()
+	if err != nil {
+		return "", err
+	}
+	if !translator.eof() {
+		return "", fmt.Errorf("%w unexpected '%c'", ErrSyntax, translator.peek())
+	}
+
+	builder := &strings.Builder{}
+	builder.WriteString(`<math xmlns="` + namespace + `"`)
+	if display {
+		builder.WriteString(` display="block"`)
+	}
+	builder.WriteString("><semantics>")
+	builder.WriteString(row(content))
+	builder.WriteString(`<annotation encoding="application/x-tex">`)
+	builder.WriteString(html.EscapeString(strings.TrimSpace(tex)))
+	builder.WriteString("</annotation></semantics></math>")
+	return builder.String(), nil
+}
+
+// translator reads the TeX one rune at a time, building the MathML elements
+type translator struct {
+	input []rune
+	pos   int
+	// variant is the mathvariant set by a font command such as \mathbf
+	variant string
+}
+
+func (translator *translator) eof() bool {
+	return translator.pos >= len(translator.input)
+}
+
+func (translator *translator) peek() rune {
+	if translator.eof() {
+		return 0
+	}
+	return translator.input[translator.pos]
+}
+
+func (translator *translator) skipSpace() {
+	for !translator.eof() && unicode.IsSpace(translator.peek()) {
+		translator.pos++
+	}
+}
+
+// peekCommand returns the name of the command at the current position
+// without consuming it, or an empty string if there is no command
+func (translator *translator) peekCommand() string {
+	if translator.peek() != '\\' || translator.pos+1 >= len(translator.input) {
+		return ""
+	}
+	end := translator.pos + 1
+	for end < len(translator.input) && isCommandLetter(translator.input[end]) {
+		end++
+	}
+	if end == translator.pos+1 {
+		return string(translator.input[end])
+	}
+	return string(translator.input[translator.pos+1 : end])
+}
+
+// command consumes and returns the name of the command at the current position
+func (translator *translator) command() string {
+	name := translator.peekCommand()
+	translator.pos += 1 + len([]rune(name))
+	return name
+}
+
+// expression translates elements until the end of a group, a cell, a row, or
+// the end of the input
+func (translator *translator) expression() ([]string, error) {
+	elements := make([]string, 0)
+	for {
+		translator.skipSpace()
+		if translator.eof() {
+			return elements, nil
+		}
+		switch translator.peek() {
+		case '}', '&':
+			return elements, nil
+		case '\\':
+			switch translator.peekCommand() {
+			case "\\", "right", "end":
+				return elements, nil
+			}
+		}
+
+		element, limits, err := translator.atom(false)
+		if err != nil {
+			return nil, err
+		}
+		if element, err = translator.scripts(element, limits); err != nil {
+			return nil, err
+		}
+		elements = append(elements, element)
+	}
+}
+
+// group translates a braced group, the opening brace must be next
+func (translator *translator) group() (string, error) {
+	translator.pos++
+	elements, err := translator.expression()
+	if err != nil {
+		return "", err
+	}
+	if translator.peek() != '}' {
+		return "", fmt.Errorf("%w missing '}'", ErrSyntax)
+	}
+	translator.pos++
+	return row(elements), nil
+}
+
+// argument translates the argument of a command or script, which is either a
+// braced group or a single token
+func (translator *translator) argument() (string, error) {
+	translator.skipSpace()
+	if translator.eof() {
+		return "", fmt.Errorf("%w missing argument", ErrSyntax)
+	}
+	element, _, err := translator.atom(true)
+	return element, err
+}
+
+// atom translates the next element, a single rune if single is true, returning
+// true if it is an operator with limits
+func (translator *translator) atom(single bool) (string, bool, error) {
+	r := translator.peek()
+	switch {
+	case r == '{':
+		element, err := translator.group()
+		return element, false, err
+	case r == '}' || r == '&':
+		return "", false, fmt.Errorf("%w unexpected '%c'", ErrSyntax, r)
+	case r == '^' || r == '_':
+		if single {
+			return "", false, fmt.Errorf("%w unexpected '%c'", ErrSyntax, r)
+		}
+		return "<mrow></mrow>", false, nil
+	case r == '\\':
+		return translator.control()
+	case unicode.IsDigit(r) || (r == '.' && translator.pos+1 < len(translator.input) && unicode.IsDigit(translator.input[translator.pos+1])):
+		return translator.number(single), false, nil
+	case unicode.IsLetter(r):
+		translator.pos++
+		return translator.identifier(string(r)), false, nil
+	default:
+		translator.pos++
+		return operator(string(r)), false, nil
+	}
+}
+
+// number translates a number, or a single digit if single is true
+func (translator *translator) number(single bool) string {
+	start := translator.pos
+	translator.pos++
+	if !single {
+		decimal := translator.input[start] == '.'
+		for !translator.eof() {
+			r := translator.peek()
+			if r == '.' && !decimal && translator.pos+1 < len(translator.input) && unicode.IsDigit(translator.input[translator.pos+1]) {
+				decimal = true
+			} else if !unicode.IsDigit(r) {
+				break
+			}
+			translator.pos++
+		}
+	}
+	return element("mn", translator.attributes(), string(translator.input[start:translator.pos]))
+}
+
+// identifier translates a letter or symbol to an identifier in the current
+// font variant
+func (translator *translator) identifier(text string) string {
+	return element("mi", translator.attributes(), text)
+}
+
+func (translator *translator) attributes() string {
+	if translator.variant == "" {
+		return ""
+	}
+	return ` mathvariant="` + translator.variant + `"`
+}
+
+// scripts translates any subscripts, superscripts, and primes following the base
+func (translator *translator) scripts(base string, limits bool) (string, error) {
+	var sub, sup, primes string
+	for {
+		translator.skipSpace()
+		switch translator.peek() {
+		case '_':
+			if sub != "" {
+				return "", fmt.Errorf("%w double subscript", ErrSyntax)
+			}
+			translator.pos++
+			var err error
+			if sub, err = translator.argument(); err != nil {
+				return "", err
+			}
+			continue
+		case '^':
+			if sup != "" {
+				return "", fmt.Errorf("%w double superscript", ErrSyntax)
+			}
+			translator.pos++
+			var err error
+			if sup, err = translator.argument(); err != nil {
+				return "", err
+			}
+			continue
+		case '\'':
+			primes += "′"
+			translator.pos++
+			continue
+		}
+		break
+	}
+	if primes != "" {
+		if sup == "" {
+			sup = operator(primes)
+		} else {
+			sup = "<mrow>" + operator(primes) + sup + "</mrow>"
+		}
+	}
+
+	under, over, both := "msub", "msup", "msubsup"
+	if limits {
+		under, over, both = "munder", "mover", "munderover"
+	}
+	switch {
+	case sub != "" && sup != "":
+		return "<" + both + ">" + base + sub + sup + "</" + both + ">", nil
+	case sub != "":
+		return "<" + under + ">" + base + sub + "</" + under + ">", nil
+	case sup != "":
+		return "<" + over + ">" + base + sup + "</" + over + ">", nil
+	default:
+		return base, nil
+	}
+}
+
+// control translates the command at the current position
+func (translator *translator) control() (string, bool, error) {
+	name := translator.command()
+	if name == "" {
+		return "", false, fmt.Errorf("%w trailing '\\'", ErrSyntax)
+	}
+
+	if symbol, ok := greek[name]; ok {
+		if unicode.IsUpper([]rune(name)[0]) && translator.variant == "" {
+			return `<mi mathvariant="normal">` + symbol + "</mi>", false, nil
+		}
+		return translator.identifier(symbol), false, nil
+	}
+	if symbol, ok := identifiers[name]; ok {
+		return translator.identifier(symbol), false, nil
+	}
+	if symbol, ok := operators[name]; ok {
+		return operator(symbol), false, nil
+	}
+	if symbol, ok := largeOperators[name]; ok {
+		return `<mo largeop="true">` + symbol + "</mo>", limits[name], nil
+	}
+	if text, ok := functions[name]; ok {
+		return element("mi", "", text), limits[name], nil
+	}
+	if width, ok := spaces[name]; ok {
+		return `<mspace width="` + width + `"></mspace>`, false, nil
+	}
+	if variant, ok := variants[name]; ok {
+		previous := translator.variant
+		translator.variant = variant
+		content, err := translator.argument()
+		translator.variant = previous
+		return content, false, err
+	}
+	if accent, ok := accents[name]; ok {
+		content, err := translator.argument()
+		if err != nil {
+			return "", false, err
+		}
+		tag, attribute := "mover", "accent"
+		if accent.underline {
+			tag, attribute = "munder", "accentunder"
+		}
+		stretchy := "false"
+		if accent.stretchy {
+			stretchy = "true"
+		}
+		return "<" + tag + " " + attribute + `="true">` + content + `<mo stretchy="` + stretchy + `">` + html.EscapeString(accent.symbol) + "</mo></" + tag + ">", false, nil
+	}
+
+	switch name {
+	case "{", "}", "|", "#", "$", "%", "&", "_":
+		if name == "|" {
+			name = "‖"
+		}
+		return operator(name), false, nil
+	case "frac", "dfrac", "tfrac", "binom":
+		return translator.fraction(name)
+	case "sqrt":
+		return translator.root()
+	case "text", "textrm", "textit", "textbf", "mbox":
+		text, err := translator.text()
+		return element("mtext", "", text), false, err
+	case "operatorname":
+		text, err := translator.text()
+		return element("mi", "", text), false, err
+	case "left":
+		return translator.fenced()
+	case "begin":
+		return translator.environment()
+	}
+	return "", false, fmt.Errorf("%w '\\%s'", ErrUnsupported, name)
+}
+
+// fraction translates a fraction or binomial coefficient
+func (translator *translator) fraction(name string) (string, bool, error) {
+	numerator, err := translator.argument()
+	if err != nil {
+		return "", false, err
+	}
+	denominator, err := translator.argument()
+	if err != nil {
+		return "", false, err
+	}
+
+	switch name {
+	case "binom":
+		return `<mrow><mo>(</mo><mfrac linethickness="0">` + numerator + denominator + "</mfrac><mo>)</mo></mrow>", false, nil
+	case "dfrac":
+		return `<mstyle displaystyle="true"><mfrac>` + numerator + denominator + "</mfrac></mstyle>", false, nil
+	case "tfrac":
+		return `<mstyle displaystyle="false"><mfrac>` + numerator + denominator + "</mfrac></mstyle>", false, nil
+	default:
+		return "<mfrac>" + numerator + denominator + "</mfrac>", false, nil
+	}
+}
+
+// root translates a square root, or an nth root if the index is given
+func (translator *translator) root() (string, bool, error) {
+	translator.skipSpace()
+	index := ""
+	if translator.peek() == '[' {
+		translator.pos++
+		elements := make([]string, 0)
+		for {
+			translator.skipSpace()
+			if translator.eof() {
+				return "", false, fmt.Errorf("%w missing ']'", ErrSyntax)
+			}
+			if translator.peek() == ']' {
+				translator.pos++
+				break
+			}
+			element, _, err := translator.atom(false)
+			if err != nil {
+				return "", false, err
+			}
+			elements = append(elements, element)
+		}
+		index = row(elements)
+	}
+
+	content, err := translator.argument()
+	if err != nil {
+		return "", false, err
+	}
+	if index != "" {
+		return "<mroot>" + content + index + "</mroot>", false, nil
+	}
+	return "<msqrt>" + content + "</msqrt>", false, nil
+}
+
+// text reads the braced argument of a text command as it is written
+func (translator *translator) text() (string, error) {
+	translator.skipSpace()
+	if translator.peek() != '{' {
+		return "", fmt.Errorf("%w missing argument", ErrSyntax)
+	}
+	translator.pos++
+
+	start, depth := translator.pos, 0
+	for ; !translator.eof(); translator.pos++ {
+		switch translator.peek() {
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				text := string(translator.input[start:translator.pos])
+				translator.pos++
+				return text, nil
+			}
+			depth--
+		}
+	}
+	return "", fmt.Errorf("%w missing '}'", ErrSyntax)
+}
+
+// delimiter reads the delimiter following \left or \right, an empty string is
+// returned for the invisible "." delimiter
+func (translator *translator) delimiter() (string, error) {
+	translator.skipSpace()
+	if translator.eof() {
+		return "", fmt.Errorf("%w missing delimiter", ErrSyntax)
+	}
+	r := translator.peek()
+	if r != '\\' {
+		translator.pos++
+		switch r {
+		case '.':
+			return "", nil
+		case '(', ')', '[', ']', '|', '/':
+			return string(r), nil
+		}
+		return "", fmt.Errorf("%w invalid delimiter '%c'", ErrSyntax, r)
+	}
+
+	name := translator.command()
+	switch name {
+	case "{", "}":
+		return name, nil
+	case "|":
+		return "‖", nil
+	}
+	if symbol, ok := operators[name]; ok {
+		return symbol, nil
+	}
+	return "", fmt.Errorf("%w delimiter '\\%s'", ErrUnsupported, name)
+}
+
+// fenced translates a \left ... \right pair of stretchy delimiters
+func (translator *translator) fenced() (string, bool, error) {
+	open, err := translator.delimiter()
+	if err != nil {
+		return "", false, err
+	}
+	content, err := translator.expression()
+	if err != nil {
+		return "", false, err
+	}
+	if translator.peekCommand() != "right" {
+		return "", false, fmt.Errorf("%w missing '\\right'", ErrSyntax)
+	}
+	translator.command()
+	close, err := translator.delimiter()
+	if err != nil {
+		return "", false, err
+	}
+	return "<mrow>" + fence(open) + strings.Join(content, "") + fence(close) + "</mrow>", false, nil
+}
+
+// environment translates a matrix like environment into a table
+func (translator *translator) environment() (string, bool, error) {
+	name, err := translator.text()
+	if err != nil {
+		return "", false, err
+	}
+	delimiters, ok := environments[name]
+	if !ok {
+		return "", false, fmt.Errorf("%w environment '%s'", ErrUnsupported, name)
+	}
+	if name == "array" {
+		if _, err := translator.text(); err != nil {
+			return "", false, err
+		}
+	}
+
+	rows := make([]string, 0)
+	cells := make([]string, 0)
+	for {
+		content, err := translator.expression()
+		if err != nil {
+			return "", false, err
+		}
+		cells = append(cells, "<mtd>"+row(content)+"</mtd>")
+
+		if translator.eof() {
+			return "", false, fmt.Errorf("%w missing '\\end{%s}'", ErrSyntax, name)
+		}
+		if translator.peek() == '&' {
+			translator.pos++
+			continue
+		}
+		command := translator.command()
+		if command == "\\" {
+			rows = append(rows, "<mtr>"+strings.Join(cells, "")+"</mtr>")
+			cells = make([]string, 0)
+			continue
+		}
+		if command != "end" {
+			return "", false, fmt.Errorf("%w unexpected '\\%s'", ErrSyntax, command)
+		}
+		end, err := translator.text()
+		if err != nil {
+			return "", false, err
+		}
+		if end != name {
+			return "", false, fmt.Errorf("%w '\\begin{%s}' ended by '\\end{%s}'", ErrSyntax, name, end)
+		}
+		break
+	}
+	if len(cells) > 1 || cells[0] != "<mtd><mrow></mrow></mtd>" {
+		rows = append(rows, "<mtr>"+strings.Join(cells, "")+"</mtr>")
+	}
+
+	attributes := ""
+	switch name {
+	case "cases", "aligned", "align", "align*":
+		attributes = ` columnalign="left"`
+	}
+	table := "<mtable" + attributes + ">" + strings.Join(rows, "") + "</mtable>"
+	if delimiters[0] == "" && delimiters[1] == "" {
+		return table, false, nil
+	}
+	return "<mrow>" + fence(delimiters[0]) + table + fence(delimiters[1]) + "</mrow>", false, nil
+}
+
+// row joins the elements, wrapping them in an mrow unless there is only one
+func row(elements []string) string {
+	if len(elements) == 1 {
+		return elements[0]
+	}
+	return "<mrow>" + strings.Join(elements, "") + "</mrow>"
+}
+
+// fence returns a stretchy delimiter, or nothing for an invisible delimiter
+func fence(delimiter string) string {
+	if delimiter == "" {
+		return ""
+	}
+	return `<mo fence="true" stretchy="true">` + html.EscapeString(delimiter) + "</mo>"
+}
+
+// operator returns the operator element for the symbol, using the proper
+// minus sign for hyphens
+func operator(symbol string) string {
+	switch symbol {
+	case "-":
+		symbol = "−"
+	case "*":
+		symbol = "∗"
+	}
+	return element("mo", "", symbol)
+}
+
+func element(tag, attributes, text string) string {
+	return "<" + tag + attributes + ">" + html.EscapeString(text) + "</" + tag + ">"
+}
+
+// isCommandLetter returns true if the rune can be part of a command name
+func isCommandLetter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
@@ -0,0 +1,170 @@
+package mathml
+
+import (
+	"html"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Translate(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "identifiers_and_operators",
+			input:    "a - b * c < d",
+			expected: "<mrow><mi>a</mi><mo>−</mo><mi>b</mi><mo>∗</mo><mi>c</mi><mo>&lt;</mo><mi>d</mi></mrow>",
+		},
+		{
+			name:     "numbers",
+			input:    "3.14 + .5 + 12",
+			expected: "<mrow><mn>3.14</mn><mo>+</mo><mn>.5</mn><mo>+</mo><mn>12</mn></mrow>",
+		},
+		{
+			name:     "scripts",
+			input:    "x^2 + x_i^{10} + x^23",
+			expected: "<mrow><msup><mi>x</mi><mn>2</mn></msup><mo>+</mo><msubsup><mi>x</mi><mi>i</mi><mn>10</mn></msubsup><mo>+</mo><msup><mi>x</mi><mn>2</mn></msup><mn>3</mn></mrow>",
+		},
+		{
+			name:     "primes",
+			input:    "f''(x) + g'^2",
+			expected: "<mrow><msup><mi>f</mi><mo>′′</mo></msup><mo>(</mo><mi>x</mi><mo>)</mo><mo>+</mo><msup><mi>g</mi><mrow><mo>′</mo><mn>2</mn></mrow></msup></mrow>",
+		},
+		{
+			name:     "greek",
+			input:    `\alpha\Omega`,
+			expected: `<mrow><mi>α</mi><mi mathvariant="normal">Ω</mi></mrow>`,
+		},
+		{
+			name:     "symbols",
+			input:    `\infty \leq \to`,
+			expected: "<mrow><mi>∞</mi><mo>≤</mo><mo>→</mo></mrow>",
+		},
+		{
+			name:     "fractions",
+			input:    `\frac{1}{2} \dfrac12 \binom{n}{k}`,
+			expected: `<mrow><mfrac><mn>1</mn><mn>2</mn></mfrac><mstyle displaystyle="true"><mfrac><mn>1</mn><mn>2</mn></mfrac></mstyle><mrow><mo>(</mo><mfrac linethickness="0"><mi>n</mi><mi>k</mi></mfrac><mo>)</mo></mrow></mrow>`,
+		},
+		{
+			name:     "roots",
+			input:    `\sqrt{x} \sqrt[n]{x}`,
+			expected: "<mrow><msqrt><mi>x</mi></msqrt><mroot><mi>x</mi><mi>n</mi></mroot></mrow>",
+		},
+		{
+			name:     "large_operators",
+			input:    `\sum_{i=1}^n \int_0^1`,
+			expected: `<mrow><munderover><mo largeop="true">∑</mo><mrow><mi>i</mi><mo>=</mo><mn>1</mn></mrow><mi>n</mi></munderover><msubsup><mo largeop="true">∫</mo><mn>0</mn><mn>1</mn></msubsup></mrow>`,
+		},
+		{
+			name:     "functions",
+			input:    `\sin x + \lim_{x \to 0}`,
+			expected: "<mrow><mi>sin</mi><mi>x</mi><mo>+</mo><munder><mi>lim</mi><mrow><mi>x</mi><mo>→</mo><mn>0</mn></mrow></munder></mrow>",
+		},
+		{
+			name:     "text",
+			input:    `\text{if } x \operatorname{sgn}`,
+			expected: "<mrow><mtext>if </mtext><mi>x</mi><mi>sgn</mi></mrow>",
+		},
+		{
+			name:     "variants",
+			input:    `\mathbb{R}^n \mathbf{v1}`,
+			expected: `<mrow><msup><mi mathvariant="double-struck">R</mi><mi>n</mi></msup><mrow><mi mathvariant="bold">v</mi><mn mathvariant="bold">1</mn></mrow></mrow>`,
+		},
+		{
+			name:     "accents",
+			input:    `\hat{x} \overline{ab}`,
+			expected: `<mrow><mover accent="true"><mi>x</mi><mo stretchy="false">^</mo></mover><mover accent="true"><mrow><mi>a</mi><mi>b</mi></mrow><mo stretchy="true">‾</mo></mover></mrow>`,
+		},
+		{
+			name:     "spaces",
+			input:    `a\,b\quad c`,
+			expected: `<mrow><mi>a</mi><mspace width="0.1667em"></mspace><mi>b</mi><mspace width="1em"></mspace><mi>c</mi></mrow>`,
+		},
+		{
+			name:     "escaped",
+			input:    `\{ \% \}`,
+			expected: "<mrow><mo>{</mo><mo>%</mo><mo>}</mo></mrow>",
+		},
+		{
+			name:     "fenced",
+			input:    `\left( x \right.`,
+			expected: `<mrow><mo fence="true" stretchy="true">(</mo><mi>x</mi></mrow>`,
+		},
+		{
+			name:     "fenced_commands",
+			input:    `\left\langle x \right\|`,
+			expected: `<mrow><mo fence="true" stretchy="true">⟨</mo><mi>x</mi><mo fence="true" stretchy="true">‖</mo></mrow>`,
+		},
+		{
+			name:     "matrix",
+			input:    `\begin{pmatrix} 1 & 0 \\ 0 & 1 \\ \end{pmatrix}`,
+			expected: `<mrow><mo fence="true" stretchy="true">(</mo><mtable><mtr><mtd><mn>1</mn></mtd><mtd><mn>0</mn></mtd></mtr><mtr><mtd><mn>0</mn></mtd><mtd><mn>1</mn></mtd></mtr></mtable><mo fence="true" stretchy="true">)</mo></mrow>`,
+		},
+		{
+			name:     "cases",
+			input:    `\begin{cases} 1 & x > 0 \\ 0 \end{cases}`,
+			expected: `<mrow><mo fence="true" stretchy="true">{</mo><mtable columnalign="left"><mtr><mtd><mn>1</mn></mtd><mtd><mrow><mi>x</mi><mo>&gt;</mo><mn>0</mn></mrow></mtd></mtr><mtr><mtd><mn>0</mn></mtd></mtr></mtable></mrow>`,
+		},
+		{
+			name:     "empty_base",
+			input:    `{}^{14}C`,
+			expected: "<mrow><msup><mrow></mrow><mn>14</mn></msup><mi>C</mi></mrow>",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := Translate(test.input, false)
+			assert.Nil(t, err)
+			assert.Equal(t, `<math xmlns="http://www.w3.org/1998/Math/MathML"><semantics>`+test.expected+`<annotation encoding="application/x-tex">`+html.EscapeString(test.input)+`</annotation></semantics></math>`, actual)
+		})
+	}
+}
+
+func Test_Translate_Display(t *testing.T) {
+	actual, err := Translate(" x < 1 ", true)
+	assert.Nil(t, err)
+	assert.Equal(t, `<math xmlns="http://www.w3.org/1998/Math/MathML" display="block"><semantics><mrow><mi>x</mi><mo>&lt;</mo><mn>1</mn></mrow><annotation encoding="application/x-tex">x &lt; 1</annotation></semantics></math>`, actual)
+}
+
+func Test_Translate_Errors(t *testing.T) {
+
+	tests := []struct {
+		input    string
+		expected error
+	}{
+		{input: `\unknown`, expected: ErrUnsupported},
+		{input: `\begin{tabular}\end{tabular}`, expected: ErrUnsupported},
+		{input: `\left\uparrow x \right)`, expected: nil},
+		{input: `{x`, expected: ErrSyntax},
+		{input: `x}`, expected: ErrSyntax},
+		{input: `x^`, expected: ErrSyntax},
+		{input: `x^2^3`, expected: ErrSyntax},
+		{input: `x_1_2`, expected: ErrSyntax},
+		{input: `\frac{1}`, expected: ErrSyntax},
+		{input: `\sqrt[3{x}`, expected: ErrSyntax},
+		{input: `\text x`, expected: ErrSyntax},
+		{input: `\left( x`, expected: ErrSyntax},
+		{input: `\left< x \right)`, expected: ErrSyntax},
+		{input: `\begin{matrix} x`, expected: ErrSyntax},
+		{input: `\begin{matrix} x \end{pmatrix}`, expected: ErrSyntax},
+		{input: `a \\ b`, expected: ErrSyntax},
+		{input: `a & b`, expected: ErrSyntax},
+		{input: `x\`, expected: ErrSyntax},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			_, err := Translate(test.input, false)
+			if test.expected == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorIs(t, err, test.expected)
+			}
+		})
+	}
+}
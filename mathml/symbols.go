@@ -0,0 +1,294 @@
+package mathml
+
+// greek maps commands to Greek letters, uppercase letters are upright
+var greek map[string]string = map[string]string{
+	"alpha":      "α",
+	"beta":       "β",
+	"gamma":      "γ",
+	"delta":      "δ",
+	"epsilon":    "ϵ",
+	"varepsilon": "ε",
+	"zeta":       "ζ",
+	"eta":        "η",
+	"theta":      "θ",
+	"vartheta":   "ϑ",
+	"iota":       "ι",
+	"kappa":      "κ",
+	"lambda":     "λ",
+	"mu":         "μ",
+	"nu":         "ν",
+	"xi":         "ξ",
+	"pi":         "π",
+	"varpi":      "ϖ",
+	"rho":        "ρ",
+	"varrho":     "ϱ",
+	"sigma":      "σ",
+	"varsigma":   "ς",
+	"tau":        "τ",
+	"upsilon":    "υ",
+	"phi":        "ϕ",
+	"varphi":     "φ",
+	"chi":        "χ",
+	"psi":        "ψ",
+	"omega":      "ω",
+	"Gamma":      "Γ",
+	"Delta":      "Δ",
+	"Theta":      "Θ",
+	"Lambda":     "Λ",
+	"Xi":         "Ξ",
+	"Pi":         "Π",
+	"Sigma":      "Σ",
+	"Upsilon":    "Υ",
+	"Phi":        "Φ",
+	"Psi":        "Ψ",
+	"Omega":      "Ω",
+}
+
+// identifiers maps commands to symbols rendered as identifiers
+var identifiers map[string]string = map[string]string{
+	"infty":      "∞",
+	"partial":    "∂",
+	"nabla":      "∇",
+	"emptyset":   "∅",
+	"varnothing": "∅",
+	"hbar":       "ℏ",
+	"ell":        "ℓ",
+	"aleph":      "ℵ",
+	"Re":         "ℜ",
+	"Im":         "ℑ",
+	"wp":         "℘",
+	"imath":      "ı",
+	"jmath":      "ȷ",
+}
+
+// operators maps commands to symbols rendered as operators
+var operators map[string]string = map[string]string{
+	"times":          "×",
+	"cdot":           "⋅",
+	"div":            "÷",
+	"pm":             "±",
+	"mp":             "∓",
+	"ast":            "∗",
+	"star":           "⋆",
+	"circ":           "∘",
+	"bullet":         "∙",
+	"oplus":          "⊕",
+	"otimes":         "⊗",
+	"leq":            "≤",
+	"le":             "≤",
+	"geq":            "≥",
+	"ge":             "≥",
+	"neq":            "≠",
+	"ne":             "≠",
+	"ll":             "≪",
+	"gg":             "≫",
+	"approx":         "≈",
+	"equiv":          "≡",
+	"sim":            "∼",
+	"simeq":          "≃",
+	"cong":           "≅",
+	"propto":         "∝",
+	"in":             "∈",
+	"notin":          "∉",
+	"ni":             "∋",
+	"subset":         "⊂",
+	"subseteq":       "⊆",
+	"supset":         "⊃",
+	"supseteq":       "⊇",
+	"cup":            "∪",
+	"cap":            "∩",
+	"setminus":       "∖",
+	"forall":         "∀",
+	"exists":         "∃",
+	"nexists":        "∄",
+	"neg":            "¬",
+	"lnot":           "¬",
+	"land":           "∧",
+	"wedge":          "∧",
+	"lor":            "∨",
+	"vee":            "∨",
+	"to":             "→",
+	"rightarrow":     "→",
+	"leftarrow":      "←",
+	"gets":           "←",
+	"leftrightarrow": "↔",
+	"Rightarrow":     "⇒",
+	"implies":        "⇒",
+	"Leftarrow":      "⇐",
+	"Leftrightarrow": "⇔",
+	"iff":            "⇔",
+	"mapsto":         "↦",
+	"uparrow":        "↑",
+	"downarrow":      "↓",
+	"perp":           "⊥",
+	"parallel":       "∥",
+	"mid":            "∣",
+	"angle":          "∠",
+	"ldots":          "…",
+	"dots":           "…",
+	"cdots":          "⋯",
+	"vdots":          "⋮",
+	"ddots":          "⋱",
+	"colon":          ":",
+	"langle":         "⟨",
+	"rangle":         "⟩",
+	"lfloor":         "⌊",
+	"rfloor":         "⌋",
+	"lceil":          "⌈",
+	"rceil":          "⌉",
+	"vert":           "|",
+	"lvert":          "|",
+	"rvert":          "|",
+	"Vert":           "‖",
+	"lVert":          "‖",
+	"rVert":          "‖",
+	"prime":          "′",
+	"degree":         "°",
+}
+
+// largeOperators maps commands to operators drawn larger in display math
+var largeOperators map[string]string = map[string]string{
+	"sum":       "∑",
+	"prod":      "∏",
+	"coprod":    "∐",
+	"int":       "∫",
+	"iint":      "∬",
+	"iiint":     "∭",
+	"oint":      "∮",
+	"bigcup":    "⋃",
+	"bigcap":    "⋂",
+	"bigoplus":  "⨁",
+	"bigotimes": "⨂",
+	"bigvee":    "⋁",
+	"bigwedge":  "⋀",
+}
+
+// limits are the operators and functions with limits placed above and below
+// in display math rather than to the side
+var limits map[string]bool = map[string]bool{
+	"sum":       true,
+	"prod":      true,
+	"coprod":    true,
+	"bigcup":    true,
+	"bigcap":    true,
+	"bigoplus":  true,
+	"bigotimes": true,
+	"bigvee":    true,
+	"bigwedge":  true,
+	"lim":       true,
+	"liminf":    true,
+	"limsup":    true,
+	"max":       true,
+	"min":       true,
+	"sup":       true,
+	"inf":       true,
+	"det":       true,
+	"gcd":       true,
+}
+
+// functions are the commands rendered as the upright function name
+var functions map[string]string = map[string]string{
+	"sin":    "sin",
+	"cos":    "cos",
+	"tan":    "tan",
+	"cot":    "cot",
+	"sec":    "sec",
+	"csc":    "csc",
+	"arcsin": "arcsin",
+	"arccos": "arccos",
+	"arctan": "arctan",
+	"sinh":   "sinh",
+	"cosh":   "cosh",
+	"tanh":   "tanh",
+	"log":    "log",
+	"ln":     "ln",
+	"lg":     "lg",
+	"exp":    "exp",
+	"lim":    "lim",
+	"liminf": "lim inf",
+	"limsup": "lim sup",
+	"max":    "max",
+	"min":    "min",
+	"sup":    "sup",
+	"inf":    "inf",
+	"det":    "det",
+	"gcd":    "gcd",
+	"deg":    "deg",
+	"dim":    "dim",
+	"ker":    "ker",
+	"arg":    "arg",
+	"Pr":     "Pr",
+	"mod":    "mod",
+}
+
+// accent is a symbol drawn over or under an expression
+type accent struct {
+	symbol    string
+	stretchy  bool
+	underline bool
+}
+
+// accents maps commands to the accent drawn over or under their argument
+var accents map[string]accent = map[string]accent{
+	"hat":        {symbol: "^"},
+	"widehat":    {symbol: "^", stretchy: true},
+	"bar":        {symbol: "¯"},
+	"overline":   {symbol: "‾", stretchy: true},
+	"underline":  {symbol: "_", stretchy: true, underline: true},
+	"vec":        {symbol: "→"},
+	"dot":        {symbol: "˙"},
+	"ddot":       {symbol: "¨"},
+	"tilde":      {symbol: "~"},
+	"widetilde":  {symbol: "~", stretchy: true},
+	"check":      {symbol: "ˇ"},
+	"breve":      {symbol: "˘"},
+	"acute":      {symbol: "´"},
+	"grave":      {symbol: "`"},
+	"overbrace":  {symbol: "⏞", stretchy: true},
+	"underbrace": {symbol: "⏟", stretchy: true, underline: true},
+}
+
+// variants maps font commands to their MathML mathvariant
+var variants map[string]string = map[string]string{
+	"mathrm":     "normal",
+	"mathbf":     "bold",
+	"boldsymbol": "bold-italic",
+	"mathit":     "italic",
+	"mathbb":     "double-struck",
+	"mathcal":    "script",
+	"mathfrak":   "fraktur",
+	"mathsf":     "sans-serif",
+	"mathtt":     "monospace",
+}
+
+// spaces maps spacing commands to their width
+var spaces map[string]string = map[string]string{
+	",":          "0.1667em",
+	"thinspace":  "0.1667em",
+	":":          "0.2222em",
+	">":          "0.2222em",
+	"medspace":   "0.2222em",
+	";":          "0.2778em",
+	"thickspace": "0.2778em",
+	"!":          "-0.1667em",
+	" ":          "0.25em",
+	"quad":       "1em",
+	"qquad":      "2em",
+}
+
+// environments maps the supported matrix environments to their delimiters
+var environments map[string][2]string = map[string][2]string{
+	"matrix":      {"", ""},
+	"smallmatrix": {"", ""},
+	"pmatrix":     {"(", ")"},
+	"bmatrix":     {"[", "]"},
+	"Bmatrix":     {"{", "}"},
+	"vmatrix":     {"|", "|"},
+	"Vmatrix":     {"‖", "‖"},
+	"cases":       {"{", ""},
+	"aligned":     {"", ""},
+	"align":       {"", ""},
+	"align*":      {"", ""},
+	"gathered":    {"", ""},
+	"array":       {"", ""},
+}
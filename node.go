@@ -0,0 +1,58 @@
+package markdownconverter
+
+import (
+	"strings"
+
+	"github.com/gomarkdown/markdown/ast"
+)
+
+// underlineTags are the inline HTML tags that start or end an underline, with
+// true for the opening tags
+var underlineTags map[string]bool = map[string]bool{
+	"<u>":    true,
+	"</u>":   false,
+	"<ins>":  true,
+	"</ins>": false,
+}
+
+// PlainText returns the text of the inline children of the node without any
+// formatting
+func PlainText(node ast.Node) string {
+	builder := &strings.Builder{}
+	ast.WalkFunc(node, func(child ast.Node, entering bool) ast.WalkStatus {
+		if leaf := child.AsLeaf(); leaf != nil && entering {
+			builder.Write(leaf.Literal)
+		}
+		return ast.GoToNext
+	})
+	return strings.TrimSpace(builder.String())
+}
+
+// OnlyImage returns the image if it is the only content of the paragraph,
+// ignoring the empty text nodes the parser leaves around inline nodes
+func OnlyImage(paragraph *ast.Paragraph) (*ast.Image, bool) {
+	var image *ast.Image
+	for _, child := range paragraph.Children {
+		switch child := child.(type) {
+		case *ast.Image:
+			if image != nil {
+				return nil, false
+			}
+			image = child
+		case *ast.Text:
+			if strings.TrimSpace(string(child.Literal)) != "" {
+				return nil, false
+			}
+		default:
+			return nil, false
+		}
+	}
+	return image, image != nil
+}
+
+// UnderlineTag returns whether the inline HTML is a "<u>" or "<ins>" tag, shown
+// as an underline by most converters, and whether it is the opening tag
+func UnderlineTag(span *ast.HTMLSpan) (opening bool, ok bool) {
+	opening, ok = underlineTags[strings.ToLower(string(span.Literal))]
+	return opening, ok
+}
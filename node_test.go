@@ -0,0 +1,83 @@
+package markdownconverter
+
+import (
+	"testing"
+
+	"github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/gomarkdown/markdown/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PlainText(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "text", input: "Heading", expected: "Heading"},
+		{name: "formatting", input: "**bold** _italic_ `code`", expected: "bold italic code"},
+		{name: "link", input: "[the docs](https://example.com)", expected: "the docs"},
+		{name: "trimmed", input: "  text  ", expected: "text"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			document := markdown.Parse([]byte(test.input), parser.NewWithExtensions(parser.CommonExtensions))
+			assert.Equal(t, test.expected, PlainText(document))
+		})
+	}
+}
+
+func Test_OnlyImage(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "image", input: "![logo](logo.png)", expected: "logo.png"},
+		{name: "text", input: "text"},
+		{name: "image_and_text", input: "![logo](logo.png) text"},
+		{name: "two_images", input: "![logo](logo.png) ![icon](icon.png)"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			document := markdown.Parse([]byte(test.input), parser.NewWithExtensions(parser.CommonExtensions))
+			paragraph := document.GetChildren()[0].(*ast.Paragraph)
+			image, ok := OnlyImage(paragraph)
+			if test.expected == "" {
+				assert.False(t, ok)
+				assert.Nil(t, image)
+				return
+			}
+			assert.True(t, ok)
+			assert.Equal(t, test.expected, string(image.Destination))
+		})
+	}
+}
+
+func Test_UnderlineTag(t *testing.T) {
+
+	tests := []struct {
+		tag     string
+		opening bool
+		ok      bool
+	}{
+		{tag: "<u>", opening: true, ok: true},
+		{tag: "</u>", opening: false, ok: true},
+		{tag: "<INS>", opening: true, ok: true},
+		{tag: "</ins>", opening: false, ok: true},
+		{tag: "<b>", opening: false, ok: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tag, func(t *testing.T) {
+			opening, ok := UnderlineTag(&ast.HTMLSpan{Leaf: ast.Leaf{Literal: []byte(test.tag)}})
+			assert.Equal(t, test.opening, opening)
+			assert.Equal(t, test.ok, ok)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+// Package preview serves a live preview of a markdown file, showing the HTML
+// and Slack renderings side by side and refreshing them whenever it changes
+package preview
+
+import (
+	_ "embed" // required for the preview page
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/evilmonkeyinc/markdownconverter"
+)
+
+// DefaultInterval is the default time between checks for changes to the file
+const DefaultInterval time.Duration = 250 * time.Millisecond
+
+//go:embed preview.html
+var page []byte
+
+// New returns a new instance of Server for the markdown file
+func New(filename string, html, slack markdownconverter.Converter) *Server {
+	return &Server{
+		Filename: filename,
+		HTML:     html,
+		Slack:    slack,
+		Interval: DefaultInterval,
+	}
+}
+
+// Server is an http.Handler serving the preview page at / and the renderings
+// as server-sent events at /events
+type Server struct {
+	// Filename is the markdown file being previewed
+	Filename string
+	// HTML renders the HTML preview, it should output a complete document
+	HTML markdownconverter.Converter
+	// Slack renders the Slack preview
+	Slack markdownconverter.Converter
+	// Interval is the time between checks for changes to the file
+	Interval time.Duration
+}
+
+// update is the data of each event sent to the preview page
+type update struct {
+	Filename string `json:"filename"`
+	HTML     string `json:"html"`
+	Slack    string `json:"slack"`
+	Error    string `json:"error,omitempty"`
+}
+
+// fileState is used to detect changes to the file
+type fileState struct {
+	modified time.Time
+	size     int64
+	missing  bool
+}
+
+// ServeHTTP handles the request
+func (server *Server) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet && request.Method != http.MethodHead {
+		writer.Header().Set("Allow", "GET, HEAD")
+		http.Error(writer, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch request.URL.Path {
+	case "/":
+		writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+		writer.Write(page)
+	case "/events":
+		server.events(writer, request)
+	default:
+		http.NotFound(writer, request)
+	}
+}
+
+// events sends the renderings when the client connects and again every time
+// the file changes, until the client disconnects
+func (server *Server) events(writer http.ResponseWriter, request *http.Request) {
+	flusher, ok := writer.(http.Flusher)
+	if !ok {
+		http.Error(writer, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	writer.Header().Set("Content-Type", "text/event-stream")
+	writer.Header().Set("Cache-Control", "no-cache")
+	writer.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(server.Interval)
+	defer ticker.Stop()
+
+	var last *fileState
+	for {
+		state := server.state()
+		if last == nil || state != *last {
+			last = &state
+			data, err := json.Marshal(server.render())
+			if err != nil {
+				return
+			}
+			if _, err := fmt.Fprintf(writer, "event: update\ndata: %s\n\n", data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+
+		select {
+		case <-request.Context().Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (server *Server) state() fileState {
+	info, err := os.Stat(server.Filename)
+	if err != nil {
+		return fileState{missing: true}
+	}
+	return fileState{modified: info.ModTime(), size: info.Size()}
+}
+
+// render reads the file and returns its renderings
+func (server *Server) render() update {
+	result := update{Filename: server.Filename}
+
+	markdown, err := os.ReadFile(server.Filename)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	html, err := server.HTML.Parse(markdown)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	slack, err := server.Slack.Parse(markdown)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.HTML = string(html)
+	result.Slack = slackHTML(string(slack))
+	return result
+}
@@ -0,0 +1,147 @@
+package preview
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	nethttp "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evilmonkeyinc/markdownconverter/http"
+	"github.com/evilmonkeyinc/markdownconverter/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Server_ServeHTTP(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		status      int
+		contentType string
+	}{
+		{
+			name:        "page",
+			method:      nethttp.MethodGet,
+			path:        "/",
+			status:      nethttp.StatusOK,
+			contentType: "text/html; charset=utf-8",
+		},
+		{
+			name:   "not_found",
+			method: nethttp.MethodGet,
+			path:   "/other",
+			status: nethttp.StatusNotFound,
+		},
+		{
+			name:   "method",
+			method: nethttp.MethodPost,
+			path:   "/",
+			status: nethttp.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := New("README.md", http.New(), slack.New())
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, httptest.NewRequest(test.method, test.path, nil))
+
+			assert.Equal(t, test.status, recorder.Code)
+			if test.contentType != "" {
+				assert.Equal(t, test.contentType, recorder.Header().Get("Content-Type"))
+				assert.Equal(t, string(page), recorder.Body.String())
+			}
+		})
+	}
+}
+
+func Test_Server_Events(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "notes.md")
+	assert.Nil(t, os.WriteFile(filename, []byte("# First"), 0600))
+
+	server := New(filename, http.New(), slack.New())
+	server.Interval = 10 * time.Millisecond
+	testServer := httptest.NewServer(server)
+	defer testServer.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	request, _ := nethttp.NewRequestWithContext(ctx, nethttp.MethodGet, testServer.URL+"/events", nil)
+	response, err := nethttp.DefaultClient.Do(request)
+	assert.Nil(t, err)
+	defer response.Body.Close()
+	assert.Equal(t, "text/event-stream", response.Header.Get("Content-Type"))
+
+	scanner := bufio.NewScanner(response.Body)
+	next := func() update {
+		var event update
+		for scanner.Scan() {
+			if data := strings.TrimPrefix(scanner.Text(), "data: "); data != scanner.Text() {
+				assert.Nil(t, json.Unmarshal([]byte(data), &event))
+				return event
+			}
+		}
+		return event
+	}
+
+	first := next()
+	assert.Equal(t, filename, first.Filename)
+	assert.Equal(t, "<h1>First</h1>", first.HTML)
+	assert.Equal(t, "<b>First</b>", first.Slack)
+
+	assert.Nil(t, os.WriteFile(filename, []byte("# Second heading"), 0600))
+	second := next()
+	assert.Equal(t, "<h1>Second heading</h1>", second.HTML)
+
+	assert.Nil(t, os.Remove(filename))
+	missing := next()
+	assert.Contains(t, missing.Error, "notes.md")
+}
+
+func Test_slackHTML(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "formatting",
+			input:    "*bold* _italic_ ~strike~ snake_case_name 2*3*4",
+			expected: "<b>bold</b> <i>italic</i> <s>strike</s> snake_case_name 2*3*4",
+		},
+		{
+			name:     "code",
+			input:    "`*not bold* <b>` and *bold*",
+			expected: "<code>*not bold* &lt;b&gt;</code> and <b>bold</b>",
+		},
+		{
+			name:     "links",
+			input:    "<https://example.com/a_b_|the _site_> <mailto:a@example.com> <javascript:alert(1)|x>",
+			expected: "<a href=\"https://example.com/a_b_\" target=\"_blank\" rel=\"noopener\">the _site_</a> <a href=\"mailto:a@example.com\" target=\"_blank\" rel=\"noopener\">mailto:a@example.com</a> &lt;javascript:alert(1)|x&gt;",
+		},
+		{
+			name:     "lines",
+			input:    "one\ntwo\n> quoted\n> *lines*\nafter",
+			expected: "one<br>two<blockquote>quoted<br><b>lines</b></blockquote>after",
+		},
+		{
+			name:     "code_block",
+			input:    "before\n```\nfunc() *x*\n<tag>\n```\nafter",
+			expected: "before<pre>func() *x*\n&lt;tag&gt;\n</pre>after",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, slackHTML(test.input))
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package preview
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+const (
+	codeFence      string = "```"
+	quotePrefix    string = "&gt; "
+	inlineCodeMark string = "`"
+	// linkPlaceholder stands in for links while the formatting is rendered,
+	// so markers within URLs are left alone
+	linkPlaceholder string = "\x00"
+)
+
+var (
+	slackLink   = regexp.MustCompile(`&lt;((?:https?|mailto):[^|\s]+?)(?:\|(.*?))?&gt;`)
+	slackBold   = regexp.MustCompile(`(^|[^\p{L}\p{N}*])\*([^*\s](?:[^*]*[^*\s])?)\*`)
+	slackItalic = regexp.MustCompile(`(^|[^\p{L}\p{N}_])_([^_\s](?:[^_]*[^_\s])?)_`)
+	slackStrike = regexp.MustCompile(`(^|[^\p{L}\p{N}~])~([^~\s](?:[^~]*[^~\s])?)~`)
+)
+
+// slackHTML renders Slack mrkdwn as HTML, approximating how Slack displays
+// the message
+func slackHTML(mrkdwn string) string {
+	builder := &strings.Builder{}
+	lines := strings.Split(html.EscapeString(mrkdwn), "\n")
+
+	// afterBlock is true when the last line closed a block, so there is no
+	// need for a line break
+	inCode, inQuote, afterBlock := false, false, true
+	for _, line := range lines {
+		if inCode {
+			if strings.HasSuffix(line, codeFence) {
+				builder.WriteString(strings.TrimSuffix(line, codeFence) + "</pre>")
+				inCode, afterBlock = false, true
+			} else {
+				builder.WriteString(line + "\n")
+			}
+			continue
+		}
+		if strings.HasPrefix(line, codeFence) {
+			if inQuote {
+				builder.WriteString("</blockquote>")
+				inQuote = false
+			}
+			line = strings.TrimPrefix(line, codeFence)
+			if strings.HasSuffix(line, codeFence) && line != "" {
+				builder.WriteString("<pre>" + strings.TrimSuffix(line, codeFence) + "</pre>")
+				afterBlock = true
+				continue
+			}
+			builder.WriteString("<pre>")
+			if line != "" {
+				builder.WriteString(line + "\n")
+			}
+			inCode = true
+			continue
+		}
+
+		quoted := strings.HasPrefix(line, quotePrefix) || line == "&gt;"
+		if quoted && !inQuote {
+			builder.WriteString("<blockquote>")
+		} else if !quoted && inQuote {
+			builder.WriteString("</blockquote>")
+		} else if !afterBlock {
+			builder.WriteString("<br>")
+		}
+		inQuote, afterBlock = quoted, false
+		if quoted {
+			line = strings.TrimPrefix(strings.TrimPrefix(line, "&gt;"), " ")
+		}
+		builder.WriteString(slackInline(line))
+	}
+	if inCode {
+		builder.WriteString("</pre>")
+	}
+	if inQuote {
+		builder.WriteString("</blockquote>")
+	}
+	return builder.String()
+}
+
+// slackInline renders the inline formatting of the escaped line, the content
+// of code spans is left as it is
+func slackInline(line string) string {
+	parts := strings.Split(line, inlineCodeMark)
+	for index, part := range parts {
+		if index%2 == 1 && index < len(parts)-1 {
+			parts[index] = "<code>" + part + "</code>"
+			continue
+		}
+		links := make([]string, 0)
+		part = slackLink.ReplaceAllStringFunc(part, func(match string) string {
+			groups := slackLink.FindStringSubmatch(match)
+			text := groups[2]
+			if text == "" {
+				text = groups[1]
+			}
+			links = append(links, `<a href="`+groups[1]+`" target="_blank" rel="noopener">`+text+"</a>")
+			return linkPlaceholder
+		})
+		part = slackBold.ReplaceAllString(part, "$1<b>$2</b>")
+		part = slackItalic.ReplaceAllString(part, "$1<i>$2</i>")
+		part = slackStrike.ReplaceAllString(part, "$1<s>$2</s>")
+		for _, link := range links {
+			part = strings.Replace(part, linkPlaceholder, link, 1)
+		}
+		if index%2 == 1 {
+			part = inlineCodeMark + part
+		}
+		parts[index] = part
+	}
+	return strings.Join(parts, "")
+}
@@ -0,0 +1,622 @@
+package rst
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/evilmonkeyinc/markdownconverter"
+	"github.com/gomarkdown/markdown/ast"
+)
+
+// indentWidth is the indentation of directive and block quote content
+const indentWidth int = 3
+
+// adornment is the character and style of a section title
+type adornment struct {
+	character string
+	overline  bool
+}
+
+// adornments are the section title styles of each section depth, the first
+// is only used by the document title
+var adornments []adornment = []adornment{
+	{character: "=", overline: true},
+	{character: "="},
+	{character: "-"},
+	{character: "~"},
+	{character: "^"},
+	{character: `"`},
+	{character: "'"},
+}
+
+// lineStarts match the text at the start of a line that reStructuredText
+// would read as the start of a list, directive, or other body element, with
+// the character to escape as the first group
+var lineStarts []*regexp.Regexp = []*regexp.Regexp{
+	// bullet lists, block quote attributions, and option lists
+	regexp.MustCompile(`^([-+•‣⁃—―])(?:[ -]|$|[A-Za-z0-9])`),
+	// enumerated lists
+	regexp.MustCompile(`^(?:\d+|[A-Za-z]|[ivxlcdmIVXLCDM]+|#)([.)])(?: |$)`),
+	regexp.MustCompile(`^(\()(?:\d+|[A-Za-z]|[ivxlcdmIVXLCDM]+|#)\)(?: |$)`),
+	// explicit markup, field lists, and doctest blocks
+	regexp.MustCompile(`^(\.)\.(?: |$)`),
+	regexp.MustCompile(`^(:)[^:\s][^:]*:(?: |$)`),
+	regexp.MustCompile(`^(>)>>`),
+	// lines that could be section title adornments or transitions
+	regexp.MustCompile(`^([!-/:-@\[-` + "`" + `{-~])(?:[!-/:-@\[-` + "`" + `{-~])+$`),
+}
+
+// before and after are the ASCII characters, other than whitespace, that
+// inline markup can follow and be followed by
+const (
+	before string = `-/:'"<([{`
+	after  string = `-/:'".,;!?\)>]}`
+)
+
+type renderer struct {
+	// listTables will use the list-table directive rather than grid tables
+	listTables bool
+	// titled is true when the document has a title, so headings start at the
+	// second section style
+	titled bool
+	// targets are the hyperlink target names of headings
+	targets map[ast.Node]string
+	// levels are the heading levels of the current section and its parents
+	levels []int
+	// nested is the number of body elements the current node is in, where
+	// section titles and transitions are not allowed
+	nested int
+	// lineBlock is true while rendering a paragraph with hard line breaks
+	lineBlock bool
+	// substitutions are the definitions of the inline images
+	substitutions []string
+}
+
+// blocks renders each block node separated by blank lines
+func (renderer *renderer) blocks(nodes []ast.Node) string {
+	parts := make([]string, 0, len(nodes))
+	var previous ast.Node
+	for _, node := range nodes {
+		text := renderer.block(node)
+		if text == "" {
+			continue
+		}
+		if _, quote := node.(*ast.BlockQuote); quote && previous != nil {
+			switch previous.(type) {
+			case *ast.Paragraph, *ast.Heading:
+			default:
+				// an empty comment ends the previous element, so the
+				// indented quote is not read as part of it
+				text = "..\n\n" + text
+			}
+		}
+		parts = append(parts, text)
+		previous = node
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// nestedBlocks renders the block nodes inside a body element
+func (renderer *renderer) nestedBlocks(nodes []ast.Node) string {
+	renderer.nested++
+	defer func() { renderer.nested-- }()
+	return renderer.blocks(nodes)
+}
+
+func (renderer *renderer) block(node ast.Node) string {
+	switch node := node.(type) {
+	case *ast.Heading:
+		if renderer.nested > 0 {
+			return "**" + escape(markdownconverter.PlainText(node)) + "**"
+		}
+		return renderer.heading(node)
+	case *ast.Paragraph:
+		if image, ok := markdownconverter.OnlyImage(node); ok {
+			return directive("image", string(image.Destination), imageOptions(image), "")
+		}
+		return renderer.paragraph(node)
+	case *ast.BlockQuote:
+		return indent(renderer.nestedBlocks(node.Children), strings.Repeat(" ", indentWidth))
+	case *markdownconverter.Admonition:
+		content := renderer.nestedBlocks(node.Children)
+		if content == "" {
+			// directives need content, an empty comment shows nothing
+			content = ".."
+		}
+		return directive(node.Type, "", nil, content)
+	case *ast.CodeBlock:
+		code := strings.TrimRight(string(node.Literal), "\n")
+		if strings.TrimSpace(code) == "" {
+			return ""
+		}
+		if diagramType := markdownconverter.DiagramType(node.Info); diagramType != "" {
+			return "*" + markdownconverter.DiagramLabel(diagramType) + " diagram*\n\n" + directive("code-block", "text", nil, code)
+		}
+		language := ""
+		if fields := strings.Fields(string(node.Info)); len(fields) > 0 {
+			language = fields[0]
+		}
+		return directive("code-block", language, nil, code)
+	case *ast.MathBlock:
+		math := strings.TrimSpace(string(node.Literal))
+		if math == "" {
+			return ""
+		}
+		return directive("math", "", nil, math)
+	case *ast.List:
+		return renderer.list(node)
+	case *ast.HorizontalRule:
+		if renderer.nested > 0 {
+			return ""
+		}
+		return "----"
+	case *ast.Table:
+		if renderer.listTables {
+			return renderer.listTable(node)
+		}
+		return renderer.gridTable(node)
+	case *ast.HTMLBlock:
+		html := strings.TrimRight(string(node.Literal), "\n")
+		if strings.TrimSpace(html) == "" {
+			return ""
+		}
+		return directive("raw", "html", nil, html)
+	default:
+		if container := node.AsContainer(); container != nil {
+			return renderer.blocks(container.Children)
+		}
+		return escapeLines(escape(strings.TrimSpace(string(node.AsLeaf().Literal))))
+	}
+}
+
+// heading renders the section title with the style of its depth, which is
+// the number of open sections it is in so the styles are always consistent
+func (renderer *renderer) heading(node *ast.Heading) string {
+	for len(renderer.levels) > 0 && renderer.levels[len(renderer.levels)-1] >= node.Level {
+		renderer.levels = renderer.levels[:len(renderer.levels)-1]
+	}
+	renderer.levels = append(renderer.levels, node.Level)
+
+	depth := len(renderer.levels)
+	if renderer.titled {
+		depth++
+	}
+	title := section(renderer.inline(node), depth)
+	if target, ok := renderer.targets[node]; ok {
+		return ".. _" + targetName(target) + ":\n\n" + title
+	}
+	return title
+}
+
+// section returns the title with the adornment of the depth, at least as
+// wide as the title is displayed
+func section(title string, depth int) string {
+	if depth >= len(adornments) {
+		depth = len(adornments) - 1
+	}
+	style := adornments[depth]
+	width := markdownconverter.DisplayWidth(title)
+	if width < 4 {
+		width = 4
+	}
+	line := strings.Repeat(style.character, width)
+	if style.overline {
+		return line + "\n" + title + "\n" + line
+	}
+	return title + "\n" + line
+}
+
+// paragraph renders the paragraph, as a line block if it has hard line breaks
+func (renderer *renderer) paragraph(node *ast.Paragraph) string {
+	for _, child := range node.Children {
+		if _, ok := child.(*ast.Hardbreak); ok {
+			renderer.lineBlock = true
+			text := renderer.inline(node)
+			renderer.lineBlock = false
+			return strings.TrimRight("| "+strings.ReplaceAll(text, "\n", "\n| "), " ")
+		}
+	}
+
+	text := escapeLines(renderer.inline(node))
+	if strings.HasSuffix(text, "::") {
+		// a paragraph ending in "::" would start a literal block
+		text = text[:len(text)-1] + `\:`
+	}
+	return text
+}
+
+// list renders the list items with their content indented to line up with
+// the text after the marker, separated by blank lines if any item has more
+// than one block
+func (renderer *renderer) list(node *ast.List) string {
+	if node.ListFlags&ast.ListTypeDefinition != 0 {
+		return renderer.definitionList(node)
+	}
+
+	start := node.Start
+	if start == 0 {
+		start = 1
+	}
+
+	separator := "\n"
+	items := make([]string, 0, len(node.Children))
+	for index, item := range node.Children {
+		marker := "-"
+		if node.ListFlags&ast.ListTypeOrdered != 0 {
+			marker = fmt.Sprintf("%d.", index+start)
+		}
+		if len(item.GetChildren()) > 1 {
+			separator = "\n\n"
+		}
+		content := renderer.nestedBlocks(item.GetChildren())
+		if content == "" {
+			items = append(items, marker)
+			continue
+		}
+		items = append(items, marker+" "+indent(content, strings.Repeat(" ", len(marker)+1))[len(marker)+1:])
+	}
+	return strings.Join(items, separator)
+}
+
+// definitionList renders the terms with their definitions indented below
+func (renderer *renderer) definitionList(node *ast.List) string {
+	lines := make([]string, 0, len(node.Children))
+	for _, child := range node.Children {
+		item, ok := child.(*ast.ListItem)
+		if !ok {
+			continue
+		}
+		if item.ListFlags&ast.ListTypeTerm != 0 {
+			if len(lines) > 0 {
+				lines = append(lines, "")
+			}
+			lines = append(lines, escapeLines(renderer.inline(item)))
+			continue
+		}
+		if content := renderer.nestedBlocks(item.Children); content != "" {
+			lines = append(lines, indent(content, strings.Repeat(" ", indentWidth)))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// rows returns the rendered cells of each row of the table and the number of
+// header rows
+func (renderer *renderer) rows(node *ast.Table) ([][]string, int) {
+	rows := make([][]string, 0)
+	headers := 0
+	ast.WalkFunc(node, func(child ast.Node, entering bool) ast.WalkStatus {
+		row, ok := child.(*ast.TableRow)
+		if !ok || !entering {
+			return ast.GoToNext
+		}
+		cells := make([]string, 0, len(row.Children))
+		for _, cell := range row.Children {
+			cells = append(cells, escapeLines(renderer.inline(cell)))
+		}
+		if _, header := row.Parent.(*ast.TableHeader); header {
+			headers++
+		}
+		rows = append(rows, cells)
+		return ast.SkipChildren
+	})
+	return rows, headers
+}
+
+// gridTable renders the table as a grid table with columns as wide as their
+// widest cell
+func (renderer *renderer) gridTable(node *ast.Table) string {
+	rows, headers := renderer.rows(node)
+	widths := make([]int, 0)
+	for _, row := range rows {
+		for index, cell := range row {
+			if index >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if width := markdownconverter.DisplayWidth(cell); width > widths[index] {
+				widths[index] = width
+			}
+		}
+	}
+	if len(widths) == 0 {
+		return ""
+	}
+
+	border := func(character string) string {
+		builder := &strings.Builder{}
+		for _, width := range widths {
+			builder.WriteString("+" + strings.Repeat(character, width+2))
+		}
+		return builder.String() + "+"
+	}
+
+	lines := []string{border("-")}
+	for index, row := range rows {
+		builder := &strings.Builder{}
+		for column, width := range widths {
+			cell := ""
+			if column < len(row) {
+				cell = row[column]
+			}
+			builder.WriteString("| " + cell + strings.Repeat(" ", width-markdownconverter.DisplayWidth(cell)+1))
+		}
+		lines = append(lines, builder.String()+"|")
+		if index == headers-1 {
+			lines = append(lines, border("="))
+		} else {
+			lines = append(lines, border("-"))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// listTable renders the table with the list-table directive
+func (renderer *renderer) listTable(node *ast.Table) string {
+	rows, headers := renderer.rows(node)
+	if len(rows) == 0 {
+		return ""
+	}
+
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		for index, cell := range row {
+			marker := "  -"
+			if index == 0 {
+				marker = "* -"
+			}
+			if cell == "" {
+				lines = append(lines, marker)
+				continue
+			}
+			lines = append(lines, marker+" "+cell)
+		}
+	}
+	var options []string
+	if headers > 0 {
+		options = []string{fmt.Sprintf(":header-rows: %d", headers)}
+	}
+	return directive("list-table", "", options, strings.Join(lines, "\n"))
+}
+
+// chunk is a part of the inline content, markup is true if it is inline
+// markup that must be separated from the characters around it
+type chunk struct {
+	text   string
+	markup bool
+}
+
+// inline renders the inline children of the node, separating inline markup
+// from surrounding characters that would stop it being recognised with
+// escaped spaces
+func (renderer *renderer) inline(node ast.Node) string {
+	chunks := make([]chunk, 0, len(node.GetChildren()))
+	for _, child := range node.GetChildren() {
+		if chunk := renderer.inlineNode(child); chunk.text != "" {
+			chunks = append(chunks, chunk)
+		}
+	}
+
+	builder := &strings.Builder{}
+	for index, current := range chunks {
+		if index > 0 {
+			previous := chunks[index-1]
+			last, _ := utf8.DecodeLastRuneInString(previous.text)
+			first, _ := utf8.DecodeRuneInString(current.text)
+			if (current.markup && !allowedBefore(last)) || (previous.markup && !allowedAfter(first)) {
+				builder.WriteString(`\ `)
+			}
+		}
+		builder.WriteString(current.text)
+	}
+	return strings.TrimSpace(builder.String())
+}
+
+func (renderer *renderer) inlineNode(node ast.Node) chunk {
+	switch node := node.(type) {
+	case *ast.Text:
+		text := string(node.Literal)
+		if renderer.lineBlock {
+			text = strings.ReplaceAll(text, "\n", " ")
+		}
+		return chunk{text: escape(text)}
+	case *ast.Code:
+		return chunk{text: literal(string(node.Literal)), markup: true}
+	case *ast.Math:
+		return role("math", strings.TrimSpace(string(node.Literal)))
+	case *ast.Emph:
+		return wrapped("*", escape(markdownconverter.PlainText(node)))
+	case *ast.Strong:
+		return wrapped("**", escape(markdownconverter.PlainText(node)))
+	case *ast.Subscript:
+		return role("sub", escape(string(node.Literal)))
+	case *ast.Superscript:
+		return role("sup", escape(string(node.Literal)))
+	case *ast.Hardbreak:
+		if renderer.lineBlock {
+			return chunk{text: "\n"}
+		}
+		return chunk{text: " "}
+	case *ast.Link:
+		return chunk{text: renderer.link(string(node.Destination), markdownconverter.PlainText(node)), markup: true}
+	case *ast.Image:
+		name := fmt.Sprintf("image%d", len(renderer.substitutions)+1)
+		renderer.substitutions = append(renderer.substitutions, directive("|"+name+"| image", string(node.Destination), imageOptions(node), ""))
+		return chunk{text: "|" + name + "|", markup: true}
+	case *ast.HTMLSpan:
+		// inline HTML, including underline tags, has no equivalent
+		return chunk{}
+	default:
+		if node.AsContainer() != nil {
+			return chunk{text: renderer.inline(node)}
+		}
+		return chunk{text: escape(string(node.AsLeaf().Literal))}
+	}
+}
+
+// link renders an anonymous hyperlink reference, using the target of the
+// heading for links to a heading in the document
+func (renderer *renderer) link(destination, text string) string {
+	reference := ""
+	if strings.HasPrefix(destination, "#") {
+		for _, target := range renderer.targets {
+			if target == destination[1:] {
+				reference = targetName(target) + "_"
+				break
+			}
+		}
+	}
+	if reference == "" {
+		reference = uri(destination)
+	}
+	if text == "" || text == destination {
+		return "`<" + reference + ">`__"
+	}
+	return "`" + strings.ReplaceAll(escape(text), "<", `\<`) + " <" + reference + ">`__"
+}
+
+// uri escapes the characters of the destination that would end an embedded
+// URI or make it an alias
+func uri(destination string) string {
+	if parsed, err := url.Parse(destination); err == nil {
+		destination = parsed.String()
+	}
+	destination = strings.NewReplacer(`\`, `\\`, "`", "\\`", "<", `\<`, ">", `\>`, " ", "%20").Replace(destination)
+	if strings.HasSuffix(destination, "_") {
+		destination = destination[:len(destination)-1] + `\_`
+	}
+	return destination
+}
+
+// targetName returns the hyperlink target name, quoted if it contains a colon
+func targetName(name string) string {
+	if strings.Contains(name, ":") {
+		return "`" + name + "`"
+	}
+	return name
+}
+
+// imageOptions returns the alt option of the image directive
+func imageOptions(image *ast.Image) []string {
+	if alt := markdownconverter.PlainText(image); alt != "" {
+		return []string{":alt: " + alt}
+	}
+	return nil
+}
+
+// literal renders the code as an inline literal, or with the literal role if
+// it contains characters an inline literal cannot
+func literal(code string) string {
+	code = strings.TrimSpace(strings.ReplaceAll(code, "\n", " "))
+	if code == "" {
+		return ""
+	}
+	if strings.Contains(code, "`") {
+		return ":literal:`" + strings.NewReplacer(`\`, `\\`, "`", "\\`").Replace(code) + "`"
+	}
+	return "``" + code + "``"
+}
+
+// role renders the content as interpreted text with the role
+func role(name, content string) chunk {
+	if content == "" {
+		return chunk{}
+	}
+	return chunk{text: ":" + name + ":`" + content + "`", markup: true}
+}
+
+// wrapped returns the content between the markers
+func wrapped(marker, content string) chunk {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return chunk{}
+	}
+	return chunk{text: marker + content + marker, markup: true}
+}
+
+// allowedBefore returns true if inline markup can start after the rune
+func allowedBefore(r rune) bool {
+	if r < utf8.RuneSelf {
+		return unicode.IsSpace(r) || strings.ContainsRune(before, r)
+	}
+	return unicode.IsSpace(r) || unicode.In(r, unicode.Pd, unicode.Po, unicode.Ps, unicode.Pi, unicode.Pf)
+}
+
+// allowedAfter returns true if inline markup can end before the rune
+func allowedAfter(r rune) bool {
+	if r < utf8.RuneSelf {
+		return unicode.IsSpace(r) || strings.ContainsRune(after, r)
+	}
+	return unicode.IsSpace(r) || unicode.In(r, unicode.Pd, unicode.Po, unicode.Pe, unicode.Pi, unicode.Pf)
+}
+
+// escape adds a backslash before the characters that could start inline
+// markup, and before underscores that could end a reference
+func escape(text string) string {
+	builder := &strings.Builder{}
+	for index, r := range text {
+		switch r {
+		case '\\', '*', '`', '|':
+			builder.WriteRune('\\')
+		case '_':
+			next, _ := utf8.DecodeRuneInString(text[index+1:])
+			if !unicode.IsLetter(next) && !unicode.IsDigit(next) {
+				builder.WriteRune('\\')
+			}
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// escapeLines adds a backslash before the characters at the start of each
+// line that would start a body element. Lines starting with an escape or
+// inline markup, as any of its characters in the text are escaped, are left
+// as they are.
+func escapeLines(text string) string {
+	lines := strings.Split(text, "\n")
+	for index, line := range lines {
+		if line != "" && strings.ContainsRune("\\*`|", rune(line[0])) {
+			continue
+		}
+		for _, lineStart := range lineStarts {
+			if match := lineStart.FindStringSubmatchIndex(line); match != nil {
+				line = line[:match[2]] + `\` + line[match[2]:]
+				break
+			}
+		}
+		lines[index] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// directive renders the directive with its argument, options, and content
+func directive(name, argument string, options []string, content string) string {
+	builder := &strings.Builder{}
+	builder.WriteString(".. " + name + "::")
+	if argument != "" {
+		builder.WriteString(" " + argument)
+	}
+	padding := strings.Repeat(" ", indentWidth)
+	for _, option := range options {
+		builder.WriteString("\n" + padding + option)
+	}
+	if content != "" {
+		builder.WriteString("\n\n" + indent(content, padding))
+	}
+	return builder.String()
+}
+
+// indent adds the prefix to each line of the text that is not empty
+func indent(text, prefix string) string {
+	lines := strings.Split(text, "\n")
+	for index, line := range lines {
+		if line != "" {
+			lines[index] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,128 @@
+// Package rst converts markdown to reStructuredText
+package rst
+
+import (
+	"strings"
+
+	"github.com/evilmonkeyinc/markdownconverter"
+	"github.com/evilmonkeyinc/markdownconverter/links"
+	"github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/gomarkdown/markdown/parser"
+)
+
+const (
+	// TableGrid renders tables as grid tables
+	TableGrid string = "grid"
+	// TableList renders tables with the list-table directive
+	TableList string = "list"
+)
+
+// New returns a new instance of Converter
+func New() *Converter {
+	return &Converter{
+		Tables: TableGrid,
+	}
+}
+
+// Converter is the reStructuredText Converter implementation
+type Converter struct {
+	// Links is used to rewrite the destinations of links and images, leave nil
+	// to keep them as they are
+	Links *links.Rewriter
+	// Tables is how tables are written, either TableGrid or TableList
+	Tables string
+}
+
+// Format returns a unique name for the converter
+func (converter *Converter) Format() string {
+	return "rst"
+}
+
+// Parse will parse the standard markdown and return the converted data
+func (converter *Converter) Parse(markdwn []byte) ([]byte, error) {
+	frontMatter, body := markdownconverter.FrontMatter(markdwn)
+	document := markdown.Parse(markdown.NormalizeNewlines(body), parser.NewWithExtensions(parser.CommonExtensions|parser.OrderedListStart))
+	markdownconverter.Admonitions(document)
+	if converter.Links != nil {
+		if err := converter.Links.Rewrite(document); err != nil {
+			return nil, err
+		}
+	}
+
+	renderer := &renderer{
+		listTables: converter.Tables == TableList,
+		targets:    headingTargets(document),
+		levels:     make([]int, 0),
+	}
+	parts := make([]string, 0)
+	if title := frontMatter["title"]; title != "" {
+		parts = append(parts, section(escape(title), 0))
+		renderer.titled = true
+	}
+	if content := renderer.blocks(transitions(document.GetChildren())); content != "" {
+		parts = append(parts, content)
+	}
+	parts = append(parts, renderer.substitutions...)
+	// only trailing space is trimmed, leading space starts a block quote
+	return []byte(strings.TrimRight(strings.Join(parts, "\n\n"), " \n")), nil
+}
+
+// transitions removes the horizontal rules that cannot be transitions, those
+// at the start or end of the document, directly after a heading, or directly
+// after another transition
+func transitions(nodes []ast.Node) []ast.Node {
+	kept := make([]ast.Node, 0, len(nodes))
+	for index, node := range nodes {
+		if _, ok := node.(*ast.HorizontalRule); ok {
+			if len(kept) == 0 || index == len(nodes)-1 {
+				continue
+			}
+			switch kept[len(kept)-1].(type) {
+			case *ast.HorizontalRule, *ast.Heading:
+				continue
+			}
+		}
+		kept = append(kept, node)
+	}
+	for len(kept) > 0 {
+		if _, ok := kept[len(kept)-1].(*ast.HorizontalRule); !ok {
+			break
+		}
+		kept = kept[:len(kept)-1]
+	}
+	return kept
+}
+
+// headingTargets returns the hyperlink target names of the top level headings
+// that have an explicit ID or are linked to from the document, using the
+// GitHub compatible ID generated from their text for the others
+func headingTargets(document ast.Node) map[ast.Node]string {
+	fragments := make(map[string]bool)
+	ast.WalkFunc(document, func(node ast.Node, entering bool) ast.WalkStatus {
+		if link, ok := node.(*ast.Link); ok && entering && strings.HasPrefix(string(link.Destination), "#") {
+			fragments[string(link.Destination[1:])] = true
+		}
+		return ast.GoToNext
+	})
+
+	targets := make(map[ast.Node]string)
+	slugger := markdownconverter.NewSlugger()
+	for _, node := range document.GetChildren() {
+		if heading, ok := node.(*ast.Heading); ok && heading.HeadingID != "" {
+			slugger.Reserve(heading.HeadingID)
+			targets[heading] = heading.HeadingID
+		}
+	}
+	for _, node := range document.GetChildren() {
+		heading, ok := node.(*ast.Heading)
+		if !ok || heading.HeadingID != "" {
+			continue
+		}
+		id := slugger.Slug(markdownconverter.PlainText(heading))
+		if fragments[id] {
+			targets[heading] = id
+		}
+	}
+	return targets
+}
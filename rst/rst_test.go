@@ -0,0 +1,241 @@
+package rst
+
+import (
+	"testing"
+
+	"github.com/evilmonkeyinc/markdownconverter/links"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Converter_Format(t *testing.T) {
+	actual := New().Format()
+	assert.Equal(t, "rst", actual)
+}
+
+func Test_Converter_Parse(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "sections",
+			input:    "# Title\n\n## Section\n\n### Subsection\n\n## Other",
+			expected: "Title\n=====\n\nSection\n-------\n\nSubsection\n~~~~~~~~~~\n\nOther\n-----",
+		},
+		{
+			name:     "skipped_levels",
+			input:    "# Title\n\n#### Deep\n\n## Section",
+			expected: "Title\n=====\n\nDeep\n----\n\nSection\n-------",
+		},
+		{
+			name:     "front_matter_title",
+			input:    "---\ntitle: Notes\n---\n# First",
+			expected: "=====\nNotes\n=====\n\nFirst\n-----",
+		},
+		{
+			name:     "wide_title",
+			input:    "# 日本語のタイトル",
+			expected: "日本語のタイトル\n================",
+		},
+		{
+			name:     "short_title",
+			input:    "# Go",
+			expected: "Go\n====",
+		},
+		{
+			name:     "formatting",
+			input:    "**bold** _italic_ ~~strikethrough~~ <u>underline</u> `code` $x^2$",
+			expected: "**bold** *italic* strikethrough underline ``code`` :math:`x^2`",
+		},
+		{
+			name:     "nested_formatting",
+			input:    "**bold _and italic_**",
+			expected: "**bold and italic**",
+		},
+		{
+			name:     "intra_word",
+			input:    "un**believ**able and **bold**`code`",
+			expected: "un\\ **believ**\\ able and **bold**\\ ``code``",
+		},
+		{
+			name:     "punctuation_around_markup",
+			input:    "(**bold**), *italic*.",
+			expected: "(**bold**), *italic*.",
+		},
+		{
+			name:     "code_with_backticks",
+			input:    "`` a`b ``",
+			expected: ":literal:`a\\`b`",
+		},
+		{
+			name:     "escaping",
+			input:    "2\\*3 and a\\|b\\| or name\\_ but not snake_case or C:\\\\",
+			expected: "2\\*3 and a\\|b\\| or name\\_ but not snake_case or C:\\\\",
+		},
+		{
+			name:     "line_starts",
+			input:    "text\n\n\\- not a list\n\n1\\. not a list\n\n.. not a comment\n\n:not: a field",
+			expected: "text\n\n\\- not a list\n\n1\\. not a list\n\n\\.. not a comment\n\n\\:not: a field",
+		},
+		{
+			name:     "punctuation_lines",
+			input:    "`::`\n\n`-->`\n\n\\*\\*\n\n-->",
+			expected: "``::``\n\n``-->``\n\n\\*\\*\n\n\\-->",
+		},
+		{
+			name:     "punctuation_table_cell",
+			input:    "| a |\n|---|\n| \\*\\* |",
+			expected: "+------+\n| a    |\n+======+\n| \\*\\* |\n+------+",
+		},
+		{
+			name:     "literal_block_marker",
+			input:    "Example::",
+			expected: "Example:\\:",
+		},
+		{
+			name:     "hard_line_breaks",
+			input:    "first\\\nsecond\nthird",
+			expected: "| first\n| second third",
+		},
+		{
+			name:     "links",
+			input:    "[evilmonkeyinc](https://github.com/evilmonkeyinc) and <https://github.com>",
+			expected: "`evilmonkeyinc <https://github.com/evilmonkeyinc>`__ and `<https://github.com>`__",
+		},
+		{
+			name:     "link_escaping",
+			input:    "[a <b>](https://example.com/a_)",
+			expected: "`a \\<b> <https://example.com/a\\_>`__",
+		},
+		{
+			name:     "heading_targets",
+			input:    "# Install\n\nSee [usage](#usage) and [install](#install).\n\n## Usage\n\n## Other {#custom}",
+			expected: ".. _install:\n\nInstall\n=======\n\nSee `usage <usage_>`__ and `install <install_>`__.\n\n.. _usage:\n\nUsage\n-----\n\n.. _custom:\n\nOther\n-----",
+		},
+		{
+			name:     "unknown_fragment",
+			input:    "[missing](#missing)",
+			expected: "`missing <#missing>`__",
+		},
+		{
+			name:     "images",
+			input:    "![logo](logo.png)\n\nan ![icon](icon.png) inline",
+			expected: ".. image:: logo.png\n   :alt: logo\n\nan |image1| inline\n\n.. |image1| image:: icon.png\n   :alt: icon",
+		},
+		{
+			name:     "code_blocks",
+			input:    "```go\nfunc main() {\n\n}\n```\n\n```\nplain\n```",
+			expected: ".. code-block:: go\n\n   func main() {\n\n   }\n\n.. code-block::\n\n   plain",
+		},
+		{
+			name:     "diagram",
+			input:    "```mermaid\ngraph TD\n```",
+			expected: "*Mermaid diagram*\n\n.. code-block:: text\n\n   graph TD",
+		},
+		{
+			name:     "math_block",
+			input:    "$$\n\\frac{1}{2}\n$$",
+			expected: ".. math::\n\n   \\frac{1}{2}",
+		},
+		{
+			name:     "admonition",
+			input:    "> [!TIP]\n> Use *this*",
+			expected: ".. tip::\n\n   Use *this*",
+		},
+		{
+			name:     "empty_admonition",
+			input:    "> [!WARNING]",
+			expected: ".. warning::\n\n   ..",
+		},
+		{
+			name:     "block_quote",
+			input:    "text\n\n> quoted\n> > nested",
+			expected: "text\n\n   quoted\n\n      nested",
+		},
+		{
+			name:     "block_quote_after_list",
+			input:    "- item\n\n> quoted",
+			expected: "- item\n\n..\n\n   quoted",
+		},
+		{
+			name:     "heading_in_block_quote",
+			input:    "> # Heading\n> text",
+			expected: "   **Heading**\n\n   text",
+		},
+		{
+			name:     "lists",
+			input:    "- one\n- two\n\n1. first\n2. second",
+			expected: "- one\n- two\n\n1. first\n2. second",
+		},
+		{
+			name:     "nested_lists",
+			input:    "- one\n  - nested\n- two",
+			expected: "- one\n\n  - nested\n\n- two",
+		},
+		{
+			name:     "list_continuation",
+			input:    "1. first\n\n    more\n\n2. second",
+			expected: "1. first\n\n   more\n\n2. second",
+		},
+		{
+			name:     "ordered_list_start",
+			input:    "3. third\n4. fourth",
+			expected: "3. third\n4. fourth",
+		},
+		{
+			name:     "definition_list",
+			input:    "Term\n: definition\n\nOther\n: more",
+			expected: "Term\n   definition\n\nOther\n   more",
+		},
+		{
+			name:     "transitions",
+			input:    "---\n\nabove\n\n---\n\n***\n\nbelow\n\n# Heading\n\n---\n\ntext\n\n---",
+			expected: "above\n\n----\n\nbelow\n\nHeading\n=======\n\ntext",
+		},
+		{
+			name:     "grid_table",
+			input:    "| Name | Value |\n| --- | --- |\n| short | 中文 |\n| a\\*b | |",
+			expected: "+-------+-------+\n| Name  | Value |\n+=======+=======+\n| short | 中文  |\n+-------+-------+\n| a\\*b  |       |\n+-------+-------+",
+		},
+		{
+			name:     "html_block",
+			input:    "text\n\n<div>\nhtml\n</div>\n",
+			expected: "text\n\n.. raw:: html\n\n   <div>\n   html\n   </div>",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := New().Parse([]byte(test.input))
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, string(actual))
+		})
+	}
+}
+
+func Test_Converter_Parse_ListTables(t *testing.T) {
+	converter := New()
+	converter.Tables = TableList
+
+	actual, err := converter.Parse([]byte("| Name | Value |\n| --- | --- |\n| a | 1 |\n| b | |"))
+	assert.Nil(t, err)
+	assert.Equal(t, ".. list-table::\n   :header-rows: 1\n\n   * - Name\n     - Value\n   * - a\n     - 1\n   * - b\n     -", string(actual))
+}
+
+func Test_Converter_Parse_Links(t *testing.T) {
+	converter := New()
+	converter.Links = &links.Rewriter{
+		BaseURL:        "https://example.com/docs/",
+		MarkdownToHTML: true,
+	}
+
+	actual, err := converter.Parse([]byte("[setup](./setup.md)"))
+	assert.Nil(t, err)
+	assert.Equal(t, "`setup <https://example.com/docs/setup.html>`__", string(actual))
+
+	converter.Links.BaseURL = "invalid"
+	_, err = converter.Parse([]byte("[setup](./setup.md)"))
+	assert.ErrorIs(t, err, links.ErrInvalidBaseURL)
+}
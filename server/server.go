@@ -0,0 +1,254 @@
+// Package server exposes the markdown converters over HTTP, so they can be
+// used from services not written in Go
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/evilmonkeyinc/markdownconverter"
+)
+
+const (
+	// DefaultMaxBodySize is the default size in bytes above which request
+	// bodies are rejected
+	DefaultMaxBodySize int64 = 1 << 20
+
+	convertPath string = "/convert/"
+	formatsPath string = "/formats"
+	jsonType    string = "application/json"
+	textType    string = "text/plain; charset=utf-8"
+)
+
+// DefaultMediaTypes are the media types of the output of the built in formats,
+// formats without a media type are served as plain text
+var DefaultMediaTypes map[string]string = map[string]string{
+	"adf":        "application/json",
+	"asciidoc":   "text/asciidoc; charset=utf-8",
+	"rst":        "text/x-rst; charset=utf-8",
+	"confluence": "application/xhtml+xml; charset=utf-8",
+	"discord":    textType,
+	"eml":        "message/rfc822",
+	"html-email": "text/html; charset=utf-8",
+	"http":       "text/html; charset=utf-8",
+	"jira":       textType,
+	"slack":      textType,
+	"teams":      "application/json",
+	"telegram":   textType,
+	"terminal":   textType,
+	"text":       textType,
+}
+
+// New returns a new instance of Handler serving the converters
+func New(converters ...markdownconverter.Converter) *Handler {
+	mediaTypes := make(map[string]string, len(DefaultMediaTypes))
+	for format, mediaType := range DefaultMediaTypes {
+		mediaTypes[format] = mediaType
+	}
+
+	return &Handler{
+		MaxBodySize: DefaultMaxBodySize,
+		MediaTypes:  mediaTypes,
+		converters:  converters,
+	}
+}
+
+// Handler is an http.Handler that converts markdown sent with
+// POST /convert/{format} and lists the formats with GET /formats
+type Handler struct {
+	// MaxBodySize is the size in bytes above which request bodies are
+	// rejected, 0 for no limit
+	MaxBodySize int64
+	// MediaTypes maps format names to the media type of their output
+	MediaTypes map[string]string
+
+	converters []markdownconverter.Converter
+}
+
+// Format describes a format in the GET /formats response
+type Format struct {
+	Name      string `json:"name"`
+	MediaType string `json:"mediaType"`
+}
+
+// Conversion is the response to a conversion request that accepts JSON
+type Conversion struct {
+	Format string `json:"format"`
+	Output string `json:"output"`
+}
+
+// Error is the body of every error response
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ServeHTTP handles the request
+func (handler *Handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	switch {
+	case request.URL.Path == formatsPath:
+		if request.Method != http.MethodGet && request.Method != http.MethodHead {
+			writeMethodNotAllowed(writer, "GET, HEAD")
+			return
+		}
+		handler.formats(writer)
+	case strings.HasPrefix(request.URL.Path, convertPath):
+		if request.Method != http.MethodPost {
+			writeMethodNotAllowed(writer, "POST")
+			return
+		}
+		handler.convert(writer, request, strings.TrimPrefix(request.URL.Path, convertPath))
+	default:
+		writeError(writer, http.StatusNotFound, "not_found", fmt.Sprintf("no route for '%s'", request.URL.Path))
+	}
+}
+
+func (handler *Handler) formats(writer http.ResponseWriter) {
+	formats := make([]Format, 0, len(handler.converters))
+	for _, converter := range handler.converters {
+		formats = append(formats, Format{
+			Name:      converter.Format(),
+			MediaType: handler.mediaType(converter.Format()),
+		})
+	}
+	writeJSON(writer, http.StatusOK, struct {
+		Formats []Format `json:"formats"`
+	}{formats})
+}
+
+func (handler *Handler) convert(writer http.ResponseWriter, request *http.Request, format string) {
+	converter := handler.converter(format)
+	if converter == nil {
+		writeError(writer, http.StatusNotFound, "unknown_format", fmt.Sprintf("unknown format '%s'", format))
+		return
+	}
+
+	mediaType := handler.mediaType(format)
+	accept := request.Header.Get("Accept")
+	raw, asJSON := quality(accept, mediaType), quality(accept, jsonType)
+	if raw == 0 && asJSON == 0 {
+		writeError(writer, http.StatusNotAcceptable, "not_acceptable", fmt.Sprintf("the %s format produces %s or %s", format, mediaType, jsonType))
+		return
+	}
+
+	var body io.Reader = request.Body
+	if handler.MaxBodySize > 0 {
+		body = io.LimitReader(request.Body, handler.MaxBodySize+1)
+	}
+	markdown, err := ioutil.ReadAll(body)
+	if err != nil {
+		writeError(writer, http.StatusBadRequest, "invalid_body", fmt.Sprintf("failed to read request body %s", err))
+		return
+	}
+	if handler.MaxBodySize > 0 && int64(len(markdown)) > handler.MaxBodySize {
+		writeError(writer, http.StatusRequestEntityTooLarge, "body_too_large", fmt.Sprintf("request body is larger than %d bytes", handler.MaxBodySize))
+		return
+	}
+
+	output, err := converter.Parse(markdown)
+	if err != nil {
+		writeError(writer, http.StatusUnprocessableEntity, "parse_failed", fmt.Sprintf("failed to parse %s", err))
+		return
+	}
+
+	writer.Header().Set("Vary", "Accept")
+	if asJSON > raw {
+		writeJSON(writer, http.StatusOK, Conversion{Format: format, Output: string(output)})
+		return
+	}
+	writer.Header().Set("Content-Type", mediaType)
+	writer.Header().Set("Content-Length", strconv.Itoa(len(output)))
+	writer.WriteHeader(http.StatusOK)
+	writer.Write(output)
+}
+
+func (handler *Handler) converter(format string) markdownconverter.Converter {
+	for _, converter := range handler.converters {
+		if converter.Format() == format {
+			return converter
+		}
+	}
+	return nil
+}
+
+func (handler *Handler) mediaType(format string) string {
+	if mediaType, ok := handler.MediaTypes[format]; ok {
+		return mediaType
+	}
+	return textType
+}
+
+// quality returns the quality the Accept header gives the media type, from
+// 0 when it is not acceptable to 1, using the most specific media range that
+// includes it. An empty header accepts everything.
+func quality(accept, mediaType string) float64 {
+	if strings.TrimSpace(accept) == "" {
+		return 1
+	}
+	wanted, _, err := mime.ParseMediaType(mediaType)
+	if err != nil {
+		return 0
+	}
+
+	best, bestSpecificity := 0.0, -1
+	for _, mediaRange := range strings.Split(accept, ",") {
+		name, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil {
+			continue
+		}
+		rangeSpecificity := specificity(name, wanted)
+		if rangeSpecificity < 0 || rangeSpecificity < bestSpecificity {
+			continue
+		}
+		value := 1.0
+		if q, ok := params["q"]; ok {
+			if value, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		if rangeSpecificity > bestSpecificity || value > best {
+			best, bestSpecificity = value, rangeSpecificity
+		}
+	}
+	return best
+}
+
+// specificity returns how specific the media range is if it includes the
+// media type, 2 for the type itself, 1 for a range such as text/* and 0 for
+// */*, or -1 if it does not include it
+func specificity(mediaRange, mediaType string) int {
+	switch {
+	case mediaRange == mediaType:
+		return 2
+	case strings.HasSuffix(mediaRange, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(mediaRange, "*")):
+		return 1
+	case mediaRange == "*/*":
+		return 0
+	}
+	return -1
+}
+
+func writeMethodNotAllowed(writer http.ResponseWriter, allow string) {
+	writer.Header().Set("Allow", allow)
+	writeError(writer, http.StatusMethodNotAllowed, "method_not_allowed", fmt.Sprintf("method must be %s", allow))
+}
+
+func writeError(writer http.ResponseWriter, status int, code, message string) {
+	writeJSON(writer, status, struct {
+		Error Error `json:"error"`
+	}{Error{Code: code, Message: message}})
+}
+
+func writeJSON(writer http.ResponseWriter, status int, value interface{}) {
+	writer.Header().Set("Content-Type", jsonType)
+	writer.WriteHeader(status)
+	encoder := json.NewEncoder(writer)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(value)
+}
@@ -0,0 +1,176 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/evilmonkeyinc/markdownconverter/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingConverter struct{}
+
+func (failingConverter) Format() string {
+	return "failing"
+}
+
+func (failingConverter) Parse([]byte) ([]byte, error) {
+	return nil, fmt.Errorf("broken")
+}
+
+func Test_Handler_ServeHTTP(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		accept      string
+		body        string
+		status      int
+		contentType string
+		expected    string
+	}{
+		{
+			name:        "formats",
+			method:      http.MethodGet,
+			path:        "/formats",
+			status:      http.StatusOK,
+			contentType: "application/json",
+			expected:    `{"formats":[{"name":"slack","mediaType":"text/plain; charset=utf-8"},{"name":"failing","mediaType":"text/plain; charset=utf-8"}]}` + "\n",
+		},
+		{
+			name:        "formats_method",
+			method:      http.MethodPost,
+			path:        "/formats",
+			status:      http.StatusMethodNotAllowed,
+			contentType: "application/json",
+			expected:    `{"error":{"code":"method_not_allowed","message":"method must be GET, HEAD"}}` + "\n",
+		},
+		{
+			name:        "convert",
+			method:      http.MethodPost,
+			path:        "/convert/slack",
+			body:        "# Heading",
+			status:      http.StatusOK,
+			contentType: "text/plain; charset=utf-8",
+			expected:    "*Heading*",
+		},
+		{
+			name:        "convert_accept_media_type",
+			method:      http.MethodPost,
+			path:        "/convert/slack",
+			accept:      "text/*, application/json;q=0.5",
+			body:        "# Heading",
+			status:      http.StatusOK,
+			contentType: "text/plain; charset=utf-8",
+			expected:    "*Heading*",
+		},
+		{
+			name:        "convert_accept_json",
+			method:      http.MethodPost,
+			path:        "/convert/slack",
+			accept:      "application/json",
+			body:        "<b>",
+			status:      http.StatusOK,
+			contentType: "application/json",
+			expected:    `{"format":"slack","output":"<b>"}` + "\n",
+		},
+		{
+			name:        "convert_not_acceptable",
+			method:      http.MethodPost,
+			path:        "/convert/slack",
+			accept:      "text/html, text/plain;q=0",
+			body:        "text",
+			status:      http.StatusNotAcceptable,
+			contentType: "application/json",
+			expected:    `{"error":{"code":"not_acceptable","message":"the slack format produces text/plain; charset=utf-8 or application/json"}}` + "\n",
+		},
+		{
+			name:        "convert_method",
+			method:      http.MethodGet,
+			path:        "/convert/slack",
+			status:      http.StatusMethodNotAllowed,
+			contentType: "application/json",
+			expected:    `{"error":{"code":"method_not_allowed","message":"method must be POST"}}` + "\n",
+		},
+		{
+			name:        "convert_unknown_format",
+			method:      http.MethodPost,
+			path:        "/convert/other",
+			status:      http.StatusNotFound,
+			contentType: "application/json",
+			expected:    `{"error":{"code":"unknown_format","message":"unknown format 'other'"}}` + "\n",
+		},
+		{
+			name:        "convert_too_large",
+			method:      http.MethodPost,
+			path:        "/convert/slack",
+			body:        "0123456789!",
+			status:      http.StatusRequestEntityTooLarge,
+			contentType: "application/json",
+			expected:    `{"error":{"code":"body_too_large","message":"request body is larger than 10 bytes"}}` + "\n",
+		},
+		{
+			name:        "convert_failed",
+			method:      http.MethodPost,
+			path:        "/convert/failing",
+			body:        "text",
+			status:      http.StatusUnprocessableEntity,
+			contentType: "application/json",
+			expected:    `{"error":{"code":"parse_failed","message":"failed to parse broken"}}` + "\n",
+		},
+		{
+			name:        "not_found",
+			method:      http.MethodGet,
+			path:        "/",
+			status:      http.StatusNotFound,
+			contentType: "application/json",
+			expected:    `{"error":{"code":"not_found","message":"no route for '/'"}}` + "\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handler := New(slack.New(), failingConverter{})
+			handler.MaxBodySize = 10
+
+			request := httptest.NewRequest(test.method, test.path, strings.NewReader(test.body))
+			if test.accept != "" {
+				request.Header.Set("Accept", test.accept)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			assert.Equal(t, test.status, recorder.Code)
+			assert.Equal(t, test.contentType, recorder.Header().Get("Content-Type"))
+			assert.Equal(t, test.expected, recorder.Body.String())
+		})
+	}
+}
+
+func Test_quality(t *testing.T) {
+
+	tests := []struct {
+		accept   string
+		expected float64
+	}{
+		{accept: "", expected: 1},
+		{accept: "*/*", expected: 1},
+		{accept: "text/*;q=0.4", expected: 0.4},
+		{accept: "text/plain;q=0.2, */*;q=0.1", expected: 0.2},
+		{accept: "application/json", expected: 0},
+		{accept: "text/plain;q=invalid", expected: 0},
+		{accept: "*/*;q=0.1, text/plain;q=0", expected: 0},
+		{accept: "text/plain;q=0.3, text/*;q=0.9", expected: 0.3},
+		{accept: "text/*;q=0.2, */*;q=0.8", expected: 0.2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.accept, func(t *testing.T) {
+			assert.Equal(t, test.expected, quality(test.accept, "text/plain; charset=utf-8"))
+		})
+	}
+}
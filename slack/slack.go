@@ -5,7 +5,11 @@ import (
 	"io"
 	"strings"
 	"text/tabwriter"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/evilmonkeyinc/markdownconverter"
+	"github.com/evilmonkeyinc/markdownconverter/links"
 	"github.com/gomarkdown/markdown"
 	"github.com/gomarkdown/markdown/ast"
 	"github.com/gomarkdown/markdown/parser"
@@ -18,6 +22,13 @@ func New() *Converter {
 
 // Converter is the Slack markdwn Converter implementation
 type Converter struct {
+	// Links is used to rewrite the destinations of links and images, leave nil
+	// to keep them as they are
+	Links *links.Rewriter
+	// DiagramLink is the URL linked in place of Mermaid, PlantUML, and Graphviz
+	// blocks, such as the published page with the rendered diagrams. When empty
+	// the diagram source is kept in a labelled code block.
+	DiagramLink string
 }
 
 // Format returns a unique name for the converter
@@ -29,21 +40,40 @@ func (converter *Converter) Format() string {
 func (converter *Converter) Parse(markdwn []byte) ([]byte, error) {
 	parser := parser.New()
 
-	data := markdown.NormalizeNewlines(markdwn)
+	_, body := markdownconverter.FrontMatter(markdwn)
+	data := markdown.NormalizeNewlines(body)
 	node := parser.Parse(data)
+	markdownconverter.Admonitions(node)
+	if converter.Links != nil {
+		if err := converter.Links.Rewrite(node); err != nil {
+			return nil, err
+		}
+	}
 
-	bytes := markdown.Render(node, &renderer{})
+	bytes := markdown.Render(node, &renderer{diagramLink: converter.DiagramLink})
 	return []byte(strings.TrimSpace(string(bytes))), nil
 }
 
+// admonitionEmoji are the emoji shown before the label of each admonition type
+var admonitionEmoji map[string]string = map[string]string{
+	"caution":   ":no_entry:",
+	"important": ":exclamation:",
+	"note":      ":information_source:",
+	"tip":       ":bulb:",
+	"warning":   ":warning:",
+}
+
 type renderer struct {
+	diagramLink string
+	// heading is true inside a heading, which is already bold
+	heading bool
 }
 
 func (rend *renderer) RenderNode(w io.Writer, node ast.Node, entering bool) ast.WalkStatus {
 	// fmt.Println(reflect.TypeOf(node), entering)
 	if !entering {
 		switch node.(type) {
-		case *ast.Link, *ast.TableCell, *ast.TableBody, *ast.TableHeader:
+		case *ast.Del, *ast.Emph, *ast.Link, *ast.Strong, *ast.TableCell, *ast.TableBody, *ast.TableHeader:
 			break
 		default:
 			fmt.Fprint(w, "\n")
@@ -53,12 +83,21 @@ func (rend *renderer) RenderNode(w io.Writer, node ast.Node, entering bool) ast.
 	}
 
 	switch node.(type) {
+	case *markdownconverter.Admonition:
+		admonition := node.(*markdownconverter.Admonition)
+		lines := []string{fmt.Sprintf("%s *%s*", admonitionEmoji[admonition.Type], admonition.Label())}
+		for _, child := range admonition.Children {
+			childData := strings.TrimSpace(string(markdown.Render(child, rend)))
+			lines = append(lines, strings.Split(childData, "\n")...)
+		}
+		fmt.Fprintf(w, "\n> %s", strings.Join(lines, "\n> "))
+		return ast.SkipChildren
 	case *ast.BlockQuote:
 		blockquote := node.(*ast.BlockQuote)
 		for _, child := range blockquote.Children {
 			childData := markdown.Render(child, rend)
 			data := strings.TrimSpace(string(childData))
-			fmt.Fprintf(w, "> %s", data)
+			fmt.Fprintf(w, "\n> %s", data)
 		}
 		return ast.SkipChildren
 	case *ast.Code:
@@ -69,32 +108,35 @@ func (rend *renderer) RenderNode(w io.Writer, node ast.Node, entering bool) ast.
 	case *ast.CodeBlock:
 		code := node.(*ast.CodeBlock)
 		codeBlock := strings.TrimSpace(string(code.Literal))
-		fmt.Fprintf(w, "```\n%s\n```", codeBlock)
+		if diagramType := markdownconverter.DiagramType(code.Info); diagramType != "" {
+			rend.renderDiagram(w, diagramType, codeBlock)
+			return ast.GoToNext
+		}
+		// code blocks are leaf nodes, which are not rendered again on exit to
+		// end the line
+		fmt.Fprintf(w, "```\n%s\n```\n", codeBlock)
+		return ast.GoToNext
+	case *ast.Math:
+		math := node.(*ast.Math)
+		fmt.Fprintf(w, "`%s`", strings.TrimSpace(string(math.Literal)))
 		return ast.GoToNext
+	case *ast.MathBlock:
+		math := node.(*ast.MathBlock)
+		fmt.Fprintf(w, "```\n%s\n```", strings.TrimSpace(string(math.Literal)))
+		return ast.SkipChildren
 	case *ast.Del:
-		strikethrough := node.(*ast.Del)
-		for _, child := range strikethrough.Children {
-			childData := markdown.Render(child, rend)
-			clean := strings.TrimSpace(string(childData))
-			fmt.Fprintf(w, "~%s~", clean)
-		}
+		rend.renderFormatted(w, node, "~")
 		return ast.SkipChildren
 	case *ast.Document:
 		return ast.GoToNext
 	case *ast.Emph:
-		emphasis := node.(*ast.Emph)
-		for _, child := range emphasis.Children {
-			childData := markdown.Render(child, rend)
-			clean := strings.TrimSpace(string(childData))
-			fmt.Fprintf(w, "_%s_", clean)
-		}
+		rend.renderFormatted(w, node, "_")
 		return ast.SkipChildren
 	case *ast.Heading:
-		heading := node.(*ast.Heading)
-		for _, child := range heading.Children {
-			childData := markdown.Render(child, rend)
-			fmt.Fprintf(w, "\n*%s*", string(childData))
-		}
+		rend.heading = true
+		content := strings.TrimSpace(rend.renderChildren(node))
+		rend.heading = false
+		fmt.Fprintf(w, "\n*%s*", content)
 		return ast.SkipChildren
 	case *ast.HorizontalRule:
 		fmt.Fprint(w, "\n\n")
@@ -141,12 +183,11 @@ func (rend *renderer) RenderNode(w io.Writer, node ast.Node, entering bool) ast.
 		fmt.Fprint(w, "\n")
 		return ast.GoToNext
 	case *ast.Strong:
-		strong := node.(*ast.Strong)
-		for _, child := range strong.Children {
-			childData := markdown.Render(child, rend)
-			clean := strings.TrimSpace(string(childData))
-			fmt.Fprintf(w, "*%s*", clean)
+		if rend.heading {
+			fmt.Fprint(w, rend.renderChildren(node))
+			return ast.SkipChildren
 		}
+		rend.renderFormatted(w, node, "*")
 		return ast.SkipChildren
 	case *ast.Table:
 		fmt.Fprint(w, "\n")
@@ -212,6 +253,95 @@ func (rend *renderer) RenderNode(w io.Writer, node ast.Node, entering bool) ast.
 	return ast.GoToNext
 }
 
+// zeroWidthSpace is used to separate formatting markers from adjacent word
+// characters, as Slack will only recognise markers on a word boundary
+const zeroWidthSpace = "\u200B"
+
+// renderFormatted renders the children of an inline formatting node as a
+// single run wrapped in the marker, keeping surrounding whitespace outside of
+// the markers and guarding them from adjacent word characters
+func (rend *renderer) renderFormatted(w io.Writer, node ast.Node, marker string) {
+	content := rend.renderChildren(node)
+	trimmed := strings.TrimSpace(content)
+	if trimmed == "" {
+		fmt.Fprint(w, content)
+		return
+	}
+	leading := content[:strings.Index(content, trimmed)]
+	trailing := content[len(leading)+len(trimmed):]
+
+	fmt.Fprint(w, leading)
+	if leading == "" && needsGuard(ast.GetPrevNode(node), false) {
+		fmt.Fprint(w, zeroWidthSpace)
+	}
+	fmt.Fprintf(w, "%s%s%s", marker, trimmed, marker)
+	if trailing == "" && needsGuard(ast.GetNextNode(node), true) {
+		fmt.Fprint(w, zeroWidthSpace)
+	}
+	fmt.Fprint(w, trailing)
+}
+
+// renderChildren renders each child of the node in turn and returns the
+// combined output
+func (rend *renderer) renderChildren(node ast.Node) string {
+	content := ""
+	for _, child := range node.GetChildren() {
+		content += string(markdown.Render(child, rend))
+	}
+	return content
+}
+
+// renderDiagram writes a link to the diagram if there is a diagram link,
+// otherwise its source in a code block labelled with the diagram type
+func (rend *renderer) renderDiagram(w io.Writer, diagramType, source string) {
+	label := markdownconverter.DiagramLabel(diagramType)
+	if rend.diagramLink != "" {
+		fmt.Fprintf(w, "<%s|View %s diagram>\n", rend.diagramLink, label)
+		return
+	}
+	fmt.Fprintf(w, "_%s diagram_\n```\n%s\n```\n", label, source)
+}
+
+// needsGuard returns true if the sibling node would place a word character or
+// another formatting marker directly against a marker
+func needsGuard(sibling ast.Node, next bool) bool {
+	switch sibling := sibling.(type) {
+	case nil:
+		return false
+	case *ast.Code:
+		return true
+	case *ast.Del, *ast.Emph, *ast.Strong:
+		// a preceding formatted node will already have guarded its end
+		return next
+	case *ast.Text:
+		literal := string(sibling.Literal)
+		if literal == "" {
+			// the parser leaves empty text nodes between inline nodes
+			if next {
+				return needsGuard(ast.GetNextNode(sibling), next)
+			}
+			return needsGuard(ast.GetPrevNode(sibling), next)
+		}
+		var r rune
+		if next {
+			r, _ = utf8.DecodeRuneInString(literal)
+		} else {
+			r, _ = utf8.DecodeLastRuneInString(literal)
+		}
+		return isWordRune(r)
+	default:
+		return false
+	}
+}
+
+// isWordRune returns true if Slack would treat the rune as part of a word
+func isWordRune(r rune) bool {
+	if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) {
+		return true
+	}
+	return strings.ContainsRune("*_~`", r)
+}
+
 func (rend *renderer) RenderHeader(w io.Writer, ast ast.Node) {}
 
 func (rend *renderer) RenderFooter(w io.Writer, ast ast.Node) {}
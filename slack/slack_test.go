@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/evilmonkeyinc/markdownconverter/links"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -24,6 +25,14 @@ func Test_Converter_Parse(t *testing.T) {
 			input:    "# Heading 1",
 			expected: "*Heading 1*",
 		},
+		{
+			input:    "# Heading **bold** _italic_",
+			expected: "*Heading bold _italic_*",
+		},
+		{
+			input:    "---\ntitle: Notes\n---\n# Heading 1",
+			expected: "*Heading 1*",
+		},
 		{
 			input:    "## Heading 2",
 			expected: "*Heading 2*",
@@ -80,6 +89,10 @@ func Test_Converter_Parse(t *testing.T) {
 			input:    "> blockquote",
 			expected: "> blockquote",
 		},
+		{
+			input:    "paragraph\n\n> blockquote",
+			expected: "paragraph\n\n> blockquote",
+		},
 		{
 			input:    "+ one\n+ two\n+ three",
 			expected: "• one\n• two\n• three",
@@ -176,7 +189,7 @@ _This is italic text_
 | short value | longer value | really long value |
 | qwerty | asdfgh | zxcvbn |
 `,
-			expected: "*Heading 1*\n\n*Heading 2*\n\n*Heading 3*\n\n*Heading 4*\n\n*Heading 5*\n\n*Heading 6*\n\n*This is bold text*\n\n\n*This is bold text*\n\n\n_This is italic text_\n\n\n_This is italic text_\n\n\n~Strikethrough~\n\n> blockquote\n• one\n• two\n• three\n\n1. one\n2. two\n3. three\n\n\n<https://github.com/evilmonkeyinc|evilmonkeyinc>\n\n*Header 1*   *Header 2*    *Header 3*\nshort value  longer value  really long value\nqwerty       asdfgh        zxcvbn",
+			expected: "*Heading 1*\n\n*Heading 2*\n\n*Heading 3*\n\n*Heading 4*\n\n*Heading 5*\n\n*Heading 6*\n\n*This is bold text*\n\n*This is bold text*\n\n_This is italic text_\n\n_This is italic text_\n\n~Strikethrough~\n\n> blockquote\n• one\n• two\n• three\n\n1. one\n2. two\n3. three\n\n\n<https://github.com/evilmonkeyinc|evilmonkeyinc>\n\n*Header 1*   *Header 2*    *Header 3*\nshort value  longer value  really long value\nqwerty       asdfgh        zxcvbn",
 		},
 	}
 
@@ -187,3 +200,252 @@ _This is italic text_
 		})
 	}
 }
+
+func Test_Converter_Parse_InlineFormatting(t *testing.T) {
+
+	converter := New()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "bold_mid_sentence",
+			input:    "some **bold** text",
+			expected: "some *bold* text",
+		},
+		{
+			name:     "italic_mid_sentence",
+			input:    "some *italic* text",
+			expected: "some _italic_ text",
+		},
+		{
+			name:     "strikethrough_mid_sentence",
+			input:    "some ~~deleted~~ text",
+			expected: "some ~deleted~ text",
+		},
+		{
+			name:     "italic_inside_bold",
+			input:    "**bold _and italic_ text**",
+			expected: "*bold _and italic_ text*",
+		},
+		{
+			name:     "bold_inside_italic",
+			input:    "_italic **and bold** text_",
+			expected: "_italic *and bold* text_",
+		},
+		{
+			name:     "strikethrough_inside_bold",
+			input:    "**bold ~~and deleted~~ text**",
+			expected: "*bold ~and deleted~ text*",
+		},
+		{
+			name:     "bold_inside_strikethrough",
+			input:    "~~deleted **and bold** text~~",
+			expected: "~deleted *and bold* text~",
+		},
+		{
+			name:     "italic_at_start_of_bold",
+			input:    "**_italic_ then bold**",
+			expected: "*_italic_ then bold*",
+		},
+		{
+			name:     "italic_at_end_of_bold",
+			input:    "**bold then _italic_**",
+			expected: "*bold then _italic_*",
+		},
+		{
+			name:     "bold_and_italic",
+			input:    "***both***",
+			expected: "*_both_*",
+		},
+		{
+			name:     "three_levels",
+			input:    "~~deleted **bold _italic_**~~",
+			expected: "~deleted *bold _italic_*~",
+		},
+		{
+			name:     "code_inside_bold",
+			input:    "**bold `code` text**",
+			expected: "*bold `code` text*",
+		},
+		{
+			name:     "link_inside_bold",
+			input:    "**see [evilmonkeyinc](https://github.com/evilmonkeyinc)**",
+			expected: "*see <https://github.com/evilmonkeyinc|evilmonkeyinc>*",
+		},
+		{
+			name:     "intra_word_bold",
+			input:    "foo**bar**baz",
+			expected: "foo\u200B*bar*\u200Bbaz",
+		},
+		{
+			name:     "intra_word_strikethrough",
+			input:    "foo~~bar~~baz",
+			expected: "foo\u200B~bar~\u200Bbaz",
+		},
+		{
+			name:     "word_before_bold",
+			input:    "foo**bar** baz",
+			expected: "foo\u200B*bar* baz",
+		},
+		{
+			name:     "word_after_bold",
+			input:    "foo **bar**baz",
+			expected: "foo *bar*\u200Bbaz",
+		},
+		{
+			name:     "digits_around_bold",
+			input:    "1**2**3",
+			expected: "1\u200B*2*\u200B3",
+		},
+		{
+			name:     "unicode_letters_around_bold",
+			input:    "é**ü**ß",
+			expected: "é\u200B*ü*\u200Bß",
+		},
+		{
+			name:     "punctuation_around_bold",
+			input:    "(**bold**).",
+			expected: "(*bold*).",
+		},
+		{
+			name:     "adjacent_formatting",
+			input:    "**bold**~~deleted~~",
+			expected: "*bold*\u200B~deleted~",
+		},
+		{
+			name:     "adjacent_formatting_and_code",
+			input:    "**bold**`code`",
+			expected: "*bold*\u200B`code`",
+		},
+		{
+			name:     "code_then_formatting",
+			input:    "`code`**bold**",
+			expected: "`code`\u200B*bold*",
+		},
+		{
+			name:     "multiple_in_sentence",
+			input:    "one **two** three *four* five ~~six~~",
+			expected: "one *two* three _four_ five ~six~",
+		},
+		{
+			name:     "multiple_paragraphs",
+			input:    "**bold**\n\n*italic*",
+			expected: "*bold*\n\n_italic_",
+		},
+		{
+			name:     "heading_with_italic",
+			input:    "# Heading _italic_",
+			expected: "*Heading _italic_*",
+		},
+		{
+			name:     "list_item_with_bold",
+			input:    "- one **bold**\n- two",
+			expected: "• one *bold*\n• two",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, _ := converter.Parse([]byte(test.input))
+			assert.Equal(t, test.expected, string(actual))
+		})
+	}
+}
+
+func Test_Converter_Parse_Math(t *testing.T) {
+	actual, err := New().Parse([]byte("inline $x^2$ math\n\n$$\n\\frac{1}{2}\n$$"))
+	assert.Nil(t, err)
+	assert.Equal(t, "inline `x^2` math\n```\n\\frac{1}{2}\n```", string(actual))
+
+	actual, err = New().Parse([]byte("```\ncode\n```\n\n$$\nx\n$$\n\n```\nmore\n```"))
+	assert.Nil(t, err)
+	assert.Equal(t, "```\ncode\n```\n```\nx\n```\n```\nmore\n```", string(actual))
+}
+
+func Test_Converter_Parse_Diagrams(t *testing.T) {
+
+	input := "```mermaid\ngraph TD\n  A-->B\n```\n\n```puml\nA -> B\n```\n\n```go\nfunc main() {}\n```"
+
+	tests := []struct {
+		name        string
+		diagramLink string
+		expected    string
+	}{
+		{
+			name:     "code_block",
+			expected: "_Mermaid diagram_\n```\ngraph TD\n  A-->B\n```\n_PlantUML diagram_\n```\nA -> B\n```\n```\nfunc main() {}\n```",
+		},
+		{
+			name:        "link",
+			diagramLink: "https://example.com/docs/",
+			expected:    "<https://example.com/docs/|View Mermaid diagram>\n<https://example.com/docs/|View PlantUML diagram>\n```\nfunc main() {}\n```",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			converter := New()
+			converter.DiagramLink = test.diagramLink
+			actual, err := converter.Parse([]byte(input))
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, string(actual))
+		})
+	}
+}
+
+func Test_Converter_Parse_Admonitions(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "note",
+			input:    "> [!NOTE]\n> Useful *information*\n> on two lines",
+			expected: "> :information_source: *Note*\n> Useful _information_\n> on two lines",
+		},
+		{
+			name:     "warning",
+			input:    "text\n\n> [!warning]\n> Be careful\n\nmore",
+			expected: "text\n\n> :warning: *Warning*\n> Be careful\n\nmore",
+		},
+		{
+			name:     "empty",
+			input:    "> [!CAUTION]",
+			expected: "> :no_entry: *Caution*",
+		},
+		{
+			name:     "not_admonition",
+			input:    "> [!NOTE] inline",
+			expected: "> [!NOTE] inline",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := New().Parse([]byte(test.input))
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, string(actual))
+		})
+	}
+}
+
+func Test_Converter_Parse_Links(t *testing.T) {
+	converter := New()
+	converter.Links = &links.Rewriter{
+		BaseURL:        "https://example.com/docs/",
+		MarkdownToHTML: true,
+	}
+
+	actual, err := converter.Parse([]byte("[setup](./setup.md) and [home](https://example.com)"))
+	assert.Nil(t, err)
+	assert.Equal(t, "<https://example.com/docs/setup.html|setup> and <https://example.com|home>", string(actual))
+
+	converter.Links.BaseURL = "invalid"
+	_, err = converter.Parse([]byte("[setup](./setup.md)"))
+	assert.ErrorIs(t, err, links.ErrInvalidBaseURL)
+}
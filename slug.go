@@ -0,0 +1,57 @@
+package markdownconverter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Slugger generates GitHub compatible heading IDs, adding a numbered suffix
+// when the same ID has already been used in the document
+type Slugger struct {
+	occurrences map[string]int
+}
+
+// NewSlugger returns a new instance of Slugger
+func NewSlugger() *Slugger {
+	return &Slugger{
+		occurrences: make(map[string]int),
+	}
+}
+
+// Reserve marks the ID as used, such as an ID set explicitly on a heading
+func (slugger *Slugger) Reserve(id string) {
+	slugger.occurrences[id] = 0
+}
+
+// Slug returns the unique ID for the text
+func (slugger *Slugger) Slug(text string) string {
+	original := Slug(text)
+	result := original
+	for {
+		if _, ok := slugger.occurrences[result]; !ok {
+			break
+		}
+		slugger.occurrences[original]++
+		result = fmt.Sprintf("%s-%d", original, slugger.occurrences[original])
+	}
+	slugger.occurrences[result] = 0
+	return result
+}
+
+// Slug converts the text to lower case, removes punctuation, and replaces
+// spaces with hyphens, matching the anchors GitHub generates for headings
+func Slug(text string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == ' ':
+			return '-'
+		case r == '-', r == '_':
+			return r
+		case unicode.IsLetter(r), unicode.IsMark(r), unicode.IsNumber(r), unicode.Is(unicode.Pc, r):
+			return unicode.ToLower(r)
+		default:
+			return -1
+		}
+	}, strings.ToLower(text))
+}
@@ -0,0 +1,41 @@
+package markdownconverter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Slug(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{name: "lower_case", text: "Heading", expected: "heading"},
+		{name: "spaces", text: "Getting Started", expected: "getting-started"},
+		{name: "punctuation", text: "snake_case & kebab-case", expected: "snake_case--kebab-case"},
+		{name: "unicode", text: "Café 中文", expected: "café-中文"},
+		{name: "empty", text: "!!!", expected: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, Slug(test.text))
+		})
+	}
+}
+
+func Test_Slugger_Slug(t *testing.T) {
+	slugger := NewSlugger()
+	slugger.Reserve("reserved")
+
+	assert.Equal(t, "heading", slugger.Slug("Heading"))
+	assert.Equal(t, "heading-1", slugger.Slug("Heading"))
+	assert.Equal(t, "heading-1-1", slugger.Slug("Heading 1"))
+	assert.Equal(t, "heading-2", slugger.Slug("Heading"))
+	assert.Equal(t, "reserved-1", slugger.Slug("Reserved"))
+	assert.Equal(t, "snake_case--kebab-case", slugger.Slug("snake_case & kebab-case"))
+	assert.Equal(t, "", slugger.Slug("!!!"))
+}
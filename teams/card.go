@@ -0,0 +1,61 @@
+package teams
+
+// message is the payload sent to an incoming webhook
+type message struct {
+	Type        string       `json:"type"`
+	Attachments []attachment `json:"attachments"`
+}
+
+type attachment struct {
+	ContentType string `json:"contentType"`
+	Content     *card  `json:"content"`
+}
+
+type card struct {
+	Schema  string     `json:"$schema"`
+	Type    string     `json:"type"`
+	Version string     `json:"version"`
+	Body    []*element `json:"body"`
+}
+
+// element is an Adaptive Card element, only the fields used by its type are
+// set
+type element struct {
+	Type      string `json:"type"`
+	Separator bool   `json:"separator,omitempty"`
+	Style     string `json:"style,omitempty"`
+
+	// TextBlock
+	Text   string `json:"text,omitempty"`
+	Wrap   bool   `json:"wrap,omitempty"`
+	Size   string `json:"size,omitempty"`
+	Weight string `json:"weight,omitempty"`
+
+	// Container
+	Items []*element `json:"items,omitempty"`
+
+	// FactSet
+	Facts []*fact `json:"facts,omitempty"`
+
+	// CodeBlock
+	CodeSnippet string `json:"codeSnippet,omitempty"`
+	Language    string `json:"language,omitempty"`
+
+	// Image
+	URL     string `json:"url,omitempty"`
+	AltText string `json:"altText,omitempty"`
+}
+
+type fact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// textBlock returns a TextBlock element that wraps the text
+func textBlock(text string) *element {
+	return &element{
+		Type: "TextBlock",
+		Text: text,
+		Wrap: true,
+	}
+}
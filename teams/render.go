@@ -0,0 +1,328 @@
+package teams
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/evilmonkeyinc/markdownconverter"
+	"github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/ast"
+)
+
+// headingSizes are the TextBlock sizes of each heading level, smaller
+// headings use the default size
+var headingSizes map[int]string = map[int]string{
+	1: "ExtraLarge",
+	2: "Large",
+	3: "Medium",
+}
+
+// admonitionStyles are the Container styles of each admonition type
+var admonitionStyles map[string]string = map[string]string{
+	"caution":   "attention",
+	"important": "accent",
+	"note":      "accent",
+	"tip":       "good",
+	"warning":   "warning",
+}
+
+// codeLanguages are the CodeBlock languages supported by Teams, keyed by the
+// fenced code block language
+var codeLanguages map[string]string = map[string]string{
+	"bash":        "Bash",
+	"c":           "C",
+	"c#":          "C#",
+	"c++":         "C++",
+	"cpp":         "C++",
+	"cs":          "C#",
+	"csharp":      "C#",
+	"css":         "CSS",
+	"go":          "Go",
+	"golang":      "Go",
+	"graphql":     "GraphQL",
+	"html":        "HTML",
+	"java":        "Java",
+	"javascript":  "JavaScript",
+	"js":          "JavaScript",
+	"json":        "JSON",
+	"objective-c": "Objective-C",
+	"perl":        "Perl",
+	"php":         "PHP",
+	"powershell":  "PowerShell",
+	"ps1":         "PowerShell",
+	"py":          "Python",
+	"python":      "Python",
+	"sh":          "Bash",
+	"shell":       "Bash",
+	"sql":         "SQL",
+	"ts":          "TypeScript",
+	"typescript":  "TypeScript",
+	"vb":          "Visual Basic",
+	"xml":         "XML",
+}
+
+// plainText is the CodeBlock language used for unsupported languages
+const plainText string = "PlainText"
+
+type renderer struct{}
+
+// blocks returns the elements for each block node, a horizontal rule adds a
+// separator to the element that follows it
+func (renderer *renderer) blocks(nodes []ast.Node) []*element {
+	elements := make([]*element, 0, len(nodes))
+	separator := false
+	for _, node := range nodes {
+		if _, ok := node.(*ast.HorizontalRule); ok {
+			separator = true
+			continue
+		}
+		blockElements := renderer.block(node)
+		if len(blockElements) > 0 && separator {
+			blockElements[0].Separator = true
+			separator = false
+		}
+		elements = append(elements, blockElements...)
+	}
+	return elements
+}
+
+func (renderer *renderer) block(node ast.Node) []*element {
+	switch node := node.(type) {
+	case *ast.Heading:
+		heading := textBlock(renderer.inline(node))
+		heading.Style = "heading"
+		heading.Size = headingSizes[node.Level]
+		heading.Weight = "Bolder"
+		return []*element{heading}
+	case *ast.Paragraph:
+		if image, ok := markdownconverter.OnlyImage(node); ok {
+			return []*element{{
+				Type:    "Image",
+				URL:     string(image.Destination),
+				AltText: markdownconverter.PlainText(image),
+			}}
+		}
+		if text := renderer.inline(node); text != "" {
+			return []*element{textBlock(text)}
+		}
+		return nil
+	case *ast.BlockQuote:
+		return []*element{{
+			Type:  "Container",
+			Style: "emphasis",
+			Items: required(renderer.blocks(node.Children)),
+		}}
+	case *markdownconverter.Admonition:
+		label := textBlock(node.Label())
+		label.Weight = "Bolder"
+		return []*element{{
+			Type:  "Container",
+			Style: admonitionStyles[node.Type],
+			Items: append([]*element{label}, renderer.blocks(node.Children)...),
+		}}
+	case *ast.CodeBlock:
+		code := strings.TrimRight(string(node.Literal), "\n")
+		if diagramType := markdownconverter.DiagramType(node.Info); diagramType != "" {
+			label := textBlock(markdownconverter.DiagramLabel(diagramType) + " diagram")
+			label.Weight = "Bolder"
+			return []*element{label, codeBlock(code, plainText)}
+		}
+		return []*element{codeBlock(code, codeLanguage(node.Info))}
+	case *ast.MathBlock:
+		return []*element{codeBlock(strings.TrimSpace(string(node.Literal)), plainText)}
+	case *ast.List:
+		return renderer.list(node)
+	case *ast.Table:
+		return renderer.table(node)
+	case *ast.HTMLBlock:
+		return nil
+	default:
+		if container := node.AsContainer(); container != nil {
+			return renderer.blocks(container.Children)
+		}
+		if text := strings.TrimSpace(string(node.AsLeaf().Literal)); text != "" {
+			return []*element{textBlock(text)}
+		}
+		return nil
+	}
+}
+
+// list returns a Container for each list item with the elements of its
+// blocks, the item marker is added before the text of the first block
+func (renderer *renderer) list(node *ast.List) []*element {
+	start := node.Start
+	if start == 0 {
+		start = 1
+	}
+
+	elements := make([]*element, 0, len(node.Children))
+	for index, item := range node.Children {
+		marker := "-"
+		if node.ListFlags&ast.ListTypeOrdered != 0 {
+			marker = fmt.Sprintf("%d.", index+start)
+		}
+		items := renderer.blocks(item.GetChildren())
+		if len(items) > 0 && items[0].Type == "TextBlock" {
+			items[0].Text = marker + " " + items[0].Text
+		} else {
+			items = append([]*element{textBlock(marker)}, items...)
+		}
+		elements = append(elements, &element{Type: "Container", Items: items})
+	}
+	return elements
+}
+
+// table returns a FactSet for the table. A table with two columns is a
+// single FactSet with a fact for each row, starting with the column headers
+// in bold, otherwise each row is a FactSet with a fact for each column titled
+// with the column header.
+func (renderer *renderer) table(node *ast.Table) []*element {
+	rows := make([][]string, 0)
+	ast.WalkFunc(node, func(child ast.Node, entering bool) ast.WalkStatus {
+		row, ok := child.(*ast.TableRow)
+		if !ok || !entering {
+			return ast.GoToNext
+		}
+		cells := make([]string, 0, len(row.Children))
+		for _, cell := range row.Children {
+			cells = append(cells, renderer.inline(cell))
+		}
+		rows = append(rows, cells)
+		return ast.SkipChildren
+	})
+	if len(rows) == 0 {
+		return nil
+	}
+
+	headers, rows := rows[0], rows[1:]
+	if len(headers) == 2 {
+		facts := make([]*fact, 0, len(rows)+1)
+		facts = append(facts, &fact{Title: "**" + headers[0] + "**", Value: "**" + headers[1] + "**"})
+		for _, row := range rows {
+			facts = append(facts, &fact{Title: cell(row, 0), Value: cell(row, 1)})
+		}
+		return []*element{{Type: "FactSet", Facts: facts}}
+	}
+
+	if len(rows) == 0 {
+		// a table with only headers is shown as a row without values
+		rows = [][]string{nil}
+	}
+	elements := make([]*element, 0, len(rows))
+	for index, row := range rows {
+		facts := make([]*fact, 0, len(headers))
+		for column, header := range headers {
+			facts = append(facts, &fact{Title: header, Value: cell(row, column)})
+		}
+		elements = append(elements, &element{
+			Type:      "FactSet",
+			Separator: index > 0,
+			Facts:     facts,
+		})
+	}
+	return elements
+}
+
+// inline renders the inline children of the node as TextBlock markdown
+func (renderer *renderer) inline(node ast.Node) string {
+	return strings.TrimSpace(renderer.renderChildren(node))
+}
+
+// renderChildren renders each child of the node in turn and returns the
+// combined output
+func (renderer *renderer) renderChildren(node ast.Node) string {
+	content := ""
+	for _, child := range node.GetChildren() {
+		content += string(markdown.Render(child, renderer))
+	}
+	return content
+}
+
+// RenderNode writes the inline node as TextBlock markdown
+func (renderer *renderer) RenderNode(w io.Writer, node ast.Node, entering bool) ast.WalkStatus {
+	if !entering {
+		return ast.GoToNext
+	}
+
+	switch node := node.(type) {
+	case *ast.Text:
+		fmt.Fprint(w, strings.ReplaceAll(string(node.Literal), "\n", " "))
+	case *ast.Code:
+		fmt.Fprintf(w, "`%s`", string(node.Literal))
+	case *ast.Math:
+		fmt.Fprintf(w, "`%s`", strings.TrimSpace(string(node.Literal)))
+	case *ast.Emph:
+		fmt.Fprintf(w, "_%s_", renderer.inline(node))
+		return ast.SkipChildren
+	case *ast.Strong:
+		fmt.Fprintf(w, "**%s**", renderer.inline(node))
+		return ast.SkipChildren
+	case *ast.Hardbreak:
+		fmt.Fprint(w, "\n")
+	case *ast.Link:
+		text := renderer.inline(node)
+		if text == "" {
+			text = string(node.Destination)
+		}
+		fmt.Fprintf(w, "[%s](%s)", text, string(node.Destination))
+		return ast.SkipChildren
+	case *ast.Image:
+		text := markdownconverter.PlainText(node)
+		if text == "" {
+			text = string(node.Destination)
+		}
+		fmt.Fprintf(w, "[%s](%s)", text, string(node.Destination))
+		return ast.SkipChildren
+	case *ast.HTMLSpan:
+	default:
+		if leaf := node.AsLeaf(); leaf != nil {
+			fmt.Fprint(w, string(leaf.Literal))
+		}
+	}
+	return ast.GoToNext
+}
+
+func (renderer *renderer) RenderHeader(w io.Writer, ast ast.Node) {}
+
+func (renderer *renderer) RenderFooter(w io.Writer, ast ast.Node) {}
+
+// required returns the elements, or an empty TextBlock if there are none, for
+// the Containers that must have items such as an empty quote
+func required(elements []*element) []*element {
+	if len(elements) == 0 {
+		return []*element{textBlock(" ")}
+	}
+	return elements
+}
+
+// codeBlock returns a CodeBlock element for the code
+func codeBlock(code, language string) *element {
+	return &element{
+		Type:        "CodeBlock",
+		CodeSnippet: code,
+		Language:    language,
+	}
+}
+
+// codeLanguage returns the CodeBlock language of the fenced code block
+// language, or PlainText if Teams does not support it
+func codeLanguage(info []byte) string {
+	fields := strings.Fields(string(info))
+	if len(fields) == 0 {
+		return plainText
+	}
+	if language, ok := codeLanguages[strings.ToLower(fields[0])]; ok {
+		return language
+	}
+	return plainText
+}
+
+// cell returns the value of the column in the row, or an empty string if the
+// row is too short
+func cell(row []string, column int) string {
+	if column < len(row) {
+		return row[column]
+	}
+	return ""
+}
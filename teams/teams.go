@@ -0,0 +1,87 @@
+// Package teams converts markdown to an Adaptive Card for Microsoft Teams
+package teams
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/evilmonkeyinc/markdownconverter"
+	"github.com/evilmonkeyinc/markdownconverter/links"
+	"github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/parser"
+)
+
+const (
+	// DefaultVersion is the Adaptive Card schema version, the first to
+	// include the CodeBlock element
+	DefaultVersion string = "1.5"
+
+	cardSchema      string = "http://adaptivecards.io/schemas/adaptive-card.json"
+	cardContentType string = "application/vnd.microsoft.card.adaptive"
+)
+
+// New returns a new instance of Converter
+func New() *Converter {
+	return &Converter{
+		Message: true,
+		Version: DefaultVersion,
+	}
+}
+
+// Converter is the Microsoft Teams Adaptive Card Converter implementation
+type Converter struct {
+	// Links is used to rewrite the destinations of links and images, leave nil
+	// to keep them as they are
+	Links *links.Rewriter
+	// Message wraps the card in a message with the card as an attachment, the
+	// payload expected by Teams incoming webhooks. When false only the card
+	// is returned.
+	Message bool
+	// Version is the Adaptive Card schema version of the card
+	Version string
+}
+
+// Format returns a unique name for the converter
+func (converter *Converter) Format() string {
+	return "teams"
+}
+
+// Parse will parse the standard markdown and return the converted data
+func (converter *Converter) Parse(markdwn []byte) ([]byte, error) {
+	_, body := markdownconverter.FrontMatter(markdwn)
+	document := markdown.Parse(markdown.NormalizeNewlines(body), parser.NewWithExtensions(parser.CommonExtensions|parser.OrderedListStart))
+	markdownconverter.Admonitions(document)
+	if converter.Links != nil {
+		if err := converter.Links.Rewrite(document); err != nil {
+			return nil, err
+		}
+	}
+
+	renderer := &renderer{}
+	var payload interface{} = &card{
+		Schema:  cardSchema,
+		Type:    "AdaptiveCard",
+		Version: converter.Version,
+		Body:    renderer.blocks(document.GetChildren()),
+	}
+	if converter.Message {
+		payload = &message{
+			Type: "message",
+			Attachments: []attachment{
+				{
+					ContentType: cardContentType,
+					Content:     payload.(*card),
+				},
+			},
+		}
+	}
+
+	buffer := &bytes.Buffer{}
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(payload); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSpace(buffer.Bytes()), nil
+}
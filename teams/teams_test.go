@@ -0,0 +1,173 @@
+package teams
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/evilmonkeyinc/markdownconverter/links"
+	"github.com/stretchr/testify/assert"
+)
+
+// cardBody returns the JSON body of the card in the webhook message
+func cardBody(t *testing.T, output []byte) string {
+	payload := struct {
+		Type        string `json:"type"`
+		Attachments []struct {
+			ContentType string `json:"contentType"`
+			Content     struct {
+				Type    string          `json:"type"`
+				Version string          `json:"version"`
+				Body    json.RawMessage `json:"body"`
+			} `json:"content"`
+		} `json:"attachments"`
+	}{}
+	assert.Nil(t, json.Unmarshal(output, &payload))
+	assert.Equal(t, "message", payload.Type)
+	assert.Len(t, payload.Attachments, 1)
+	if len(payload.Attachments) == 0 {
+		return ""
+	}
+	assert.Equal(t, "application/vnd.microsoft.card.adaptive", payload.Attachments[0].ContentType)
+	assert.Equal(t, "AdaptiveCard", payload.Attachments[0].Content.Type)
+	assert.Equal(t, DefaultVersion, payload.Attachments[0].Content.Version)
+	return string(payload.Attachments[0].Content.Body)
+}
+
+func Test_Converter_Format(t *testing.T) {
+	actual := New().Format()
+	assert.Equal(t, "teams", actual)
+}
+
+func Test_Converter_Parse(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "headings",
+			input:    "# Heading 1\n\n#### Heading 4",
+			expected: `[{"type":"TextBlock","style":"heading","text":"Heading 1","wrap":true,"size":"ExtraLarge","weight":"Bolder"},{"type":"TextBlock","style":"heading","text":"Heading 4","wrap":true,"weight":"Bolder"}]`,
+		},
+		{
+			name:     "inline",
+			input:    "Some **bold** and _italic_ a & b, `code`, and [a link](https://example.com)\nover two lines",
+			expected: `[{"type":"TextBlock","text":"Some **bold** and _italic_ a & b, ` + "`code`" + `, and [a link](https://example.com) over two lines","wrap":true}]`,
+		},
+		{
+			name:     "image",
+			input:    "![The logo](https://example.com/logo.png)",
+			expected: `[{"type":"Image","url":"https://example.com/logo.png","altText":"The logo"}]`,
+		},
+		{
+			name:     "lists",
+			input:    "* one\n* two\n  1. first\n  2. second",
+			expected: `[{"type":"Container","items":[{"type":"TextBlock","text":"- one","wrap":true}]},{"type":"Container","items":[{"type":"TextBlock","text":"- two","wrap":true},{"type":"Container","items":[{"type":"TextBlock","text":"1. first","wrap":true}]},{"type":"Container","items":[{"type":"TextBlock","text":"2. second","wrap":true}]}]}]`,
+		},
+		{
+			name:     "ordered_list_start",
+			input:    "3. third\n4. fourth",
+			expected: `[{"type":"Container","items":[{"type":"TextBlock","text":"3. third","wrap":true}]},{"type":"Container","items":[{"type":"TextBlock","text":"4. fourth","wrap":true}]}]`,
+		},
+		{
+			name:     "list_code_block",
+			input:    "1. step:\n\n        code",
+			expected: `[{"type":"Container","items":[{"type":"TextBlock","text":"1. step:","wrap":true},{"type":"CodeBlock","codeSnippet":"code","language":"PlainText"}]}]`,
+		},
+		{
+			name:     "list_image",
+			input:    "- ![logo](logo.png)",
+			expected: `[{"type":"Container","items":[{"type":"TextBlock","text":"-","wrap":true},{"type":"Image","url":"logo.png","altText":"logo"}]}]`,
+		},
+		{
+			name:     "quote",
+			input:    "> quoted text",
+			expected: `[{"type":"Container","style":"emphasis","items":[{"type":"TextBlock","text":"quoted text","wrap":true}]}]`,
+		},
+		{
+			name:     "admonition",
+			input:    "> [!WARNING]\n> Be careful",
+			expected: `[{"type":"Container","style":"warning","items":[{"type":"TextBlock","text":"Warning","wrap":true,"weight":"Bolder"},{"type":"TextBlock","text":"Be careful","wrap":true}]}]`,
+		},
+		{
+			name:     "code",
+			input:    "```go\nfunc main() {}\n```\n\n```brainfuck\n+++\n```",
+			expected: `[{"type":"CodeBlock","codeSnippet":"func main() {}","language":"Go"},{"type":"CodeBlock","codeSnippet":"+++","language":"PlainText"}]`,
+		},
+		{
+			name:     "diagram",
+			input:    "```mermaid\ngraph TD\n```",
+			expected: `[{"type":"TextBlock","text":"Mermaid diagram","wrap":true,"weight":"Bolder"},{"type":"CodeBlock","codeSnippet":"graph TD","language":"PlainText"}]`,
+		},
+		{
+			name:     "separator",
+			input:    "above\n\n---\n\nbelow",
+			expected: `[{"type":"TextBlock","text":"above","wrap":true},{"type":"TextBlock","separator":true,"text":"below","wrap":true}]`,
+		},
+		{
+			name:     "table_two_columns",
+			input:    "| Name | Value |\n| --- | --- |\n| one | 1 |\n| two | 2 |",
+			expected: `[{"type":"FactSet","facts":[{"title":"**Name**","value":"**Value**"},{"title":"one","value":"1"},{"title":"two","value":"2"}]}]`,
+		},
+		{
+			name:     "table_columns",
+			input:    "| Name | Value | Notes |\n| --- | --- | --- |\n| one | 1 | first |\n| two | 2 | second |",
+			expected: `[{"type":"FactSet","facts":[{"title":"Name","value":"one"},{"title":"Value","value":"1"},{"title":"Notes","value":"first"}]},{"type":"FactSet","separator":true,"facts":[{"title":"Name","value":"two"},{"title":"Value","value":"2"},{"title":"Notes","value":"second"}]}]`,
+		},
+		{
+			name:     "empty_quote",
+			input:    ">",
+			expected: `[{"type":"Container","style":"emphasis","items":[{"type":"TextBlock","text":" ","wrap":true}]}]`,
+		},
+		{
+			name:     "table_headers_only",
+			input:    "| Name | Value |\n| --- | --- |",
+			expected: `[{"type":"FactSet","facts":[{"title":"**Name**","value":"**Value**"}]}]`,
+		},
+		{
+			name:     "table_columns_headers_only",
+			input:    "| Name | Value | Notes |\n| --- | --- | --- |",
+			expected: `[{"type":"FactSet","facts":[{"title":"Name","value":""},{"title":"Value","value":""},{"title":"Notes","value":""}]}]`,
+		},
+		{
+			name:     "front_matter",
+			input:    "---\ntitle: Notes\n---\ntext",
+			expected: `[{"type":"TextBlock","text":"text","wrap":true}]`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := New().Parse([]byte(test.input))
+			assert.Nil(t, err)
+			assert.JSONEq(t, test.expected, cardBody(t, actual))
+		})
+	}
+}
+
+func Test_Converter_Parse_Card(t *testing.T) {
+	converter := New()
+	converter.Message = false
+	converter.Version = "1.4"
+
+	actual, err := converter.Parse([]byte("text"))
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"$schema":"http://adaptivecards.io/schemas/adaptive-card.json","type":"AdaptiveCard","version":"1.4","body":[{"type":"TextBlock","text":"text","wrap":true}]}`, string(actual))
+}
+
+func Test_Converter_Parse_Links(t *testing.T) {
+	converter := New()
+	converter.Links = &links.Rewriter{
+		BaseURL:        "https://example.com/docs/",
+		MarkdownToHTML: true,
+	}
+
+	actual, err := converter.Parse([]byte("[setup](./setup.md)"))
+	assert.Nil(t, err)
+	assert.JSONEq(t, `[{"type":"TextBlock","text":"[setup](https://example.com/docs/setup.html)","wrap":true}]`, cardBody(t, actual))
+
+	converter.Links.BaseURL = "invalid"
+	_, err = converter.Parse([]byte("[setup](./setup.md)"))
+	assert.ErrorIs(t, err, links.ErrInvalidBaseURL)
+}
@@ -0,0 +1,337 @@
+package telegram
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/evilmonkeyinc/markdownconverter"
+	"github.com/gomarkdown/markdown/ast"
+)
+
+const (
+	// spoilerMarker surrounds spoilers in the markdown, as in Telegram
+	spoilerMarker string = "||"
+
+	horizontalRule string = "——————"
+)
+
+var (
+	// markdownEscaper escapes the characters reserved by MarkdownV2 outside
+	// of entities
+	markdownEscaper *strings.Replacer = strings.NewReplacer(
+		`\`, `\\`, `_`, `\_`, `*`, `\*`, `[`, `\[`, `]`, `\]`, `(`, `\(`, `)`, `\)`,
+		`~`, `\~`, "`", "\\`", `>`, `\>`, `#`, `\#`, `+`, `\+`, `-`, `\-`, `=`, `\=`,
+		`|`, `\|`, `{`, `\{`, `}`, `\}`, `.`, `\.`, `!`, `\!`,
+	)
+	// codeEscaper escapes the characters reserved by MarkdownV2 inside code
+	// and pre entities
+	codeEscaper *strings.Replacer = strings.NewReplacer(`\`, `\\`, "`", "\\`")
+	// urlEscaper escapes the characters reserved by MarkdownV2 inside the
+	// destination of a link
+	urlEscaper *strings.Replacer = strings.NewReplacer(`\`, `\\`, `)`, `\)`)
+	// htmlEscaper escapes the characters reserved by the HTML parse mode
+	htmlEscaper *strings.Replacer = strings.NewReplacer(`&`, `&amp;`, `<`, `&lt;`, `>`, `&gt;`)
+	// attributeEscaper escapes the characters reserved by the HTML parse mode
+	// inside attribute values
+	attributeEscaper *strings.Replacer = strings.NewReplacer(`&`, `&amp;`, `<`, `&lt;`, `>`, `&gt;`, `"`, `&quot;`)
+)
+
+type renderer struct {
+	html bool
+	// quoted is true inside a block quote, as Telegram does not support
+	// nested quotes
+	quoted bool
+	// spoilers is the number of spoiler markers left in the current block
+	// that have a matching marker
+	spoilers int
+	// spoiler is true inside a spoiler
+	spoiler bool
+	// heading is true inside a heading, which is already bold
+	heading bool
+}
+
+// blocks renders each block node joined by the separator
+func (renderer *renderer) blocks(nodes []ast.Node, separator string) string {
+	parts := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		if text := renderer.block(node); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, separator)
+}
+
+func (renderer *renderer) block(node ast.Node) string {
+	switch node := node.(type) {
+	case *ast.Heading:
+		renderer.heading = true
+		text := renderer.text(node)
+		renderer.heading = false
+		return renderer.entity(text, "*", "b")
+	case *ast.Paragraph:
+		return renderer.text(node)
+	case *ast.BlockQuote:
+		return renderer.quote(func() string {
+			return renderer.blocks(node.Children, "\n\n")
+		})
+	case *markdownconverter.Admonition:
+		return renderer.quote(func() string {
+			content := node.Emoji() + " " + renderer.entity(renderer.escape(node.Label()), "*", "b")
+			if children := renderer.blocks(node.Children, "\n\n"); children != "" {
+				content += "\n" + children
+			}
+			return content
+		})
+	case *ast.CodeBlock:
+		language := ""
+		if fields := strings.Fields(string(node.Info)); len(fields) > 0 {
+			language = fields[0]
+		}
+		code := renderer.pre(language, strings.TrimRight(string(node.Literal), "\n"))
+		if diagramType := markdownconverter.DiagramType(node.Info); diagramType != "" {
+			label := renderer.escape(markdownconverter.DiagramLabel(diagramType) + " diagram")
+			return renderer.entity(label, "*", "b") + "\n" + code
+		}
+		return code
+	case *ast.MathBlock:
+		return renderer.pre("", strings.TrimSpace(string(node.Literal)))
+	case *ast.List:
+		return renderer.list(node)
+	case *ast.HorizontalRule:
+		return horizontalRule
+	case *ast.Table:
+		return renderer.table(node)
+	case *ast.HTMLBlock:
+		return ""
+	default:
+		if container := node.AsContainer(); container != nil {
+			return renderer.blocks(container.Children, "\n\n")
+		}
+		return renderer.escape(strings.TrimSpace(string(node.AsLeaf().Literal)))
+	}
+}
+
+// quote renders the content as a block quote, or as it is if already inside
+// a block quote
+func (renderer *renderer) quote(content func() string) string {
+	if renderer.quoted {
+		return content()
+	}
+	renderer.quoted = true
+	text := content()
+	renderer.quoted = false
+
+	if renderer.html {
+		return "<blockquote>" + text + "</blockquote>"
+	}
+	lines := strings.Split(text, "\n")
+	for index, line := range lines {
+		lines[index] = ">" + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (renderer *renderer) list(node *ast.List) string {
+	start := node.Start
+	if start == 0 {
+		start = 1
+	}
+
+	items := make([]string, 0, len(node.Children))
+	for index, child := range node.Children {
+		marker := "• "
+		if node.ListFlags&ast.ListTypeOrdered != 0 {
+			marker = renderer.escape(fmt.Sprintf("%d. ", index+start))
+		}
+		content := renderer.blocks(child.GetChildren(), "\n")
+		lines := strings.Split(content, "\n")
+		for line := range lines {
+			if line == 0 {
+				lines[line] = marker + lines[line]
+			} else if lines[line] != "" {
+				lines[line] = "  " + lines[line]
+			}
+		}
+		items = append(items, strings.Join(lines, "\n"))
+	}
+	return strings.Join(items, "\n")
+}
+
+// table renders the table as aligned columns in a pre entity
+func (renderer *renderer) table(node *ast.Table) string {
+	rows := make([][]string, 0)
+	ast.WalkFunc(node, func(child ast.Node, entering bool) ast.WalkStatus {
+		row, ok := child.(*ast.TableRow)
+		if !ok || !entering {
+			return ast.GoToNext
+		}
+		cells := make([]string, 0, len(row.Children))
+		for _, cell := range row.Children {
+			cells = append(cells, markdownconverter.PlainText(cell))
+		}
+		rows = append(rows, cells)
+		return ast.SkipChildren
+	})
+	return renderer.pre("", strings.TrimRight(markdownconverter.AlignColumns(rows, 2), "\n"))
+}
+
+// text renders the inline children of a block node, first counting the
+// spoiler markers so one without a match is shown as it is
+func (renderer *renderer) text(node ast.Node) string {
+	markers := 0
+	ast.WalkFunc(node, func(child ast.Node, entering bool) ast.WalkStatus {
+		if text, ok := child.(*ast.Text); ok && entering {
+			markers += strings.Count(string(text.Literal), spoilerMarker)
+		}
+		return ast.GoToNext
+	})
+	renderer.spoilers = markers - markers%2
+	renderer.spoiler = false
+	return strings.TrimSpace(renderer.inline(node))
+}
+
+// inline renders the inline children of the node
+func (renderer *renderer) inline(node ast.Node) string {
+	text := ""
+	for _, child := range node.GetChildren() {
+		text = renderer.join(text, renderer.inlineNode(child))
+	}
+	return text
+}
+
+func (renderer *renderer) inlineNode(node ast.Node) string {
+	switch node := node.(type) {
+	case *ast.Text:
+		return renderer.plain(string(node.Literal))
+	case *ast.Code:
+		return renderer.code(string(node.Literal))
+	case *ast.Math:
+		return renderer.code(strings.TrimSpace(string(node.Literal)))
+	case *ast.Emph:
+		return renderer.entity(renderer.inline(node), "_", "i")
+	case *ast.Strong:
+		if renderer.heading {
+			return renderer.inline(node)
+		}
+		return renderer.entity(renderer.inline(node), "*", "b")
+	case *ast.Del:
+		return renderer.entity(renderer.inline(node), "~", "s")
+	case *ast.Hardbreak:
+		return "\n"
+	case *ast.Link:
+		text := renderer.inline(node)
+		if text == "" {
+			text = renderer.escape(string(node.Destination))
+		}
+		return renderer.link(text, string(node.Destination))
+	case *ast.Image:
+		text := renderer.escape(markdownconverter.PlainText(node))
+		if text == "" {
+			text = renderer.escape(string(node.Destination))
+		}
+		return renderer.link(text, string(node.Destination))
+	case *ast.HTMLSpan:
+		opening, ok := markdownconverter.UnderlineTag(node)
+		switch {
+		case !ok:
+			return ""
+		case !renderer.html:
+			return "__"
+		case opening:
+			return "<u>"
+		default:
+			return "</u>"
+		}
+	default:
+		if node.AsContainer() != nil {
+			return renderer.inline(node)
+		}
+		return renderer.escape(string(node.AsLeaf().Literal))
+	}
+}
+
+// plain renders text, starting or ending a spoiler at each matched marker
+func (renderer *renderer) plain(text string) string {
+	parts := strings.Split(text, spoilerMarker)
+	builder := &strings.Builder{}
+	for index, part := range parts {
+		if index > 0 {
+			if renderer.spoilers > 0 {
+				renderer.spoilers--
+				builder.WriteString(renderer.spoilerMarker())
+			} else {
+				builder.WriteString(renderer.escape(spoilerMarker))
+			}
+		}
+		builder.WriteString(renderer.escape(part))
+	}
+	return builder.String()
+}
+
+// spoilerMarker returns the markup that starts or ends a spoiler
+func (renderer *renderer) spoilerMarker() string {
+	renderer.spoiler = !renderer.spoiler
+	switch {
+	case !renderer.html:
+		return spoilerMarker
+	case renderer.spoiler:
+		return "<tg-spoiler>"
+	default:
+		return "</tg-spoiler>"
+	}
+}
+
+// entity wraps the content in the MarkdownV2 marker or the HTML tag
+func (renderer *renderer) entity(content, marker, tag string) string {
+	if renderer.html {
+		return "<" + tag + ">" + content + "</" + tag + ">"
+	}
+	return renderer.join(renderer.join(marker, content), marker)
+}
+
+// join joins the rendered text, separating an italic marker from an underline
+// marker next to it with \r in MarkdownV2, as Telegram reads ___ as an
+// underline marker followed by an italic marker
+func (renderer *renderer) join(left, right string) string {
+	if !renderer.html && strings.HasSuffix(left, "_") && strings.HasPrefix(right, "_") &&
+		!escaped([]rune(left[:len(left)-1])) && (strings.HasSuffix(left, "__") || strings.HasPrefix(right, "__")) {
+		return left + "\r" + right
+	}
+	return left + right
+}
+
+// code renders inline code
+func (renderer *renderer) code(code string) string {
+	if renderer.html {
+		return "<code>" + htmlEscaper.Replace(code) + "</code>"
+	}
+	return "`" + codeEscaper.Replace(code) + "`"
+}
+
+// pre renders a block of code, with the language for syntax highlighting if
+// it is not empty
+func (renderer *renderer) pre(language, code string) string {
+	if !renderer.html {
+		return "```" + codeEscaper.Replace(language) + "\n" + codeEscaper.Replace(code) + "\n```"
+	}
+	if language == "" {
+		return "<pre>" + htmlEscaper.Replace(code) + "</pre>"
+	}
+	return `<pre><code class="language-` + attributeEscaper.Replace(language) + `">` + htmlEscaper.Replace(code) + "</code></pre>"
+}
+
+// link renders a link to the destination with the already escaped text
+func (renderer *renderer) link(text, destination string) string {
+	if renderer.html {
+		return `<a href="` + attributeEscaper.Replace(destination) + `">` + text + "</a>"
+	}
+	return "[" + text + "](" + urlEscaper.Replace(destination) + ")"
+}
+
+// escape escapes text outside of entities
+func (renderer *renderer) escape(text string) string {
+	if renderer.html {
+		return htmlEscaper.Replace(text)
+	}
+	return markdownEscaper.Replace(text)
+}
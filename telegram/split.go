@@ -0,0 +1,343 @@
+package telegram
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// htmlEntity matches the HTML tags of the pre and block quote entities, or of
+// an inline entity with the tag name as the third submatch
+var htmlEntity *regexp.Regexp = regexp.MustCompile(`</?(pre|blockquote)>(<code[^>]*>)?|</code></pre>|</?(b|i|u|s|tg-spoiler|code|a)(\s[^>]*)?>`)
+
+// Split breaks the converted text into messages of no more than limit
+// characters, splitting between lines where possible. Entities that are
+// split, such as pre entities or bold text in a long paragraph, are closed at
+// the end of one message and opened again at the start of the next. A limit
+// of zero or less returns the text as it is, and ErrLimitTooSmall is returned
+// if the limit leaves no room for the markup of the entities.
+func Split(text string, limit int, mode string) ([]string, error) {
+	if text == "" {
+		return nil, nil
+	}
+	if limit <= 0 || utf8.RuneCountInString(text) <= limit {
+		return []string{text}, nil
+	}
+
+	splitter := &splitter{limit: limit, html: mode == ModeHTML}
+	for _, line := range strings.Split(text, "\n") {
+		splitter.add(line)
+	}
+	splitter.flush()
+	if splitter.tooSmall {
+		return nil, fmt.Errorf("%w '%d'", ErrLimitTooSmall, limit)
+	}
+	return splitter.messages, nil
+}
+
+// entity is the markup that opens and closes an entity
+type entity struct {
+	opening string
+	closing string
+	// pre is true for pre entities, which have no entities inside them
+	pre bool
+}
+
+type splitter struct {
+	limit    int
+	html     bool
+	messages []string
+	// lines are the lines of the current message
+	lines []string
+	// size is the number of characters in the current message, including
+	// the reopened entities
+	size int
+	// open are the entities the current line is in
+	open []entity
+	// tooSmall is true if a message is over the limit, as the markup of its
+	// entities leaves no room for the text
+	tooSmall bool
+}
+
+// add appends the line to the current message, starting a new message when
+// it would exceed the limit
+func (splitter *splitter) add(line string) {
+	if splitter.tooSmall {
+		return
+	}
+	after := splitter.entities(line)
+
+	// leave room to close the entities if the message ends inside them
+	for splitter.size+splitter.separator()+utf8.RuneCountInString(line+closing(after)) > splitter.limit {
+		if len(splitter.lines) > 0 {
+			splitter.flush()
+			continue
+		}
+		piece, rest := splitter.cut(line)
+		splitter.append(piece)
+		splitter.open = splitter.entities(piece)
+		splitter.flush()
+		if splitter.tooSmall {
+			return
+		}
+		line = rest
+		after = splitter.entities(line)
+	}
+	if line == "" && len(splitter.lines) == 0 && len(splitter.open) == 0 {
+		// blank lines are not needed at the start of a message
+		return
+	}
+	splitter.append(line)
+	splitter.open = after
+}
+
+// entities returns the entities open after the line
+func (splitter *splitter) entities(line string) []entity {
+	open := append([]entity{}, splitter.open...)
+	if splitter.html {
+		return htmlEntities(open, line)
+	}
+	switch {
+	case !inPre(open) && strings.HasPrefix(line, "```"):
+		return append(open, entity{opening: line + "\n", closing: "\n```", pre: true})
+	case inPre(open) && line == "```":
+		return open[:len(open)-1]
+	case inPre(open):
+		return open
+	}
+	return markdownEntities(open, line)
+}
+
+// htmlEntities returns the entities open after the line in the HTML mode
+func htmlEntities(open []entity, line string) []entity {
+	for _, match := range htmlEntity.FindAllStringSubmatch(line, -1) {
+		tag, name := match[0], match[3]
+		switch {
+		case strings.HasPrefix(tag, "</"):
+			open = closeEntity(open, tag)
+		case name != "":
+			open = append(open, entity{opening: tag, closing: "</" + name + ">"})
+		case strings.HasPrefix(tag, "<pre>"):
+			closing := "</pre>"
+			if strings.Contains(tag, "<code") {
+				closing = "</code></pre>"
+			}
+			open = append(open, entity{opening: tag, closing: closing, pre: true})
+		default:
+			open = append(open, entity{opening: tag, closing: "</blockquote>"})
+		}
+	}
+	return open
+}
+
+// markdownEntities returns the inline entities open after the line in the
+// MarkdownV2 mode, skipping escaped characters and link destinations
+func markdownEntities(open []entity, line string) []entity {
+	runes := []rune(line)
+	for index := 0; index < len(runes); index++ {
+		code := len(open) > 0 && open[len(open)-1].opening == "`"
+		marker := string(runes[index])
+		switch runes[index] {
+		case '\\':
+			index++
+			continue
+		case '`':
+		case '*', '~':
+			if code {
+				continue
+			}
+		case '_', '|':
+			if code {
+				continue
+			}
+			if index+1 < len(runes) && runes[index+1] == runes[index] {
+				marker += marker
+				index++
+			} else if runes[index] == '|' {
+				continue
+			}
+		case ']':
+			if !code && index+1 < len(runes) && runes[index+1] == '(' {
+				// the destination only escapes closing parentheses
+				for index++; index < len(runes) && runes[index] != ')'; index++ {
+					if runes[index] == '\\' {
+						index++
+					}
+				}
+			}
+			continue
+		default:
+			continue
+		}
+
+		if closed := closeEntity(open, marker); len(closed) < len(open) {
+			open = closed
+			continue
+		}
+		open = append(open, entity{opening: marker, closing: marker})
+	}
+	return open
+}
+
+// closeEntity removes the last open entity with the closing markup
+func closeEntity(open []entity, markup string) []entity {
+	for index := len(open) - 1; index >= 0; index-- {
+		if open[index].closing == markup {
+			return append(open[:index:index], open[index+1:]...)
+		}
+	}
+	return open
+}
+
+// inPre returns true if the last open entity is a pre entity
+func inPre(open []entity) bool {
+	return len(open) > 0 && open[len(open)-1].pre
+}
+
+// cut returns as much of the start of the line as fits in the current
+// message with the entities open at the end of it closed, and the rest
+func (splitter *splitter) cut(line string) (string, string) {
+	// the rest of a quoted line starts with the > again, so the piece needs
+	// more than the > for the line to get shorter
+	quoted := !splitter.html && strings.HasPrefix(line, ">")
+	minimum := 1
+	if quoted {
+		minimum = 2
+	}
+
+	for size := splitter.limit - splitter.size; ; size-- {
+		if size < minimum {
+			size = minimum
+		}
+		piece, rest := cut(line, size, minimum, !inPre(splitter.open), splitter.html)
+		if size == minimum || splitter.size+utf8.RuneCountInString(piece+closing(splitter.entities(piece))) <= splitter.limit {
+			if quoted && rest != "" {
+				// keep the rest of the line in the block quote
+				rest = ">" + rest
+			}
+			return piece, rest
+		}
+	}
+}
+
+// append adds the line to the current message, after the markup reopening
+// the entities if it is the first line
+func (splitter *splitter) append(line string) {
+	splitter.size += splitter.separator() + utf8.RuneCountInString(line)
+	if len(splitter.lines) == 0 {
+		if !splitter.html && !inPre(splitter.open) && strings.HasPrefix(line, ">") {
+			// reopen the entities inside the block quote
+			line = ">" + opening(splitter.open) + line[1:]
+		} else {
+			line = opening(splitter.open) + line
+		}
+	}
+	splitter.lines = append(splitter.lines, line)
+}
+
+// separator returns the number of characters needed before the next line
+func (splitter *splitter) separator() int {
+	if len(splitter.lines) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// flush finishes the current message, closing any open entities so they can
+// be opened again in the next message
+func (splitter *splitter) flush() {
+	if len(splitter.lines) == 0 {
+		return
+	}
+
+	message := strings.Join(splitter.lines, "\n")
+	if len(splitter.open) == 0 {
+		message = strings.TrimRight(message, "\n")
+	}
+	message += closing(splitter.open)
+	if utf8.RuneCountInString(message) > splitter.limit {
+		splitter.tooSmall = true
+	}
+	splitter.messages = append(splitter.messages, message)
+
+	splitter.lines = nil
+	splitter.size = utf8.RuneCountInString(opening(splitter.open))
+}
+
+// opening returns the markup that opens the entities
+func opening(entities []entity) string {
+	builder := &strings.Builder{}
+	for _, entity := range entities {
+		builder.WriteString(entity.opening)
+	}
+	return builder.String()
+}
+
+// closing returns the markup that closes the entities
+func closing(entities []entity) string {
+	builder := &strings.Builder{}
+	for index := len(entities) - 1; index >= 0; index-- {
+		builder.WriteString(entities[index].closing)
+	}
+	return builder.String()
+}
+
+// cut splits the line after at most size characters, and at least minimum
+// characters, at the last space before then if words is true, never inside an
+// HTML tag or character reference if html is true, and otherwise never
+// between an escape and the escaped character or inside a two character marker
+func cut(line string, size, minimum int, words, html bool) (string, string) {
+	runes := []rune(line)
+	if len(runes) <= size {
+		return line, ""
+	}
+	if html {
+		head := string(runes[:size])
+		for _, markup := range []string{"<>", "&;"} {
+			start := strings.LastIndexByte(head, markup[0])
+			if start < 0 || !inside(head, markup) {
+				continue
+			}
+			if start > 0 {
+				head = head[:start]
+			} else if end := strings.IndexByte(line, markup[1]); end >= 0 {
+				// a tag at the start of the line is kept whole
+				head = line[:end+1]
+			}
+		}
+		if size = utf8.RuneCountInString(head); size >= len(runes) {
+			return line, ""
+		}
+	}
+	if words {
+		for index := size; index > 0; index-- {
+			if runes[index] == ' ' && !(html && inside(string(runes[:index]), "<>")) {
+				return string(runes[:index]), string(runes[index+1:])
+			}
+		}
+	}
+	for !html && size > minimum && (escaped(runes[:size]) || runes[size-1] == runes[size] && strings.ContainsRune("_|", runes[size])) {
+		size--
+	}
+	if !html && size < len(runes) && escaped(runes[:size]) {
+		// keep the escaped character with its escape
+		size++
+	}
+	return string(runes[:size]), string(runes[size:])
+}
+
+// escaped returns true if the text ends with an escaping backslash
+func escaped(text []rune) bool {
+	count := 0
+	for index := len(text) - 1; index >= 0 && text[index] == '\\'; index-- {
+		count++
+	}
+	return count%2 == 1
+}
+
+// inside returns true if the end of the text is between the opening and
+// closing characters of the markup
+func inside(text, markup string) bool {
+	return strings.LastIndexByte(text, markup[0]) > strings.LastIndexByte(text, markup[1])
+}
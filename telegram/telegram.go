@@ -0,0 +1,87 @@
+// Package telegram converts markdown to the MarkdownV2 or HTML formatting
+// used by the Telegram Bot API
+package telegram
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/evilmonkeyinc/markdownconverter"
+	"github.com/evilmonkeyinc/markdownconverter/links"
+	"github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/parser"
+)
+
+const (
+	// DefaultMessageLimit is the maximum number of characters in a Telegram
+	// message
+	DefaultMessageLimit int = 4096
+
+	// ModeMarkdownV2 outputs the MarkdownV2 parse mode
+	ModeMarkdownV2 string = "MarkdownV2"
+	// ModeHTML outputs the HTML parse mode
+	ModeHTML string = "HTML"
+)
+
+var (
+	// ErrUnknownMode is returned if the converter mode is not supported
+	ErrUnknownMode error = fmt.Errorf("unknown mode")
+	// ErrLimitTooSmall is returned if the message limit is too small to split
+	// the output between the markup of its entities
+	ErrLimitTooSmall error = fmt.Errorf("message limit too small")
+)
+
+// New returns a new instance of Converter
+func New() *Converter {
+	return &Converter{
+		Mode:         ModeMarkdownV2,
+		MessageLimit: DefaultMessageLimit,
+	}
+}
+
+// Converter is the Telegram Converter implementation
+type Converter struct {
+	// Links is used to rewrite the destinations of links and images, leave nil
+	// to keep them as they are
+	Links *links.Rewriter
+	// Mode is the parse mode of the output, either ModeMarkdownV2 or ModeHTML,
+	// which must be sent as the parse_mode with the message
+	Mode string
+	// MessageLimit is the maximum number of characters in each message
+	// returned by Messages
+	MessageLimit int
+}
+
+// Format returns a unique name for the converter
+func (converter *Converter) Format() string {
+	return "telegram"
+}
+
+// Parse will parse the standard markdown and return the converted data
+func (converter *Converter) Parse(markdwn []byte) ([]byte, error) {
+	if converter.Mode != ModeMarkdownV2 && converter.Mode != ModeHTML {
+		return nil, fmt.Errorf("%w '%s'", ErrUnknownMode, converter.Mode)
+	}
+
+	_, body := markdownconverter.FrontMatter(markdwn)
+	document := markdown.Parse(markdown.NormalizeNewlines(body), parser.NewWithExtensions(parser.CommonExtensions|parser.OrderedListStart))
+	markdownconverter.Admonitions(document)
+	if converter.Links != nil {
+		if err := converter.Links.Rewrite(document); err != nil {
+			return nil, err
+		}
+	}
+
+	renderer := &renderer{html: converter.Mode == ModeHTML}
+	return []byte(strings.TrimSpace(renderer.blocks(document.GetChildren(), "\n\n"))), nil
+}
+
+// Messages converts the markdown and splits it into messages no longer than
+// the message limit, so it can be sent as a series of messages
+func (converter *Converter) Messages(markdwn []byte) ([]string, error) {
+	output, err := converter.Parse(markdwn)
+	if err != nil {
+		return nil, err
+	}
+	return Split(string(output), converter.MessageLimit, converter.Mode)
+}
@@ -0,0 +1,332 @@
+package telegram
+
+import (
+	"testing"
+
+	"github.com/evilmonkeyinc/markdownconverter/links"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Converter_Format(t *testing.T) {
+	actual := New().Format()
+	assert.Equal(t, "telegram", actual)
+}
+
+func Test_Converter_Parse(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		input    string
+		markdown string
+		html     string
+	}{
+		{
+			name:     "heading",
+			input:    "## Release 1.2 (beta)",
+			markdown: `*Release 1\.2 \(beta\)*`,
+			html:     "<b>Release 1.2 (beta)</b>",
+		},
+		{
+			name:     "heading_formatting",
+			input:    "# Title **bold** _italic_",
+			markdown: "*Title bold _italic_*",
+			html:     "<b>Title bold <i>italic</i></b>",
+		},
+		{
+			name:     "formatting",
+			input:    "**bold** _italic_ ~~strikethrough~~ <u>underline</u>",
+			markdown: "*bold* _italic_ ~strikethrough~ __underline__",
+			html:     "<b>bold</b> <i>italic</i> <s>strikethrough</s> <u>underline</u>",
+		},
+		{
+			name:     "italic_underline",
+			input:    "*<u>x</u>*",
+			markdown: "_\r__x__\r_",
+			html:     "<i><u>x</u></i>",
+		},
+		{
+			name:     "italic_next_to_underline",
+			input:    "_a_<u>b</u>",
+			markdown: "_a_\r__b__",
+			html:     "<i>a</i><u>b</u>",
+		},
+		{
+			name:     "escaping",
+			input:    `1+1=2! a-b #tag {x} [y] a|b > c & d \ e`,
+			markdown: `1\+1\=2\! a\-b \#tag \{x\} \[y\] a\|b \> c & d \\ e`,
+			html:     `1+1=2! a-b #tag {x} [y] a|b &gt; c &amp; d \ e`,
+		},
+		{
+			name:     "spoiler",
+			input:    "the ||secret *ending*|| is ||here",
+			markdown: `the ||secret _ending_|| is \|\|here`,
+			html:     "the <tg-spoiler>secret <i>ending</i></tg-spoiler> is ||here",
+		},
+		{
+			name:     "code",
+			input:    "run `a\\b` and $x_1$",
+			markdown: "run `a\\\\b` and `x_1`",
+			html:     `run <code>a\b</code> and <code>x_1</code>`,
+		},
+		{
+			name:     "code_block",
+			input:    "```go\nfmt.Println(\"`<a>`\")\n```",
+			markdown: "```go\nfmt.Println(\"\\`<a>\\`\")\n```",
+			html:     "<pre><code class=\"language-go\">fmt.Println(\"`&lt;a&gt;`\")</code></pre>",
+		},
+		{
+			name:     "link",
+			input:    "[docs (v1.2)](https://example.com/a_(b)?c=1&d=2)",
+			markdown: `[docs \(v1\.2\)](https://example.com/a_(b\)?c=1&d=2)`,
+			html:     `<a href="https://example.com/a_(b)?c=1&amp;d=2">docs (v1.2)</a>`,
+		},
+		{
+			name:     "image",
+			input:    "![logo](https://example.com/logo.png)",
+			markdown: "[logo](https://example.com/logo.png)",
+			html:     `<a href="https://example.com/logo.png">logo</a>`,
+		},
+		{
+			name:     "lists",
+			input:    "* one\n* two\n  1. first\n  2. second",
+			markdown: "• one\n• two\n  1\\. first\n  2\\. second",
+			html:     "• one\n• two\n  1. first\n  2. second",
+		},
+		{
+			name:     "ordered_list_start",
+			input:    "3. third\n4. fourth",
+			markdown: "3\\. third\n4\\. fourth",
+			html:     "3. third\n4. fourth",
+		},
+		{
+			name:     "quote",
+			input:    "> quoted\n> lines.",
+			markdown: ">quoted\n>lines\\.",
+			html:     "<blockquote>quoted\nlines.</blockquote>",
+		},
+		{
+			name:     "admonition",
+			input:    "> [!TIP]\n> Try this",
+			markdown: ">💡 *Tip*\n>Try this",
+			html:     "<blockquote>💡 <b>Tip</b>\nTry this</blockquote>",
+		},
+		{
+			name:     "diagram",
+			input:    "```mermaid\ngraph TD\n```",
+			markdown: "*Mermaid diagram*\n```mermaid\ngraph TD\n```",
+			html:     "<b>Mermaid diagram</b>\n<pre><code class=\"language-mermaid\">graph TD</code></pre>",
+		},
+		{
+			name:     "table",
+			input:    "| Name | Value |\n| --- | --- |\n| a<b | 1 |",
+			markdown: "```\nName  Value\na<b   1\n```",
+			html:     "<pre>Name  Value\na&lt;b   1</pre>",
+		},
+		{
+			name:     "table_wide_characters",
+			input:    "| 名前 | Value |\n| --- | --- |\n| name | 1 |",
+			markdown: "```\n名前  Value\nname  1\n```",
+			html:     "<pre>名前  Value\nname  1</pre>",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			converter := New()
+			actual, err := converter.Parse([]byte(test.input))
+			assert.Nil(t, err)
+			assert.Equal(t, test.markdown, string(actual))
+
+			converter.Mode = ModeHTML
+			actual, err = converter.Parse([]byte(test.input))
+			assert.Nil(t, err)
+			assert.Equal(t, test.html, string(actual))
+		})
+	}
+}
+
+func Test_Converter_Parse_UnknownMode(t *testing.T) {
+	converter := New()
+	converter.Mode = "Markdown"
+	_, err := converter.Parse([]byte("text"))
+	assert.ErrorIs(t, err, ErrUnknownMode)
+}
+
+func Test_Converter_Parse_Links(t *testing.T) {
+	converter := New()
+	converter.Links = &links.Rewriter{
+		BaseURL:        "https://example.com/docs/",
+		MarkdownToHTML: true,
+	}
+
+	actual, err := converter.Parse([]byte("[setup](./setup.md)"))
+	assert.Nil(t, err)
+	assert.Equal(t, "[setup](https://example.com/docs/setup.html)", string(actual))
+
+	converter.Links.BaseURL = "invalid"
+	_, err = converter.Parse([]byte("[setup](./setup.md)"))
+	assert.ErrorIs(t, err, links.ErrInvalidBaseURL)
+}
+
+func Test_Converter_Messages(t *testing.T) {
+	converter := New()
+	converter.MessageLimit = 20
+
+	actual, err := converter.Messages([]byte("# Title\n\nfirst paragraph\n\nsecond paragraph"))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"*Title*", "first paragraph", "second paragraph"}, actual)
+
+	converter.Mode = "Markdown"
+	_, err = converter.Messages([]byte("text"))
+	assert.ErrorIs(t, err, ErrUnknownMode)
+}
+
+func Test_Split(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		text     string
+		limit    int
+		mode     string
+		expected []string
+		err      error
+	}{
+		{
+			name:     "empty",
+			text:     "",
+			limit:    10,
+			mode:     ModeMarkdownV2,
+			expected: nil,
+		},
+		{
+			name:     "under_limit",
+			text:     "short\ntext",
+			limit:    10,
+			mode:     ModeMarkdownV2,
+			expected: []string{"short\ntext"},
+		},
+		{
+			name:     "lines",
+			text:     "first line\nsecond line\n\nthird line",
+			limit:    22,
+			mode:     ModeMarkdownV2,
+			expected: []string{"first line\nsecond line", "third line"},
+		},
+		{
+			name:     "long_line",
+			text:     "a line that is much too long",
+			limit:    12,
+			mode:     ModeMarkdownV2,
+			expected: []string{"a line that", "is much too", "long"},
+		},
+		{
+			name:     "pre",
+			text:     "intro\n```go\nline one\nline two\nline three\n```\nafter",
+			limit:    30,
+			mode:     ModeMarkdownV2,
+			expected: []string{"intro\n```go\nline one\n```", "```go\nline two\nline three\n```", "after"},
+		},
+		{
+			name:     "html_pre",
+			text:     "intro\n<pre><code class=\"language-go\">line one\nline two\nline three</code></pre>\nafter",
+			limit:    65,
+			mode:     ModeHTML,
+			expected: []string{"intro\n<pre><code class=\"language-go\">line one</code></pre>", "<pre><code class=\"language-go\">line two\nline three</code></pre>", "after"},
+		},
+		{
+			name:     "html_quote",
+			text:     "<blockquote>first line\nsecond line\nthird line</blockquote>",
+			limit:    50,
+			mode:     ModeHTML,
+			expected: []string{"<blockquote>first line\nsecond line</blockquote>", "<blockquote>third line</blockquote>"},
+		},
+		{
+			name:     "html_long_line",
+			text:     "<b>bold words</b> and &amp; more",
+			limit:    16,
+			mode:     ModeHTML,
+			expected: []string{"<b>bold</b>", "<b>words</b> and", "&amp; more"},
+		},
+		{
+			name:     "html_long_line_nested",
+			text:     `<a href="https://example.com"><i>link text</i></a> after`,
+			limit:    45,
+			mode:     ModeHTML,
+			expected: []string{`<a href="https://example.com"><i>link</i></a>`, `<a href="https://example.com"><i>text</i></a>`, "after"},
+		},
+		{
+			name:     "long_line_entities",
+			text:     `*bold _and italic_ words* then \*not bold\*`,
+			limit:    12,
+			mode:     ModeMarkdownV2,
+			expected: []string{"*bold _and_*", "*_italic_*", "*words* then", `\*not bold\*`},
+		},
+		{
+			name:     "long_line_underline",
+			text:     "__underlined words__ and ||hidden spoiler||",
+			limit:    16,
+			mode:     ModeMarkdownV2,
+			expected: []string{"__underlined__", "__words__ and", "||hidden||", "||spoiler||"},
+		},
+		{
+			name:     "long_line_link",
+			text:     "[a\\_b](https://example.com/a_b) *bold text*",
+			limit:    38,
+			mode:     ModeMarkdownV2,
+			expected: []string{"[a\\_b](https://example.com/a_b) *bold*", "*text*"},
+		},
+		{
+			name:     "long_line_quote",
+			text:     ">quoted *bold words*",
+			limit:    14,
+			mode:     ModeMarkdownV2,
+			expected: []string{">quoted *bold*", ">*words*"},
+		},
+		{
+			name:     "small_limit_quote",
+			text:     ">quoted *bold words* and more",
+			limit:    11,
+			mode:     ModeMarkdownV2,
+			expected: []string{">quoted", ">*bold*", ">*words*", ">and more"},
+		},
+		{
+			name:     "small_limit_quote_escape",
+			text:     `>\_escaped words`,
+			limit:    3,
+			mode:     ModeMarkdownV2,
+			expected: []string{`>\_`, ">es", ">ca", ">pe", ">d", ">wo", ">rd", ">s"},
+		},
+		{
+			name:  "small_limit_entities",
+			text:  "*bold _italic words_ here*",
+			limit: 4,
+			mode:  ModeMarkdownV2,
+			err:   ErrLimitTooSmall,
+		},
+		{
+			name:     "html_small_limit",
+			text:     `<a href="https://example.com"><i>link text</i></a> after`,
+			limit:    44,
+			mode:     ModeHTML,
+			expected: []string{`<a href="https://example.com"><i>lin</i></a>`, `<a href="https://example.com"><i>k</i></a>`, `<a href="https://example.com"><i>tex</i></a>`, `<a href="https://example.com"><i>t</i></a>`, "after"},
+		},
+		{
+			name:  "html_limit_too_small",
+			text:  "<blockquote><b>bold words here</b></blockquote>",
+			limit: 30,
+			mode:  ModeHTML,
+			err:   ErrLimitTooSmall,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := Split(test.text, test.limit, test.mode)
+			assert.ErrorIs(t, err, test.err)
+			assert.Equal(t, test.expected, actual)
+			for _, message := range actual {
+				assert.LessOrEqual(t, len([]rune(message)), test.limit, message)
+			}
+		})
+	}
+}
@@ -0,0 +1,589 @@
+package terminal
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/evilmonkeyinc/markdownconverter"
+	"github.com/gomarkdown/markdown/ast"
+)
+
+// the SGR parameters of each style
+const (
+	boldStyle   string = "1"
+	faintStyle  string = "2"
+	italicStyle string = "3"
+	strikeStyle string = "9"
+	codeStyle   string = "33"
+	linkStyle   string = "4;34"
+	quoteStyle  string = "90"
+
+	underlineStyle string = "4"
+)
+
+// headingStyles are the styles of each heading level, deeper headings use the
+// last style
+var headingStyles []string = []string{"1;4;35", "1;36", "1;34", "1"}
+
+// admonitionStyles are the colors of each admonition type
+var admonitionStyles map[string]string = map[string]string{
+	"caution":   "31",
+	"important": "35",
+	"note":      "34",
+	"tip":       "32",
+	"warning":   "33",
+}
+
+// bullets are the markers of unordered lists at each depth
+var bullets []string = []string{"•", "◦", "▪"}
+
+// span is inline text with its style and the URL it links to
+type span struct {
+	text  string
+	style string
+	link  string
+}
+
+// token is a word made of one or more spans, or a hard line break
+type token struct {
+	spans   []span
+	width   int
+	newline bool
+	// attached is true if there is no space between the token and the one
+	// before, where a word is broken beside a wide character
+	attached bool
+}
+
+type renderer struct {
+	// color will style the output with ANSI escape codes
+	color bool
+	// hyperlinks will make links clickable with OSC 8 escape sequences
+	hyperlinks bool
+	// depth is the number of unordered lists the current node is in
+	depth int
+	// underline is the number of open underline tags
+	underline int
+}
+
+// blocks renders each block node at the width separated by a blank line
+func (renderer *renderer) blocks(nodes []ast.Node, width int) string {
+	parts := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		if text := renderer.block(node, width); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func (renderer *renderer) block(node ast.Node, width int) string {
+	switch node := node.(type) {
+	case *ast.Heading:
+		style := headingStyles[len(headingStyles)-1]
+		if node.Level <= len(headingStyles) {
+			style = headingStyles[node.Level-1]
+		}
+		lines, widest := renderer.wrap(renderer.inline(node, style), width)
+		if !renderer.color && node.Level <= 2 {
+			// without styles the most important headings are underlined
+			underline := "-"
+			if node.Level == 1 {
+				underline = "="
+			}
+			return lines + "\n" + strings.Repeat(underline, widest)
+		}
+		return lines
+	case *ast.Paragraph:
+		lines, _ := renderer.wrap(renderer.inline(node, ""), width)
+		return lines
+	case *ast.BlockQuote:
+		return quoteLines(renderer.blocks(node.Children, markdownconverter.Narrower(width, 2)), renderer.sgr(quoteStyle, "│"))
+	case *markdownconverter.Admonition:
+		style := admonitionStyles[node.Type]
+		content := renderer.sgr(style+";"+boldStyle, node.Label())
+		if children := renderer.blocks(node.Children, markdownconverter.Narrower(width, 2)); children != "" {
+			content += "\n" + children
+		}
+		return quoteLines(content, renderer.sgr(style, "│"))
+	case *ast.CodeBlock:
+		label := ""
+		if diagramType := markdownconverter.DiagramType(node.Info); diagramType != "" {
+			label = markdownconverter.DiagramLabel(diagramType) + " diagram"
+		} else if fields := strings.Fields(string(node.Info)); len(fields) > 0 {
+			label = clean(fields[0])
+		}
+		return renderer.box(label, strings.TrimRight(string(node.Literal), "\n"), width)
+	case *ast.MathBlock:
+		return renderer.box("math", strings.TrimSpace(string(node.Literal)), width)
+	case *ast.List:
+		if node.ListFlags&ast.ListTypeDefinition != 0 {
+			return renderer.definitionList(node, width)
+		}
+		return renderer.list(node, width)
+	case *ast.HorizontalRule:
+		return renderer.sgr(faintStyle, strings.Repeat("─", width))
+	case *ast.Table:
+		return renderer.table(node)
+	case *ast.HTMLBlock:
+		return ""
+	default:
+		if container := node.AsContainer(); container != nil {
+			return renderer.blocks(container.Children, width)
+		}
+		lines, _ := renderer.wrap([]span{{text: clean(strings.TrimSpace(string(node.AsLeaf().Literal)))}}, width)
+		return lines
+	}
+}
+
+// list renders the list items with their content wrapped to line up with the
+// text after the marker
+func (renderer *renderer) list(node *ast.List, width int) string {
+	ordered := node.ListFlags&ast.ListTypeOrdered != 0
+	if !ordered {
+		renderer.depth++
+		defer func() { renderer.depth-- }()
+	}
+
+	separator := "\n"
+	if !node.Tight {
+		separator = "\n\n"
+	}
+
+	start := node.Start
+	if start == 0 {
+		start = 1
+	}
+
+	items := make([]string, 0, len(node.Children))
+	for index, child := range node.Children {
+		marker := bullets[(renderer.depth-1+len(bullets))%len(bullets)] + " "
+		if ordered {
+			marker = fmt.Sprintf("%d. ", index+start)
+		}
+		markerWidth := markdownconverter.DisplayWidth(marker)
+
+		parts := make([]string, 0)
+		for _, grandchild := range child.GetChildren() {
+			if text := renderer.block(grandchild, markdownconverter.Narrower(width, markerWidth)); text != "" {
+				parts = append(parts, text)
+			}
+		}
+
+		lines := strings.Split(strings.Join(parts, separator), "\n")
+		for line := range lines {
+			switch {
+			case line == 0:
+				lines[line] = marker + lines[line]
+			case lines[line] != "":
+				lines[line] = strings.Repeat(" ", markerWidth) + lines[line]
+			}
+		}
+		items = append(items, strings.Join(lines, "\n"))
+	}
+	return strings.Join(items, separator)
+}
+
+// definitionList renders each term in bold with its definitions indented below
+func (renderer *renderer) definitionList(node *ast.List, width int) string {
+	lines := make([]string, 0, len(node.Children))
+	for _, child := range node.Children {
+		item, ok := child.(*ast.ListItem)
+		if !ok {
+			continue
+		}
+		if item.ListFlags&ast.ListTypeTerm != 0 {
+			if len(lines) > 0 && !node.Tight {
+				lines = append(lines, "")
+			}
+			term, _ := renderer.wrap(renderer.inline(item, boldStyle), width)
+			lines = append(lines, term)
+			continue
+		}
+		if text := renderer.blocks(item.Children, markdownconverter.Narrower(width, 2)); text != "" {
+			lines = append(lines, indent(text, "  "))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// box draws a border around the content, with the label in the top border,
+// breaking lines that are too wide to fit
+func (renderer *renderer) box(label, content string, width int) string {
+	lines := strings.Split(strings.ReplaceAll(clean(content), "\t", "    "), "\n")
+	labelWidth := markdownconverter.DisplayWidth(label)
+
+	inner := 0
+	for _, line := range lines {
+		if lineWidth := markdownconverter.DisplayWidth(line); lineWidth > inner {
+			inner = lineWidth
+		}
+	}
+	if label != "" && inner < labelWidth+2 {
+		inner = labelWidth + 2
+	}
+	if inner > width-4 {
+		inner = width - 4
+	}
+	if inner < 1 {
+		inner = 1
+	}
+
+	top := renderer.sgr(faintStyle, "┌"+strings.Repeat("─", inner+2)+"┐")
+	if label != "" && inner >= labelWidth+2 {
+		top = renderer.sgr(faintStyle, "┌─ ") + renderer.sgr(boldStyle, label) + renderer.sgr(faintStyle, " "+strings.Repeat("─", inner-labelWidth-1)+"┐")
+	}
+
+	rows := []string{top}
+	border := renderer.sgr(faintStyle, "│")
+	for _, line := range lines {
+		for _, part := range breakLine(line, inner) {
+			rows = append(rows, border+" "+part+strings.Repeat(" ", inner-markdownconverter.DisplayWidth(part))+" "+border)
+		}
+	}
+	rows = append(rows, renderer.sgr(faintStyle, "└"+strings.Repeat("─", inner+2)+"┘"))
+	return strings.Join(rows, "\n")
+}
+
+// table draws the table with borders around columns aligned by their
+// displayed width, with the header row in bold
+func (renderer *renderer) table(node *ast.Table) string {
+	type cell struct {
+		text  string
+		width int
+	}
+
+	rows := make([][]cell, 0)
+	headers := 0
+	ast.WalkFunc(node, func(child ast.Node, entering bool) ast.WalkStatus {
+		row, ok := child.(*ast.TableRow)
+		if !ok || !entering {
+			return ast.GoToNext
+		}
+		_, header := row.Parent.(*ast.TableHeader)
+		style := ""
+		if header {
+			style = boldStyle
+			headers++
+		}
+		cells := make([]cell, 0, len(row.Children))
+		for _, tableCell := range row.Children {
+			text, width := renderer.wrap(renderer.inline(tableCell, style), 0)
+			cells = append(cells, cell{text: strings.ReplaceAll(text, "\n", " "), width: width})
+		}
+		rows = append(rows, cells)
+		return ast.SkipChildren
+	})
+
+	widths := make([]int, 0)
+	for _, row := range rows {
+		for index, cell := range row {
+			if index >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if cell.width > widths[index] {
+				widths[index] = cell.width
+			}
+		}
+	}
+	if len(widths) == 0 {
+		return ""
+	}
+
+	border := func(left, middle, right string) string {
+		parts := make([]string, len(widths))
+		for index, width := range widths {
+			parts[index] = strings.Repeat("─", width+2)
+		}
+		return renderer.sgr(faintStyle, left+strings.Join(parts, middle)+right)
+	}
+
+	separator := renderer.sgr(faintStyle, "│")
+	lines := []string{border("┌", "┬", "┐")}
+	for index, row := range rows {
+		builder := &strings.Builder{}
+		builder.WriteString(separator)
+		for column, width := range widths {
+			content := cell{}
+			if column < len(row) {
+				content = row[column]
+			}
+			builder.WriteString(" " + content.text + strings.Repeat(" ", width-content.width) + " " + separator)
+		}
+		lines = append(lines, builder.String())
+		if index == headers-1 && index < len(rows)-1 {
+			lines = append(lines, border("├", "┼", "┤"))
+		}
+	}
+	lines = append(lines, border("└", "┴", "┘"))
+	return strings.Join(lines, "\n")
+}
+
+// inline returns the styled spans of the inline children of the node
+func (renderer *renderer) inline(node ast.Node, style string) []span {
+	spans := make([]span, 0)
+	for _, child := range node.GetChildren() {
+		current := style
+		if renderer.underline > 0 {
+			current = combine(current, underlineStyle)
+		}
+
+		switch child := child.(type) {
+		case *ast.Text:
+			spans = append(spans, span{text: clean(strings.ReplaceAll(string(child.Literal), "\n", " ")), style: current})
+		case *ast.Code:
+			spans = append(spans, renderer.code(string(child.Literal), current))
+		case *ast.Math:
+			spans = append(spans, renderer.code(string(child.Literal), current))
+		case *ast.Emph:
+			spans = append(spans, renderer.inline(child, combine(current, italicStyle))...)
+		case *ast.Strong:
+			spans = append(spans, renderer.inline(child, combine(current, boldStyle))...)
+		case *ast.Del:
+			spans = append(spans, renderer.inline(child, combine(current, strikeStyle))...)
+		case *ast.Hardbreak:
+			spans = append(spans, span{text: "\n"})
+		case *ast.Link:
+			spans = append(spans, renderer.link(child, current)...)
+		case *ast.Image:
+			destination := clean(string(child.Destination))
+			text := "[image]"
+			if alt := markdownconverter.PlainText(child); alt != "" {
+				text = "[" + alt + "]"
+			}
+			if renderer.hyperlinks {
+				spans = append(spans, span{text: text, style: combine(current, faintStyle), link: destination})
+			} else {
+				spans = append(spans, span{text: text, style: combine(current, faintStyle)}, span{text: " (" + destination + ")", style: current})
+			}
+		case *ast.HTMLSpan:
+			if opening, ok := markdownconverter.UnderlineTag(child); ok {
+				if opening {
+					renderer.underline++
+				} else if renderer.underline > 0 {
+					renderer.underline--
+				}
+			}
+		default:
+			if child.AsContainer() != nil {
+				spans = append(spans, renderer.inline(child, current)...)
+			} else {
+				spans = append(spans, span{text: clean(string(child.AsLeaf().Literal)), style: current})
+			}
+		}
+	}
+	return spans
+}
+
+// code returns the span of inline code, in backticks when it is not styled
+func (renderer *renderer) code(code, style string) span {
+	code = clean(strings.ReplaceAll(code, "\n", " "))
+	if !renderer.color {
+		code = "`" + code + "`"
+	}
+	return span{text: code, style: combine(style, codeStyle)}
+}
+
+// link returns the spans of the link text, linked to its destination with an
+// OSC 8 hyperlink or followed by the destination
+func (renderer *renderer) link(node *ast.Link, style string) []span {
+	destination := clean(string(node.Destination))
+	if strings.HasPrefix(destination, "#") {
+		// there is nowhere to link to in the terminal
+		return renderer.inline(node, style)
+	}
+
+	spans := renderer.inline(node, combine(style, linkStyle))
+	text := markdownconverter.PlainText(node)
+	if text == "" {
+		spans = []span{{text: destination, style: combine(style, linkStyle)}}
+	}
+	if renderer.hyperlinks {
+		for index := range spans {
+			spans[index].link = destination
+		}
+		return spans
+	}
+	if text != "" && text != destination && "mailto:"+text != destination {
+		spans = append(spans, span{text: " (" + destination + ")", style: style})
+	}
+	return spans
+}
+
+// wrap lays out the spans in lines no wider than the width, breaking between
+// words or beside wide characters, unless a single word is wider, or with only
+// hard line breaks if the width is zero. It returns the styled lines and the
+// displayed width of the widest.
+func (renderer *renderer) wrap(spans []span, width int) (string, int) {
+	lines := make([]string, 0)
+	widest := 0
+	line := make([]span, 0)
+	lineWidth := 0
+	end := func() {
+		lines = append(lines, renderer.render(line))
+		if lineWidth > widest {
+			widest = lineWidth
+		}
+		line = make([]span, 0)
+		lineWidth = 0
+	}
+
+	for _, word := range tokens(spans) {
+		if word.newline {
+			end()
+			continue
+		}
+		separator := 1
+		if word.attached || lineWidth == 0 {
+			separator = 0
+		}
+		if lineWidth > 0 && width > 0 && lineWidth+separator+word.width > width {
+			end()
+			separator = 0
+		}
+		if separator > 0 {
+			// the space is styled like the words around it if they match, so
+			// underlines and links are not broken between words
+			space := span{text: " "}
+			if previous, next := line[len(line)-1], word.spans[0]; previous.style == next.style && previous.link == next.link {
+				space.style, space.link = previous.style, previous.link
+			}
+			line = append(line, space)
+			lineWidth++
+		}
+		line = append(line, word.spans...)
+		lineWidth += word.width
+	}
+	end()
+	return strings.Join(lines, "\n"), widest
+}
+
+// tokens splits the spans into words at spaces and hard line breaks, and
+// splits words beside wide characters into attached tokens
+func tokens(spans []span) []token {
+	result := make([]token, 0)
+	current := token{}
+	previousWide := false
+	flush := func() {
+		if len(current.spans) > 0 {
+			result = append(result, current)
+		}
+		current = token{}
+	}
+
+	for _, span := range spans {
+		if span.text == "\n" {
+			flush()
+			result = append(result, token{newline: true})
+			continue
+		}
+		for index, part := range strings.Split(span.text, " ") {
+			if index > 0 {
+				flush()
+			}
+			for _, piece := range markdownconverter.BreakWide(part) {
+				first, _ := utf8.DecodeRuneInString(piece)
+				wide := markdownconverter.RuneWidth(first) > 1
+				if len(current.spans) > 0 && (wide || previousWide) {
+					flush()
+					current.attached = true
+				}
+				span.text = piece
+				current.spans = append(current.spans, span)
+				current.width += markdownconverter.DisplayWidth(piece)
+				previousWide = wide
+			}
+		}
+	}
+	flush()
+	return result
+}
+
+// render writes the spans with their styles and links, combining adjacent
+// spans that are the same
+func (renderer *renderer) render(spans []span) string {
+	builder := &strings.Builder{}
+	for start := 0; start < len(spans); {
+		end := start + 1
+		text := spans[start].text
+		for end < len(spans) && spans[end].style == spans[start].style && spans[end].link == spans[start].link {
+			text += spans[end].text
+			end++
+		}
+		builder.WriteString(renderer.hyperlink(spans[start].link, renderer.sgr(spans[start].style, text)))
+		start = end
+	}
+	return builder.String()
+}
+
+// sgr wraps the text in the escape codes that set the style and reset it,
+// unless colors are disabled
+func (renderer *renderer) sgr(style, text string) string {
+	if !renderer.color || style == "" || text == "" {
+		return text
+	}
+	return "\x1b[" + style + "m" + text + "\x1b[0m"
+}
+
+// hyperlink wraps the text in the OSC 8 escape sequences that link it to the
+// destination
+func (renderer *renderer) hyperlink(destination, text string) string {
+	if destination == "" {
+		return text
+	}
+	return "\x1b]8;;" + destination + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
+
+// combine joins the SGR parameters of two styles
+func combine(style, other string) string {
+	if style == "" {
+		return other
+	}
+	return style + ";" + other
+}
+
+// clean removes control characters from text taken from the markdown, so it
+// cannot include escape sequences of its own
+func clean(text string) string {
+	return strings.Map(func(r rune) rune {
+		if (r < 0x20 && r != '\n' && r != '\t') || (r >= 0x7F && r < 0xA0) {
+			return -1
+		}
+		return r
+	}, text)
+}
+
+// breakLine splits the line into parts no wider than the width
+func breakLine(line string, width int) []string {
+	parts := make([]string, 0, 1)
+	current := &strings.Builder{}
+	currentWidth := 0
+	for _, r := range line {
+		runeWidth := markdownconverter.RuneWidth(r)
+		if currentWidth > 0 && currentWidth+runeWidth > width {
+			parts = append(parts, current.String())
+			current.Reset()
+			currentWidth = 0
+		}
+		current.WriteRune(r)
+		currentWidth += runeWidth
+	}
+	return append(parts, current.String())
+}
+
+// quoteLines adds the quote marker and a space to each line of the text, and
+// only the marker to empty lines
+func quoteLines(text, marker string) string {
+	return markdownconverter.PrefixLines(text, marker+" ", marker+" ")
+}
+
+// indent adds the prefix to each line of the text that is not empty
+func indent(text, prefix string) string {
+	lines := strings.Split(text, "\n")
+	for index, line := range lines {
+		if line != "" {
+			lines[index] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,10 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package terminal
+
+// terminalWidth is not supported on this platform, so the width is taken from
+// the COLUMNS environment variable or the default
+func terminalWidth() (int, bool) {
+	return 0, false
+}
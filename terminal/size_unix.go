@@ -0,0 +1,29 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package terminal
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// winsize is the terminal size returned by the TIOCGWINSZ ioctl
+type winsize struct {
+	rows    uint16
+	columns uint16
+	x       uint16
+	y       uint16
+}
+
+// terminalWidth returns the number of columns of the terminal standard output
+// is written to, false if it is not a terminal
+func terminalWidth() (int, bool) {
+	size := &winsize{}
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, os.Stdout.Fd(), uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(size)))
+	if errno != 0 || size.columns == 0 {
+		return 0, false
+	}
+	return int(size.columns), true
+}
@@ -0,0 +1,82 @@
+// Package terminal converts markdown to text styled with ANSI escape codes for
+// display in a terminal
+package terminal
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/evilmonkeyinc/markdownconverter"
+	"github.com/evilmonkeyinc/markdownconverter/links"
+	"github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/parser"
+)
+
+// DefaultWidth is the number of columns used when the width of the terminal
+// cannot be detected
+const DefaultWidth int = 80
+
+// New returns a new instance of Converter, with colors and styles disabled if
+// the NO_COLOR environment variable is set
+func New() *Converter {
+	return &Converter{
+		Color:      os.Getenv("NO_COLOR") == "",
+		Hyperlinks: true,
+	}
+}
+
+// Converter is the ANSI terminal Converter implementation
+type Converter struct {
+	// Links is used to rewrite the destinations of links and images, leave nil
+	// to keep them as they are
+	Links *links.Rewriter
+	// Width is the number of columns the output is wrapped at, zero to detect
+	// the width of the terminal
+	Width int
+	// Color will style the output with ANSI colors, bold, italic, and underline
+	Color bool
+	// Hyperlinks will make links clickable with OSC 8 escape sequences, rather
+	// than writing their URL after the text
+	Hyperlinks bool
+}
+
+// Format returns a unique name for the converter
+func (converter *Converter) Format() string {
+	return "terminal"
+}
+
+// Parse will parse the standard markdown and return the converted data
+func (converter *Converter) Parse(markdwn []byte) ([]byte, error) {
+	_, body := markdownconverter.FrontMatter(markdwn)
+	document := markdown.Parse(markdown.NormalizeNewlines(body), parser.NewWithExtensions(parser.CommonExtensions|parser.OrderedListStart))
+	markdownconverter.Admonitions(document)
+	if converter.Links != nil {
+		if err := converter.Links.Rewrite(document); err != nil {
+			return nil, err
+		}
+	}
+
+	width := converter.Width
+	if width <= 0 {
+		width = DetectWidth()
+	}
+	renderer := &renderer{
+		color:      converter.Color,
+		hyperlinks: converter.Hyperlinks,
+	}
+	return []byte(strings.Trim(renderer.blocks(document.GetChildren(), width), "\n")), nil
+}
+
+// DetectWidth returns the number of columns of the terminal, from the COLUMNS
+// environment variable or the terminal standard output is written to, or
+// DefaultWidth if neither is available
+func DetectWidth() int {
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	if width, ok := terminalWidth(); ok {
+		return width
+	}
+	return DefaultWidth
+}
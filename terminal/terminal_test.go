@@ -0,0 +1,211 @@
+package terminal
+
+import (
+	"testing"
+
+	"github.com/evilmonkeyinc/markdownconverter/links"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Converter_Format(t *testing.T) {
+	actual := New().Format()
+	assert.Equal(t, "terminal", actual)
+}
+
+func Test_New(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	assert.True(t, New().Color)
+
+	t.Setenv("NO_COLOR", "1")
+	assert.False(t, New().Color)
+}
+
+func Test_DetectWidth(t *testing.T) {
+	t.Setenv("COLUMNS", "120")
+	assert.Equal(t, 120, DetectWidth())
+
+	t.Setenv("COLUMNS", "invalid")
+	width, ok := terminalWidth()
+	if !ok {
+		width = DefaultWidth
+	}
+	assert.Equal(t, width, DetectWidth())
+}
+
+func Test_Converter_Parse(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "headings",
+			input:    "# Title\n\n## Section\n\n### Subsection\n\n#### Deeper",
+			expected: "\x1b[1;4;35mTitle\x1b[0m\n\n\x1b[1;36mSection\x1b[0m\n\n\x1b[1;34mSubsection\x1b[0m\n\n\x1b[1mDeeper\x1b[0m",
+		},
+		{
+			name:     "formatting",
+			input:    "**bold** _italic_ ~~strikethrough~~ <u>underline</u> `code`",
+			expected: "\x1b[1mbold\x1b[0m \x1b[3mitalic\x1b[0m\n\x1b[9mstrikethrough\x1b[0m\n\x1b[4munderline\x1b[0m \x1b[33mcode\x1b[0m",
+		},
+		{
+			name:     "nested_formatting",
+			input:    "**bold _and italic_**",
+			expected: "\x1b[1mbold\x1b[0m \x1b[1;3mand italic\x1b[0m",
+		},
+		{
+			name:     "links",
+			input:    "[the docs](https://example.com) and <https://github.com>",
+			expected: "\x1b]8;;https://example.com\x1b\\\x1b[4;34mthe docs\x1b[0m\x1b]8;;\x1b\\ and\n\x1b]8;;https://github.com\x1b\\\x1b[4;34mhttps://github.com\x1b[0m\x1b]8;;\x1b\\",
+		},
+		{
+			name:     "fragment_link",
+			input:    "[below](#below)",
+			expected: "below",
+		},
+		{
+			name:     "image",
+			input:    "![logo](logo.png)",
+			expected: "\x1b]8;;logo.png\x1b\\\x1b[2m[logo]\x1b[0m\x1b]8;;\x1b\\",
+		},
+		{
+			name:     "escape_sequences_removed",
+			input:    "safe \x1b[31mtext",
+			expected: "safe [31mtext",
+		},
+		{
+			name:     "lists",
+			input:    "- one\n  - nested\n    - deeper\n- two\n\n1. first\n2. second",
+			expected: "• one\n  ◦ nested\n    ▪ deeper\n• two\n\n1. first\n2. second",
+		},
+		{
+			name:     "ordered_list_start",
+			input:    "3. third\n4. fourth",
+			expected: "3. third\n4. fourth",
+		},
+		{
+			name:     "block_quote",
+			input:    "> quoted\n> text",
+			expected: "\x1b[90m│\x1b[0m quoted text",
+		},
+		{
+			name:     "admonition",
+			input:    "> [!TIP]\n> Try this",
+			expected: "\x1b[32m│\x1b[0m \x1b[32;1mTip\x1b[0m\n\x1b[32m│\x1b[0m Try this",
+		},
+		{
+			name:     "code_block",
+			input:    "```go\nfunc main() {}\n```",
+			expected: "\x1b[2m┌─ \x1b[0m\x1b[1mgo\x1b[0m\x1b[2m ───────────┐\x1b[0m\n\x1b[2m│\x1b[0m func main() {} \x1b[2m│\x1b[0m\n\x1b[2m└────────────────┘\x1b[0m",
+		},
+		{
+			name:     "horizontal_rule",
+			input:    "above\n\n---\n\nbelow",
+			expected: "above\n\n\x1b[2m" + "────────────────────" + "\x1b[0m\n\nbelow",
+		},
+		{
+			name:     "table",
+			input:    "| Name | Value |\n| --- | --- |\n| 中文 | 1 |",
+			expected: "\x1b[2m┌──────┬───────┐\x1b[0m\n\x1b[2m│\x1b[0m \x1b[1mName\x1b[0m \x1b[2m│\x1b[0m \x1b[1mValue\x1b[0m \x1b[2m│\x1b[0m\n\x1b[2m├──────┼───────┤\x1b[0m\n\x1b[2m│\x1b[0m 中文 \x1b[2m│\x1b[0m 1     \x1b[2m│\x1b[0m\n\x1b[2m└──────┴───────┘\x1b[0m",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			converter := New()
+			converter.Color = true
+			converter.Width = 20
+			actual, err := converter.Parse([]byte(test.input))
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, string(actual))
+		})
+	}
+}
+
+func Test_Converter_Parse_NoColor(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		input      string
+		hyperlinks bool
+		expected   string
+	}{
+		{
+			name:     "headings",
+			input:    "# Title\n\n## Section\n\n### Subsection",
+			expected: "Title\n=====\n\nSection\n-------\n\nSubsection",
+		},
+		{
+			name:     "formatting",
+			input:    "**bold** _italic_ `code`",
+			expected: "bold italic `code`",
+		},
+		{
+			name:     "links",
+			input:    "[docs](https://example.com) and <https://github.com>",
+			expected: "docs\n(https://example.com)\nand\nhttps://github.com",
+		},
+		{
+			name:       "hyperlinks",
+			input:      "[docs](https://example.com)",
+			hyperlinks: true,
+			expected:   "\x1b]8;;https://example.com\x1b\\docs\x1b]8;;\x1b\\",
+		},
+		{
+			name:     "code_block",
+			input:    "```\nfunc main() { fmt.Println() }\n```",
+			expected: "┌──────────────────┐\n│ func main() { fm │\n│ t.Println() }    │\n└──────────────────┘",
+		},
+		{
+			name:     "wrapped",
+			input:    "The quick brown fox jumps over the lazy dog.",
+			expected: "The quick brown fox\njumps over the lazy\ndog.",
+		},
+		{
+			name:     "wrapped_wide_characters",
+			input:    "see **日本語** text and 中文字符串很长很长很长",
+			expected: "see 日本語 text and\n中文字符串很长很长很\n长",
+		},
+		{
+			name:     "wrapped_quote_wide_characters",
+			input:    "> 日本語の文章を折り返して表示するテストです",
+			expected: "│ 日本語の文章を折り\n│ 返して表示するテス\n│ トです",
+		},
+		{
+			name:     "hard_line_breaks",
+			input:    "one\\\ntwo",
+			expected: "one\ntwo",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			converter := New()
+			converter.Color = false
+			converter.Hyperlinks = test.hyperlinks
+			converter.Width = 20
+			actual, err := converter.Parse([]byte(test.input))
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, string(actual))
+		})
+	}
+}
+
+func Test_Converter_Parse_Links(t *testing.T) {
+	converter := New()
+	converter.Color = false
+	converter.Hyperlinks = false
+	converter.Links = &links.Rewriter{
+		BaseURL:        "https://example.com/docs/",
+		MarkdownToHTML: true,
+	}
+
+	actual, err := converter.Parse([]byte("[setup](./setup.md)"))
+	assert.Nil(t, err)
+	assert.Equal(t, "setup (https://example.com/docs/setup.html)", string(actual))
+
+	converter.Links.BaseURL = "invalid"
+	_, err = converter.Parse([]byte("[setup](./setup.md)"))
+	assert.ErrorIs(t, err, links.ErrInvalidBaseURL)
+}
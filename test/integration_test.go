@@ -9,7 +9,7 @@ import (
 )
 
 const (
-	sampleHelpText string = "markdownconverter is a tool for converting markdown to other formats\n\nUsage:\n\n  markdownconverter [format] [input] [output]\n\nExample:\n\n  markdownconverter slack \"[evilmonkeyinc](https://github.com/evilmonkeyinc)\"\n  > <https://github.com/evilmonkeyinc|evilmonkeyinc>\n\nOptions:\n\n  -f, --format string   The output format\n  -i, --input string    The input source file\n  -o, --output string   The output destination file. optional\n"
+	sampleHelpText string = "markdownconverter is a tool for converting markdown to other formats\n\nUsage:\n\n  markdownconverter [format] [input] [output]\n  markdownconverter serve\n  markdownconverter preview [input]\n\nExample:\n\n  markdownconverter slack \"[evilmonkeyinc](https://github.com/evilmonkeyinc)\"\n  > <https://github.com/evilmonkeyinc|evilmonkeyinc>\n\nOptions:\n\n      --address string           The address the serve and preview commands listen on, preview defaults to localhost:8080. optional (default \":8080\")\n      --base-url string          The URL used to resolve relative links and images. optional\n      --default-style            Embed the default stylesheet in standalone HTML documents. optional\n      --diagram-link string      The URL linked in place of diagrams in the slack format output, such as the published page. optional\n      --discord-tables string    How tables are shown in the discord format output. optional (code, list) (default \"code\")\n      --embed-images             Embed local images, relative to the input file, as data URIs in the http format output. optional\n      --extension strings        Parser extensions to enable for the http format, prefix with 'no-' to disable. optional (auto-heading-ids, autolinks, definition-lists, fenced-code, footnotes, hard-line-breaks, heading-ids, math, strikethrough, super-subscript, tables)\n  -f, --format string            The output format\n      --heading-anchors          Add a link to itself in every heading in the http format output. optional\n      --heading-ids              Give every heading a unique ID in the http format output. optional\n      --highlight                Add syntax highlighting to code blocks in the http format output. optional (bash, console, diff, go, golang, json, patch, sh, shell, sql, yaml, yml, zsh)\n      --highlight-inline         Use inline styles rather than classes for syntax highlighting. optional\n      --highlight-theme string   The syntax highlighting theme. optional (github, github-dark, monokai) (default \"github\")\n      --html-flag strings        Renderer flags to enable for the http format, prefix with 'no-' to disable. optional (footnote-return-links, lazy-load-images, nofollow, noopener, noreferrer, safe-links, skip-html, skip-images, smart-dashes, smart-fractions, smartypants, target-blank, xhtml)\n  -i, --input string             The input source file\n      --mathml                   Render TeX math between $ or $$ delimiters as MathML in the http format output. optional\n      --max-body-size int        The size in bytes above which the serve command rejects requests, 0 for no limit. optional (default 1048576)\n      --max-image-size int       The size in bytes above which images are not embedded, 0 for no limit. optional (default 1048576)\n      --md-to-html               Rewrite relative links to markdown files to link to HTML files. optional\n      --no-hyperlinks            Write the URL after links rather than using clickable hyperlinks in the terminal format output. optional\n  -o, --output string            The output destination file. optional\n      --rst-tables string        How tables are written in the rst format output. optional (grid, list) (default \"grid\")\n      --sanitize                 Remove unsafe HTML, such as scripts and javascript links, from the http format output. optional\n      --standalone               Output a complete HTML document for the http format. optional\n      --stylesheet string        The path or URL of a stylesheet to link from standalone HTML documents. optional\n      --suppress-embeds          Wrap links in angle brackets so Discord does not show link previews in the discord format output. optional\n      --telegram-mode string     The parse mode of the telegram format output. optional (MarkdownV2, HTML) (default \"MarkdownV2\")\n      --terminal-width int       The number of columns the terminal format output is wrapped at, 0 to detect the width of the terminal. optional\n      --text-width int           The number of columns the text format output is wrapped at, 0 to not wrap. optional (default 80)\n      --toc                      Replace a [TOC] paragraph, or the start of the http format output, with a table of contents. optional\n"
 )
 
 func runCommand(arg ...string) (string, error) {
@@ -94,10 +94,150 @@ func Test_IntegrationTests(t *testing.T) {
 			args:     []string{"[evilmonkeyinc](https://github.com/evilmonkeyinc)", "-f=slack"},
 			expected: "<https://github.com/evilmonkeyinc|evilmonkeyinc>\n",
 		},
+		{
+			name:     "http_extension_flag",
+			args:     []string{"http", "--extension=hard-line-breaks", "line one\nline two"},
+			expected: "<p>line one<br>\nline two</p>\n",
+		},
+		{
+			name:     "http_html_flag",
+			args:     []string{"http", "--html-flag=target-blank", "[evilmonkeyinc](https://github.com/evilmonkeyinc)"},
+			expected: "<p><a href=\"https://github.com/evilmonkeyinc\" target=\"_blank\">evilmonkeyinc</a></p>\n",
+		},
+		{
+			name:     "http_standalone",
+			args:     []string{"http", "--standalone", "--stylesheet=style.css", "# Title"},
+			expected: "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\">\n<title>Title</title>\n<link rel=\"stylesheet\" href=\"style.css\">\n</head>\n<body>\n<h1>Title</h1>\n</body>\n</html>\n",
+		},
+		{
+			name:     "http_sanitize",
+			args:     []string{"http", "--sanitize", "<script>alert(1)</script>[click](javascript:alert(1))"},
+			expected: "<p><a>click</a></p>\n",
+		},
+		{
+			name:     "http_sanitize_highlight_inline",
+			args:     []string{"http", "--highlight", "--highlight-inline", "--sanitize", "```go\nif x {}\n```"},
+			expected: "<pre style=\"background-color: #f6f8fa; color: #24292e\"><code class=\"language-go\"><span style=\"color: #d73a49\">if</span> x <span style=\"color: #24292e\">{}</span>\n</code></pre>\n",
+		},
+		{
+			name:     "http_toc",
+			args:     []string{"http", "--toc", "# Title"},
+			expected: "<nav class=\"toc\">\n<ul>\n<li><a href=\"#title\">Title</a></li>\n</ul>\n</nav>\n\n<h1 id=\"title\">Title</h1>\n",
+		},
+		{
+			name:     "http_highlight",
+			args:     []string{"http", "--highlight", "```go\nreturn nil\n```"},
+			expected: "<pre class=\"highlight\"><code class=\"language-go\"><span class=\"hl-keyword\">return</span> <span class=\"hl-literal\">nil</span>\n</code></pre>\n",
+		},
+		{
+			name:     "invalid_highlight_theme",
+			args:     []string{"http", "--highlight", "--highlight-theme=invalid", "text"},
+			expected: "failed: failed to parse unknown theme 'invalid', expected: (github, github-dark, monokai)\nexit status 1\n",
+		},
+		{
+			name:     "slack_base_url",
+			args:     []string{"slack", "--base-url=https://example.com/docs/", "--md-to-html", "[setup](setup)"},
+			expected: "<https://example.com/docs/setup|setup>\n",
+		},
+		{
+			name:     "http_embed_images",
+			args:     []string{"http", "--embed-images", "--max-image-size=100", "../http/testdata/images.md"},
+			expected: "<p><img src=\"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAAC0lEQVR4nGNgAAIAAAUAAXpeqz8AAAAASUVORK5CYII=\" alt=\"px\" /> <img src=\"data:image/svg+xml;base64,PHN2ZyB4bWxucz0iaHR0cDovL3d3dy53My5vcmcvMjAwMC9zdmciIHdpZHRoPSIxIiBoZWlnaHQ9IjEiPjwvc3ZnPgo=\" alt=\"svg\" /> <img src=\"notes.txt\" alt=\"txt\" /> <img src=\"nope.png\" alt=\"missing\" /> <img src=\"https://x.com/a.png\" alt=\"remote\" /></p>\n",
+		},
+		{
+			name:     "invalid_extension",
+			args:     []string{"http", "--extension=invalid", "text"},
+			expected: "failed: unknown extension 'invalid', expected: (auto-heading-ids, autolinks, definition-lists, fenced-code, footnotes, hard-line-breaks, heading-ids, math, strikethrough, super-subscript, tables)\nexit status 1\n",
+		},
+		{
+			name:     "invalid_html_flag",
+			args:     []string{"http", "--html-flag=invalid", "text"},
+			expected: "failed: unknown flag 'invalid', expected: (footnote-return-links, lazy-load-images, nofollow, noopener, noreferrer, safe-links, skip-html, skip-images, smart-dashes, smart-fractions, smartypants, target-blank, xhtml)\nexit status 1\n",
+		},
+		{
+			name:     "discord",
+			args:     []string{"discord", "**bold** text"},
+			expected: "**bold** text\n",
+		},
+		{
+			name:     "teams",
+			args:     []string{"teams", "text"},
+			expected: "{\n  \"type\": \"message\",\n  \"attachments\": [\n    {\n      \"contentType\": \"application/vnd.microsoft.card.adaptive\",\n      \"content\": {\n        \"$schema\": \"http://adaptivecards.io/schemas/adaptive-card.json\",\n        \"type\": \"AdaptiveCard\",\n        \"version\": \"1.5\",\n        \"body\": [\n          {\n            \"type\": \"TextBlock\",\n            \"text\": \"text\",\n            \"wrap\": true\n          }\n        ]\n      }\n    }\n  ]\n}\n",
+		},
+		{
+			name:     "telegram",
+			args:     []string{"telegram", "**bold** text!"},
+			expected: "*bold* text\\!\n",
+		},
+		{
+			name:     "telegram_html",
+			args:     []string{"telegram", "--telegram-mode=HTML", "**bold** text!"},
+			expected: "<b>bold</b> text!\n",
+		},
+		{
+			name:     "jira",
+			args:     []string{"jira", "**bold** text"},
+			expected: "*bold* text\n",
+		},
+		{
+			name:     "confluence",
+			args:     []string{"confluence", "**bold** text"},
+			expected: "<p><strong>bold</strong> text</p>\n",
+		},
+		{
+			name:     "adf",
+			args:     []string{"adf", "text"},
+			expected: "{\n  \"version\": 1,\n  \"type\": \"doc\",\n  \"content\": [\n    {\n      \"type\": \"paragraph\",\n      \"content\": [\n        {\n          \"type\": \"text\",\n          \"text\": \"text\"\n        }\n      ]\n    }\n  ]\n}\n",
+		},
+		{
+			name:     "asciidoc",
+			args:     []string{"asciidoc", "**bold** text"},
+			expected: "*bold* text\n",
+		},
+		{
+			name:     "rst",
+			args:     []string{"rst", "**bold** text"},
+			expected: "**bold** text\n",
+		},
+		{
+			name:     "invalid_rst_tables",
+			args:     []string{"rst", "--rst-tables=invalid", "text"},
+			expected: "failed: unexpected tables 'invalid', expected: (grid, list)\nexit status 1\n",
+		},
+		{
+			name:     "text",
+			args:     []string{"text", "**bold** text"},
+			expected: "bold text\n",
+		},
+		{
+			name:     "terminal",
+			args:     []string{"terminal", "**bold** text"},
+			expected: "\x1b[1mbold\x1b[0m text\n",
+		},
+		{
+			name:     "invalid_discord_tables",
+			args:     []string{"discord", "--discord-tables=invalid", "text"},
+			expected: "failed: unexpected tables 'invalid', expected: (code, list)\nexit status 1\n",
+		},
+		{
+			name:     "slack_invalid_discord_tables",
+			args:     []string{"slack", "--discord-tables=invalid", "text"},
+			expected: "text\n",
+		},
+		{
+			name:     "invalid_telegram_mode",
+			args:     []string{"telegram", "--telegram-mode=invalid", "text"},
+			expected: "failed: unexpected mode 'invalid', expected: (MarkdownV2, HTML)\nexit status 1\n",
+		},
+		{
+			name:     "jira_invalid_telegram_mode",
+			args:     []string{"jira", "--telegram-mode=invalid", "text"},
+			expected: "text\n",
+		},
 		{
 			name:     "invalid_format",
 			args:     []string{"-f=invalid"},
-			expected: "failed: unexpected format 'invalid', expected: (slack, http)\nexit status 1\n",
+			expected: "failed: unexpected format 'invalid', expected: (slack, http, html-email, eml, discord, teams, telegram, jira, confluence, adf, asciidoc, rst, text, terminal)\nexit status 1\n",
 		},
 	}
 
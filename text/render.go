@@ -0,0 +1,277 @@
+package text
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/evilmonkeyinc/markdownconverter"
+	"github.com/gomarkdown/markdown/ast"
+)
+
+const (
+	// codeIndent is the indentation of code blocks, which are not wrapped
+	codeIndent string = "    "
+	// ruleWidth is the widest a horizontal rule is drawn
+	ruleWidth int = 20
+)
+
+// blocks renders each block node wrapped at the width and separated by a
+// blank line
+func blocks(nodes []ast.Node, width int) string {
+	parts := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		if text := block(node, width); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func block(node ast.Node, width int) string {
+	switch node := node.(type) {
+	case *ast.Heading:
+		text := Wrap(inline(node), width)
+		underline := "-"
+		if node.Level == 1 {
+			underline = "="
+		}
+		return text + "\n" + strings.Repeat(underline, widest(text))
+	case *ast.Paragraph:
+		return Wrap(inline(node), width)
+	case *ast.BlockQuote:
+		return markdownconverter.PrefixLines(blocks(node.Children, markdownconverter.Narrower(width, 2)), "> ", "> ")
+	case *markdownconverter.Admonition:
+		content := node.Label() + ":"
+		if children := blocks(node.Children, markdownconverter.Narrower(width, 2)); children != "" {
+			content += "\n" + children
+		}
+		return markdownconverter.PrefixLines(content, "> ", "> ")
+	case *ast.CodeBlock:
+		code := markdownconverter.PrefixLines(strings.TrimRight(string(node.Literal), "\n"), codeIndent, codeIndent)
+		if diagramType := markdownconverter.DiagramType(node.Info); diagramType != "" {
+			return markdownconverter.DiagramLabel(diagramType) + " diagram:\n" + code
+		}
+		return code
+	case *ast.MathBlock:
+		return markdownconverter.PrefixLines(strings.TrimSpace(string(node.Literal)), codeIndent, codeIndent)
+	case *ast.List:
+		if node.ListFlags&ast.ListTypeDefinition != 0 {
+			return definitionList(node, width)
+		}
+		return list(node, width)
+	case *ast.HorizontalRule:
+		if width > 0 && width < ruleWidth {
+			return strings.Repeat("-", width)
+		}
+		return strings.Repeat("-", ruleWidth)
+	case *ast.Table:
+		return table(node)
+	case *ast.HTMLBlock:
+		return ""
+	default:
+		if container := node.AsContainer(); container != nil {
+			return blocks(container.Children, width)
+		}
+		return Wrap(strings.TrimSpace(string(node.AsLeaf().Literal)), width)
+	}
+}
+
+// list renders the list items with their content wrapped to line up with the
+// text after the marker
+func list(node *ast.List, width int) string {
+	separator := "\n"
+	if !node.Tight {
+		separator = "\n\n"
+	}
+
+	start := node.Start
+	if start == 0 {
+		start = 1
+	}
+
+	items := make([]string, 0, len(node.Children))
+	for index, child := range node.Children {
+		marker := "- "
+		if node.ListFlags&ast.ListTypeOrdered != 0 {
+			marker = fmt.Sprintf("%d. ", index+start)
+		}
+
+		parts := make([]string, 0)
+		for _, grandchild := range child.GetChildren() {
+			if text := block(grandchild, markdownconverter.Narrower(width, len(marker))); text != "" {
+				parts = append(parts, text)
+			}
+		}
+		items = append(items, markdownconverter.PrefixLines(strings.Join(parts, separator), marker, strings.Repeat(" ", len(marker))))
+	}
+	return strings.Join(items, separator)
+}
+
+// definitionList renders each term with its definitions indented below
+func definitionList(node *ast.List, width int) string {
+	lines := make([]string, 0, len(node.Children))
+	for _, child := range node.Children {
+		item, ok := child.(*ast.ListItem)
+		if !ok {
+			continue
+		}
+		if item.ListFlags&ast.ListTypeTerm != 0 {
+			if len(lines) > 0 && !node.Tight {
+				lines = append(lines, "")
+			}
+			lines = append(lines, Wrap(inline(item), width))
+			continue
+		}
+		if text := blocks(item.Children, markdownconverter.Narrower(width, 2)); text != "" {
+			lines = append(lines, markdownconverter.PrefixLines(text, "  ", "  "))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// table renders the table as columns aligned by their displayed width, with
+// a line under the header row
+func table(node *ast.Table) string {
+	rows := make([][]string, 0)
+	headers := 0
+	ast.WalkFunc(node, func(child ast.Node, entering bool) ast.WalkStatus {
+		row, ok := child.(*ast.TableRow)
+		if !ok || !entering {
+			return ast.GoToNext
+		}
+		cells := make([]string, 0, len(row.Children))
+		for _, cell := range row.Children {
+			cells = append(cells, inline(cell))
+		}
+		if _, header := row.Parent.(*ast.TableHeader); header {
+			headers++
+		}
+		rows = append(rows, cells)
+		return ast.SkipChildren
+	})
+
+	widths := make([]int, 0)
+	for _, row := range rows {
+		for index, cell := range row {
+			if index >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if cellWidth := markdownconverter.DisplayWidth(cell); cellWidth > widths[index] {
+				widths[index] = cellWidth
+			}
+		}
+	}
+
+	line := func(cells []string) string {
+		builder := &strings.Builder{}
+		for index, cell := range cells {
+			if index > 0 {
+				builder.WriteString("  ")
+			}
+			builder.WriteString(cell + strings.Repeat(" ", widths[index]-markdownconverter.DisplayWidth(cell)))
+		}
+		return strings.TrimRight(builder.String(), " ")
+	}
+
+	lines := make([]string, 0, len(rows)+1)
+	for index, row := range rows {
+		lines = append(lines, line(row))
+		if index == headers-1 {
+			rules := make([]string, len(widths))
+			for column, columnWidth := range widths {
+				rules[column] = strings.Repeat("-", columnWidth)
+			}
+			lines = append(lines, line(rules))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// inline renders the text of the node and its inline children, with line
+// breaks only where there are hard line breaks
+func inline(node ast.Node) string {
+	builder := &strings.Builder{}
+	for _, child := range node.GetChildren() {
+		switch child := child.(type) {
+		case *ast.Text:
+			builder.WriteString(strings.ReplaceAll(string(child.Literal), "\n", " "))
+		case *ast.Hardbreak:
+			builder.WriteString("\n")
+		case *ast.Link:
+			text := inline(child)
+			destination := string(child.Destination)
+			switch {
+			case strings.HasPrefix(destination, "#") && text != "":
+				builder.WriteString(text)
+			case text == "" || text == destination || "mailto:"+text == destination:
+				builder.WriteString(destination)
+			default:
+				fmt.Fprintf(builder, "%s (%s)", text, destination)
+			}
+		case *ast.Image:
+			if text := inline(child); text != "" {
+				fmt.Fprintf(builder, "%s (%s)", text, child.Destination)
+			} else {
+				builder.Write(child.Destination)
+			}
+		case *ast.HTMLSpan:
+			continue
+		default:
+			if child.AsContainer() != nil {
+				builder.WriteString(inline(child))
+			} else {
+				builder.Write(child.AsLeaf().Literal)
+			}
+		}
+	}
+	return strings.TrimSpace(builder.String())
+}
+
+// Wrap breaks each line of the text between words, or beside wide characters,
+// so no line is displayed wider than the width, unless it is a single word that
+// is wider. The text is returned as it is if the width is zero.
+func Wrap(text string, width int) string {
+	if width <= 0 {
+		return text
+	}
+
+	lines := strings.Split(text, "\n")
+	wrapped := make([]string, 0, len(lines))
+	for _, line := range lines {
+		current := &strings.Builder{}
+		currentWidth := 0
+		for _, word := range strings.Fields(line) {
+			for index, piece := range markdownconverter.BreakWide(word) {
+				// pieces of the same word are not separated by a space
+				separator := 1
+				if index > 0 || currentWidth == 0 {
+					separator = 0
+				}
+				pieceWidth := markdownconverter.DisplayWidth(piece)
+				if currentWidth > 0 && currentWidth+separator+pieceWidth > width {
+					wrapped = append(wrapped, current.String())
+					current.Reset()
+					currentWidth, separator = 0, 0
+				}
+				if separator > 0 {
+					current.WriteString(" ")
+				}
+				current.WriteString(piece)
+				currentWidth += separator + pieceWidth
+			}
+		}
+		wrapped = append(wrapped, current.String())
+	}
+	return strings.Join(wrapped, "\n")
+}
+
+// widest returns the displayed width of the widest line of the text
+func widest(text string) int {
+	result := 0
+	for _, line := range strings.Split(text, "\n") {
+		if lineWidth := markdownconverter.DisplayWidth(line); lineWidth > result {
+			result = lineWidth
+		}
+	}
+	return result
+}
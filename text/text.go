@@ -0,0 +1,51 @@
+// Package text converts markdown to plain text without any formatting, for
+// SMS messages, logs, and notification previews
+package text
+
+import (
+	"strings"
+
+	"github.com/evilmonkeyinc/markdownconverter"
+	"github.com/evilmonkeyinc/markdownconverter/links"
+	"github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/parser"
+)
+
+// DefaultWidth is the number of columns the text is wrapped at by default
+const DefaultWidth int = 80
+
+// New returns a new instance of Converter
+func New() *Converter {
+	return &Converter{
+		Width: DefaultWidth,
+	}
+}
+
+// Converter is the plain text Converter implementation
+type Converter struct {
+	// Links is used to rewrite the destinations of links and images, leave nil
+	// to keep them as they are
+	Links *links.Rewriter
+	// Width is the number of columns, by displayed width, the text is wrapped
+	// at, zero to not wrap the text
+	Width int
+}
+
+// Format returns a unique name for the converter
+func (converter *Converter) Format() string {
+	return "text"
+}
+
+// Parse will parse the standard markdown and return the converted data
+func (converter *Converter) Parse(markdwn []byte) ([]byte, error) {
+	_, body := markdownconverter.FrontMatter(markdwn)
+	document := markdown.Parse(markdown.NormalizeNewlines(body), parser.NewWithExtensions(parser.CommonExtensions|parser.OrderedListStart))
+	markdownconverter.Admonitions(document)
+	if converter.Links != nil {
+		if err := converter.Links.Rewrite(document); err != nil {
+			return nil, err
+		}
+	}
+
+	return []byte(strings.Trim(blocks(document.GetChildren(), converter.Width), "\n")), nil
+}
@@ -0,0 +1,190 @@
+package text
+
+import (
+	"testing"
+
+	"github.com/evilmonkeyinc/markdownconverter/links"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Converter_Format(t *testing.T) {
+	actual := New().Format()
+	assert.Equal(t, "text", actual)
+}
+
+func Test_Converter_Parse(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "headings",
+			input:    "# Title\n\n## Section\n\n### Subsection",
+			expected: "Title\n=====\n\nSection\n-------\n\nSubsection\n----------",
+		},
+		{
+			name:     "wide_heading",
+			input:    "# 日本語",
+			expected: "日本語\n======",
+		},
+		{
+			name:     "formatting",
+			input:    "**bold** _italic_ ~~strikethrough~~ <u>underline</u> `code` $x^2$",
+			expected: "bold italic strikethrough underline code x^2",
+		},
+		{
+			name:     "soft_and_hard_breaks",
+			input:    "one\ntwo\\\nthree",
+			expected: "one two\nthree",
+		},
+		{
+			name:     "links",
+			input:    "[evilmonkeyinc](https://github.com/evilmonkeyinc), <https://github.com>, [mail](mailto:a@example.com) and [below](#below)",
+			expected: "evilmonkeyinc (https://github.com/evilmonkeyinc), https://github.com, mail\n(mailto:a@example.com) and below",
+		},
+		{
+			name:     "images",
+			input:    "![logo](logo.png) ![](icon.png)",
+			expected: "logo (logo.png) icon.png",
+		},
+		{
+			name:     "lists",
+			input:    "- one\n- two\n  - nested\n\n1. first\n2. second",
+			expected: "- one\n- two\n  - nested\n\n1. first\n2. second",
+		},
+		{
+			name:     "loose_list",
+			input:    "1. first\n\n    more\n\n2. second",
+			expected: "1. first\n\n   more\n\n2. second",
+		},
+		{
+			name:     "ordered_list_start",
+			input:    "3. third\n4. fourth",
+			expected: "3. third\n4. fourth",
+		},
+		{
+			name:     "definition_list",
+			input:    "Term\n: definition",
+			expected: "Term\n  definition",
+		},
+		{
+			name:     "block_quote",
+			input:    "> quoted\n> text",
+			expected: "> quoted text",
+		},
+		{
+			name:     "admonition",
+			input:    "> [!WARNING]\n> Be careful",
+			expected: "> Warning:\n> Be careful",
+		},
+		{
+			name:     "code_block",
+			input:    "```go\nfunc main() {\n\n}\n```",
+			expected: "    func main() {\n\n    }",
+		},
+		{
+			name:     "diagram",
+			input:    "```mermaid\ngraph TD\n```",
+			expected: "Mermaid diagram:\n    graph TD",
+		},
+		{
+			name:     "horizontal_rule",
+			input:    "above\n\n---\n\nbelow",
+			expected: "above\n\n--------------------\n\nbelow",
+		},
+		{
+			name:     "table",
+			input:    "| Name | Value |\n| --- | --- |\n| 中文 | 1 |\n| long name | |",
+			expected: "Name       Value\n---------  -----\n中文       1\nlong name",
+		},
+		{
+			name:     "html",
+			input:    "text\n\n<div>\nhtml\n</div>\n",
+			expected: "text",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := New().Parse([]byte(test.input))
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, string(actual))
+		})
+	}
+}
+
+func Test_Converter_Parse_Width(t *testing.T) {
+
+	input := "# A heading that is wrapped\n\nThe quick brown fox jumps over the lazy dog.\n\n- a list item that wraps\n\n> a quote that wraps too\n\n```\na code block that is never wrapped\n```"
+
+	tests := []struct {
+		name     string
+		width    int
+		expected string
+	}{
+		{
+			name:     "wrapped",
+			width:    16,
+			expected: "A heading that\nis wrapped\n==============\n\nThe quick brown\nfox jumps over\nthe lazy dog.\n\n- a list item\n  that wraps\n\n> a quote that\n> wraps too\n\n    a code block that is never wrapped",
+		},
+		{
+			name:     "not_wrapped",
+			width:    0,
+			expected: "A heading that is wrapped\n=========================\n\nThe quick brown fox jumps over the lazy dog.\n\n- a list item that wraps\n\n> a quote that wraps too\n\n    a code block that is never wrapped",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			converter := New()
+			converter.Width = test.width
+			actual, err := converter.Parse([]byte(input))
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, string(actual))
+		})
+	}
+}
+
+func Test_Wrap(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		text     string
+		width    int
+		expected string
+	}{
+		{name: "short", text: "one two", width: 10, expected: "one two"},
+		{name: "exact", text: "one two", width: 7, expected: "one two"},
+		{name: "wrapped", text: "one two three", width: 7, expected: "one two\nthree"},
+		{name: "long_word", text: "a https://example.com/long b", width: 10, expected: "a\nhttps://example.com/long\nb"},
+		{name: "wide_characters", text: "日本 語の 文章", width: 10, expected: "日本 語の\n文章"},
+		{name: "wide_run", text: "日本語の文章です", width: 6, expected: "日本語\nの文章\nです"},
+		{name: "wide_mixed", text: "see 日本語 text", width: 7, expected: "see 日\n本語\ntext"},
+		{name: "lines", text: "one two\nthree four", width: 9, expected: "one two\nthree\nfour"},
+		{name: "no_width", text: "one two three", width: 0, expected: "one two three"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, Wrap(test.text, test.width))
+		})
+	}
+}
+
+func Test_Converter_Parse_Links(t *testing.T) {
+	converter := New()
+	converter.Links = &links.Rewriter{
+		BaseURL:        "https://example.com/docs/",
+		MarkdownToHTML: true,
+	}
+
+	actual, err := converter.Parse([]byte("[setup](./setup.md)"))
+	assert.Nil(t, err)
+	assert.Equal(t, "setup (https://example.com/docs/setup.html)", string(actual))
+
+	converter.Links.BaseURL = "invalid"
+	_, err = converter.Parse([]byte("[setup](./setup.md)"))
+	assert.ErrorIs(t, err, links.ErrInvalidBaseURL)
+}
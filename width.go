@@ -0,0 +1,143 @@
+package markdownconverter
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// wideRanges are the ranges of East Asian wide and full width characters, and
+// emoji presented as pictures, that take two columns when displayed
+var wideRanges [][2]rune = [][2]rune{
+	{0x1100, 0x115F},
+	{0x231A, 0x231B},
+	{0x2329, 0x232A},
+	{0x23E9, 0x23EC},
+	{0x23F0, 0x23F0},
+	{0x23F3, 0x23F3},
+	{0x25FD, 0x25FE},
+	{0x2614, 0x2615},
+	{0x2648, 0x2653},
+	{0x267F, 0x267F},
+	{0x2693, 0x2693},
+	{0x26A1, 0x26A1},
+	{0x26AA, 0x26AB},
+	{0x26BD, 0x26BE},
+	{0x26C4, 0x26C5},
+	{0x26CE, 0x26CE},
+	{0x26D4, 0x26D4},
+	{0x26EA, 0x26EA},
+	{0x26F2, 0x26F3},
+	{0x26F5, 0x26F5},
+	{0x26FA, 0x26FA},
+	{0x26FD, 0x26FD},
+	{0x2705, 0x2705},
+	{0x270A, 0x270B},
+	{0x2728, 0x2728},
+	{0x274C, 0x274C},
+	{0x274E, 0x274E},
+	{0x2753, 0x2755},
+	{0x2757, 0x2757},
+	{0x2795, 0x2797},
+	{0x27B0, 0x27B0},
+	{0x27BF, 0x27BF},
+	{0x2B1B, 0x2B1C},
+	{0x2B50, 0x2B50},
+	{0x2B55, 0x2B55},
+	{0x2E80, 0x303E},
+	{0x3041, 0x33FF},
+	{0x3400, 0x4DBF},
+	{0x4E00, 0x9FFF},
+	{0xA000, 0xA4CF},
+	{0xA960, 0xA97F},
+	{0xAC00, 0xD7A3},
+	{0xF900, 0xFAFF},
+	{0xFE10, 0xFE19},
+	{0xFE30, 0xFE6F},
+	{0xFF00, 0xFF60},
+	{0xFFE0, 0xFFE6},
+	{0x16FE0, 0x16FE4},
+	{0x17000, 0x18CFF},
+	{0x1B000, 0x1B2FF},
+	{0x1F004, 0x1F004},
+	{0x1F0CF, 0x1F0CF},
+	{0x1F18E, 0x1F18E},
+	{0x1F191, 0x1F19A},
+	{0x1F200, 0x1F251},
+	{0x1F300, 0x1F64F},
+	{0x1F680, 0x1F6FF},
+	{0x1F7E0, 0x1F7EB},
+	{0x1F90C, 0x1F9FF},
+	{0x1FA70, 0x1FAFF},
+	{0x20000, 0x2FFFD},
+	{0x30000, 0x3FFFD},
+}
+
+// DisplayWidth returns the number of columns the text takes when displayed in
+// a monospaced font, counting wide characters as two columns and combining
+// marks and control characters as none
+func DisplayWidth(text string) int {
+	width := 0
+	for len(text) > 0 {
+		r, size := utf8.DecodeRuneInString(text)
+		text = text[size:]
+		width += RuneWidth(r)
+	}
+	return width
+}
+
+// RuneWidth returns the number of columns the rune takes when displayed in a
+// monospaced font
+func RuneWidth(r rune) int {
+	switch {
+	case r < 0x20, r >= 0x7F && r < 0xA0:
+		return 0
+	case r < 0x7F:
+		return 1
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf), r >= 0x1160 && r <= 0x11FF:
+		return 0
+	}
+
+	low, high := 0, len(wideRanges)-1
+	for low <= high {
+		middle := (low + high) / 2
+		switch {
+		case r < wideRanges[middle][0]:
+			high = middle - 1
+		case r > wideRanges[middle][1]:
+			low = middle + 1
+		default:
+			return 2
+		}
+	}
+	return 1
+}
+
+// AlignColumns returns the rows as lines of cells, with every cell but the last
+// in each row padded to the display width of the widest cell in its column
+// followed by the padding
+func AlignColumns(rows [][]string, padding int) string {
+	widths := make([]int, 0)
+	for _, row := range rows {
+		for index, cell := range row {
+			if index == len(widths) {
+				widths = append(widths, 0)
+			}
+			if width := DisplayWidth(cell); width > widths[index] {
+				widths[index] = width
+			}
+		}
+	}
+
+	builder := &strings.Builder{}
+	for _, row := range rows {
+		for index, cell := range row {
+			builder.WriteString(cell)
+			if index < len(row)-1 {
+				builder.WriteString(strings.Repeat(" ", widths[index]-DisplayWidth(cell)+padding))
+			}
+		}
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
@@ -0,0 +1,54 @@
+package markdownconverter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DisplayWidth(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		text     string
+		expected int
+	}{
+		{name: "empty", text: "", expected: 0},
+		{name: "ascii", text: "Heading", expected: 7},
+		{name: "accented", text: "Café", expected: 4},
+		{name: "combining", text: "Cafe\u0301", expected: 4},
+		{name: "chinese", text: "中文", expected: 4},
+		{name: "japanese", text: "日本語 text", expected: 11},
+		{name: "korean", text: "한국어", expected: 6},
+		{name: "full_width", text: "ＡＢ", expected: 4},
+		{name: "emoji", text: "🚀 launch", expected: 9},
+		{name: "zero_width_joiner", text: "a\u200db", expected: 2},
+		{name: "control", text: "a\tb", expected: 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, DisplayWidth(test.text))
+		})
+	}
+}
+
+func Test_AlignColumns(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		rows     [][]string
+		expected string
+	}{
+		{name: "empty", rows: nil, expected: ""},
+		{name: "ascii", rows: [][]string{{"Name", "Value"}, {"one", "1"}}, expected: "Name  Value\none   1\n"},
+		{name: "wide", rows: [][]string{{"名前", "Value"}, {"one", "1"}}, expected: "名前  Value\none   1\n"},
+		{name: "short_row", rows: [][]string{{"a", "b", "c"}, {"long"}}, expected: "a     b  c\nlong\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, AlignColumns(test.rows, 2))
+		})
+	}
+}